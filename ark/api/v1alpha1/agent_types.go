@@ -25,9 +25,25 @@ type ToolPartial struct {
 	Parameters []ToolFunction `json:"parameters,omitempty"`
 }
 
+// AgentToolMCPServer selects the tools generated from an MCPServer
+type AgentToolMCPServer struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name of the MCPServer in the agent's namespace
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// Include restricts the attached tools to these names (optional). Matches either the
+	// Tool resource name or the tool name reported by the MCP server.
+	Include []string `json:"include,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Exclude removes these tools from the attached set (optional). Matches either the
+	// Tool resource name or the tool name reported by the MCP server.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
 type AgentTool struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=built-in;custom
+	// +kubebuilder:validation:Enum=built-in;custom;mcp;selector;toolset
 	Type string `json:"type"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
@@ -42,6 +58,16 @@ type AgentTool struct {
 	// from the agent. Parameters defined here are injected at runtime and are not visible or
 	// editable by the agent itself.
 	Partial *ToolPartial `json:"partial,omitempty"`
+	// +kubebuilder:validation:Optional
+	// MCPServer attaches every tool generated from the referenced MCPServer. Used with type 'mcp'.
+	MCPServer *AgentToolMCPServer `json:"mcpServer,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Selector attaches every Tool in the agent's namespace matching the labels. Used with type 'selector'.
+	// An empty selector matches no tools.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ToolSet attaches every tool in the referenced ToolSet. Used with type 'toolset'.
+	ToolSet *ToolSetRef `json:"toolSet,omitempty"`
 }
 
 // GetToolCRDName returns the actual Tool CRD name to lookup in Kubernetes.
@@ -90,12 +116,18 @@ type AgentSpec struct {
 	// JSON schema for structured output format
 	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Generation overrides the generation parameters of the model for this agent
+	Generation *GenerationParameters `json:"generation,omitempty"`
+	// +kubebuilder:validation:Optional
 	Overrides []Override `json:"overrides,omitempty"`
 }
 
 type AgentStatus struct {
 	// Conditions represent the latest available observations of an agent's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tools lists the effective tool names after resolving MCP server and selector references
+	Tools []string `json:"tools,omitempty"`
 }
 
 // +kubebuilder:object:root=true
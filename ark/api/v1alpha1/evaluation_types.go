@@ -228,6 +228,11 @@ type EvaluationStatus struct {
 	// +kubebuilder:validation:Optional
 	TokenUsage *TokenUsage `json:"tokenUsage,omitempty"`
 	// +kubebuilder:validation:Optional
+	// ModelUsage breaks the token usage of the evaluator down by model
+	ModelUsage []ModelUsage `json:"modelUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	Cost *Cost `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
 	// +kubebuilder:validation:Optional
 	// Batch evaluation progress (only set for batch type evaluations)
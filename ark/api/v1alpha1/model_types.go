@@ -14,6 +14,12 @@ type ModelConfig struct {
 	Azure *AzureModelConfig `json:"azure,omitempty"`
 	// +kubebuilder:validation:Optional
 	Bedrock *BedrockModelConfig `json:"bedrock,omitempty"`
+	// +kubebuilder:validation:Optional
+	Anthropic *AnthropicModelConfig `json:"anthropic,omitempty"`
+	// +kubebuilder:validation:Optional
+	Gemini *GeminiModelConfig `json:"gemini,omitempty"`
+	// +kubebuilder:validation:Optional
+	Router *RouterModelConfig `json:"router,omitempty"`
 }
 
 // AzureModelConfig contains Azure OpenAI specific parameters
@@ -24,6 +30,10 @@ type AzureModelConfig struct {
 	APIKey ValueSource `json:"apiKey"`
 	// +kubebuilder:validation:Optional
 	APIVersion *ValueSource `json:"apiVersion,omitempty"`
+	// API is chat for Chat Completions on the deployment (the default) or responses for the Responses API
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=chat;responses
+	API string `json:"api,omitempty"`
 	// +kubebuilder:validation:Optional
 	Headers []Header `json:"headers,omitempty"`
 	// +kubebuilder:validation:Optional
@@ -36,6 +46,11 @@ type OpenAIModelConfig struct {
 	BaseURL ValueSource `json:"baseUrl"`
 	// +kubebuilder:validation:Required
 	APIKey ValueSource `json:"apiKey"`
+	// API selects the OpenAI API used for completions, chat for Chat Completions or responses
+	// for the Responses API, which newer reasoning models and reasoning summaries require
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=chat;responses
+	API string `json:"api,omitempty"`
 	// +kubebuilder:validation:Optional
 	Headers []Header `json:"headers,omitempty"`
 	// +kubebuilder:validation:Optional
@@ -67,30 +82,258 @@ type BedrockModelConfig struct {
 	Properties map[string]ValueSource `json:"properties,omitempty"`
 }
 
-type ModelSpec struct {
+// AnthropicModelConfig contains Anthropic Messages API specific parameters
+type AnthropicModelConfig struct {
 	// +kubebuilder:validation:Required
+	BaseURL ValueSource `json:"baseUrl"`
+	// +kubebuilder:validation:Required
+	APIKey ValueSource `json:"apiKey"`
+	// Version is sent as the anthropic-version header, defaults to 2023-06-01
+	// +kubebuilder:validation:Optional
+	Version *ValueSource `json:"version,omitempty"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// GeminiModelConfig contains Google Gemini specific parameters. An API key calls the Gemini API,
+// a service account calls Vertex AI in the configured project and location.
+type GeminiModelConfig struct {
+	// BaseURL defaults to the Gemini API, or to the Vertex AI endpoint of the location
+	// +kubebuilder:validation:Optional
+	BaseURL *ValueSource `json:"baseUrl,omitempty"`
+	// +kubebuilder:validation:Optional
+	APIKey *ValueSource `json:"apiKey,omitempty"`
+	// ServiceAccount is a service account JSON key, usually read from a Secret
+	// +kubebuilder:validation:Optional
+	ServiceAccount *ValueSource `json:"serviceAccount,omitempty"`
+	// Project is the Vertex AI project, defaults to the project of the service account
+	// +kubebuilder:validation:Optional
+	Project *ValueSource `json:"project,omitempty"`
+	// Location is the Vertex AI location, defaults to global
+	// +kubebuilder:validation:Optional
+	Location *ValueSource `json:"location,omitempty"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// RouterModelConfig makes the Model a router, which sends every call to the Model of one of its
+// routes. A route is used only for the calls that match its conditions.
+type RouterModelConfig struct {
+	// Strategy chooses among the routes that match a call, weighted picks at random in proportion to
+	// the route weights and latency picks the route whose model answered fastest recently
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=weighted;latency
+	// +kubebuilder:default=weighted
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Routes []ModelRoute `json:"routes"`
+}
+
+// ModelRoute is a Model a router can call and the conditions of the calls it is used for
+type ModelRoute struct {
+	// Model is the name of a Model in the namespace of the router
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+	// Weight is the share of calls the route gets with the weighted strategy
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	Weight *int32 `json:"weight,omitempty"`
+	// MinPromptTokens limits the route to calls with at least this estimated prompt size
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinPromptTokens *int64 `json:"minPromptTokens,omitempty"`
+	// MaxPromptTokens limits the route to calls with at most this estimated prompt size
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxPromptTokens *int64 `json:"maxPromptTokens,omitempty"`
+	// Capabilities lists what the model supports, defaulting to the capabilities detected for the
+	// Model. Calls with tools, images or an output schema use only routes that support them.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=toolCalling;structuredOutput;streaming;vision
+	Capabilities []string `json:"capabilities,omitempty"`
+	// MaxCost limits the route to calls with an estimated cost up to this amount, in the currency
+	// of the pricing of the Model. Models without pricing are not limited.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	MaxCost string `json:"maxCost,omitempty"`
+	// Fallbacks are Models in the namespace of the router that are called in order when the call
+	// to the route fails
+	// +kubebuilder:validation:Optional
+	Fallbacks []string `json:"fallbacks,omitempty"`
+}
+
+type EmbeddingParameters struct {
+	// Dimensions requests embeddings of this size, from models that can shorten their embeddings
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Dimensions *int64 `json:"dimensions,omitempty"`
+	// BatchSize is the most texts sent in one request, calls with more texts make several requests
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2048
+	// +kubebuilder:default=100
+	BatchSize *int32 `json:"batchSize,omitempty"`
+}
+
+type ModelSpec struct {
+	// Model is the name of the model at the provider, and is not used by routers
+	// +kubebuilder:validation:Optional
 	Model ValueSource `json:"model"`
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=openai;azure;bedrock
+	// +kubebuilder:validation:Enum=openai;azure;bedrock;anthropic;gemini;router
 	Type string `json:"type,omitempty"`
+	// Task is completion for models agents call, or embedding for models that turn text into vectors
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=completion;embedding
+	// +kubebuilder:default=completion
+	Task string `json:"task,omitempty"`
 	// +kubebuilder:validation:Required
 	Config ModelConfig `json:"config"`
 	// +kubebuilder:validation:Optional
+	// Embedding configures the calls to embedding models
+	Embedding *EmbeddingParameters `json:"embedding,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Generation sets typed generation parameters, which take precedence over provider properties
+	Generation *GenerationParameters `json:"generation,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Limits caps the request rate, token rate and concurrency of calls to the model
+	Limits *ModelLimits `json:"limits,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Pricing is used to report the cost of queries and evaluations
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Probe configures the availability check run on every poll interval
+	Probe *ModelProbe `json:"probe,omitempty"`
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="1m"
 	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
 }
 
+// ModelProbe configures how the controller checks that a model is available and whether it
+// detects the capabilities of the model
+type ModelProbe struct {
+	// Mode is completion for a chat completion of a single token, listModels to query the models
+	// endpoint of the provider without generating tokens, or disabled to skip the check
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=completion;listModels;disabled
+	// +kubebuilder:default=completion
+	Mode string `json:"mode,omitempty"`
+	// DetectCapabilities tests tool calling, structured output, streaming and vision support once
+	// for every change of the spec and records the result in the status
+	// +kubebuilder:validation:Optional
+	DetectCapabilities bool `json:"detectCapabilities,omitempty"`
+}
+
+// GenerationParameters are generation settings that every model type maps to its own request
+// parameters. Decimal values are strings as CRDs do not support floating point fields.
+type GenerationParameters struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	// Temperature between 0 and 2
+	Temperature *string `json:"temperature,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	// TopP between 0 and 1
+	TopP *string `json:"topP,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	Stop []string `json:"stop,omitempty"`
+	// +kubebuilder:validation:Optional
+	Seed *int64 `json:"seed,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=minimal;low;medium;high
+	ReasoningEffort string `json:"reasoningEffort,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^-?\d+(\.\d+)?$
+	// PresencePenalty between -2 and 2
+	PresencePenalty *string `json:"presencePenalty,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^-?\d+(\.\d+)?$
+	// FrequencyPenalty between -2 and 2
+	FrequencyPenalty *string `json:"frequencyPenalty,omitempty"`
+}
+
+// ModelLimits are enforced across all queries of the controller. Calls that would exceed a limit
+// wait until capacity is available, waiting calls are served in turn across namespaces.
+type ModelLimits struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerMinute *int32 `json:"requestsPerMinute,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TokensPerMinute limits estimated tokens, which are corrected with the reported usage after each call
+	TokensPerMinute *int64 `json:"tokensPerMinute,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
+}
+
+// ModelPricing is the price per million tokens, as decimal strings
+type ModelPricing struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	InputPerMillion string `json:"inputPerMillion"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	OutputPerMillion string `json:"outputPerMillion"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	// CachedInputPerMillion is the price of prompt tokens read from the provider cache, defaults to the input price
+	CachedInputPerMillion string `json:"cachedInputPerMillion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=USD
+	// +kubebuilder:validation:Pattern=^[A-Z][A-Z][A-Z]$
+	Currency string `json:"currency,omitempty"`
+}
+
 type ModelStatus struct {
 	// +kubebuilder:validation:Optional
 	// ResolvedAddress contains the actual resolved base URL value
 	ResolvedAddress string `json:"resolvedAddress,omitempty"`
 	// Conditions represent the latest available observations of a model's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Capabilities are detected when spec.probe.detectCapabilities is set
+	Capabilities *ModelCapabilities `json:"capabilities,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Embedding describes the embeddings of embedding models, as observed by the completion probe
+	Embedding *ModelEmbeddingStatus `json:"embedding,omitempty"`
+}
+
+type ModelEmbeddingStatus struct {
+	// Dimensions is the size of the embeddings the model returns
+	Dimensions int64 `json:"dimensions,omitempty"`
+}
+
+// ModelCapabilities records the features a model was found to support. Capabilities that could
+// not be determined, for example because the test call timed out, are left unset.
+type ModelCapabilities struct {
+	// +kubebuilder:validation:Optional
+	ToolCalling *bool `json:"toolCalling,omitempty"`
+	// +kubebuilder:validation:Optional
+	StructuredOutput *bool `json:"structuredOutput,omitempty"`
+	// +kubebuilder:validation:Optional
+	Streaming *bool `json:"streaming,omitempty"`
+	// +kubebuilder:validation:Optional
+	Vision *bool `json:"vision,omitempty"`
+	// ObservedGeneration is the generation of the Model the capabilities were detected for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Task",type=string,JSONPath=`.spec.task`
 // +kubebuilder:printcolumn:name="Model",type=string,JSONPath=`.spec.model.value`
 // +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="ModelAvailable")].status`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
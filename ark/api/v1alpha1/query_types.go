@@ -5,6 +5,8 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/openai/openai-go"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -79,6 +81,45 @@ type QuerySpec struct {
 	Cancel bool `json:"cancel,omitempty"`
 	// +kubebuilder:validation:Optional
 	Overrides []Override `json:"overrides,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Restricts the tools available to agents during this query, including agents reached through
+	// team members, agent tools and team tools
+	ToolPolicy *QueryToolPolicy `json:"toolPolicy,omitempty"`
+}
+
+// QueryToolPolicy hides tools from agents for a single query. When allow is set, only matching
+// tools are available. Tools matching any deny entry are never available.
+type QueryToolPolicy struct {
+	// +kubebuilder:validation:Optional
+	Allow []QueryToolMatcher `json:"allow,omitempty"`
+	// +kubebuilder:validation:Optional
+	Deny []QueryToolMatcher `json:"deny,omitempty"`
+}
+
+// QueryToolMatcher matches tools on every field that is set
+type QueryToolMatcher struct {
+	// +kubebuilder:validation:Optional
+	// Tool name as exposed to the agent or the Tool resource name. Supports glob patterns such as "github-*"
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;mcp;agent;team;builtin
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tool annotation hints that must have the given values
+	Annotations *ToolAnnotationHints `json:"annotations,omitempty"`
+}
+
+// ToolAnnotationHints selects tools by their annotation hints. Tools without annotations use the
+// MCP defaults: not read-only, destructive, not idempotent and open world.
+type ToolAnnotationHints struct {
+	// +kubebuilder:validation:Optional
+	ReadOnlyHint *bool `json:"readOnlyHint,omitempty"`
+	// +kubebuilder:validation:Optional
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	// +kubebuilder:validation:Optional
+	IdempotentHint *bool `json:"idempotentHint,omitempty"`
+	// +kubebuilder:validation:Optional
+	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
 }
 
 // A2AMetadata contains optional A2A protocol metadata
@@ -100,6 +141,36 @@ type Response struct {
 	// +kubebuilder:validation:Optional
 	// A2A contains optional A2A protocol metadata (contextId, taskId)
 	A2A *A2AMetadata `json:"a2a,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Why a team target stopped before reaching its natural end, such as a termination condition
+	TerminationReason string `json:"terminationReason,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Vote contains the candidates and the winner when the target is a team using the vote strategy
+	Vote *VoteResult `json:"vote,omitempty"`
+}
+
+// VoteResult is the outcome of a vote between team members
+type VoteResult struct {
+	// Method used to pick the winner, either "majority" or "judge"
+	Method string `json:"method"`
+	// Member whose response was chosen
+	Winner string `json:"winner"`
+	// Every member that took part in the vote, in team order
+	Candidates []VoteCandidate `json:"candidates"`
+}
+
+// VoteCandidate is the score of one member in a vote
+type VoteCandidate struct {
+	Member string `json:"member"`
+	// Number of members that gave the same answer for the majority method, or the judge's score
+	// from 0 to 10
+	Score int `json:"score"`
+	// +kubebuilder:validation:Optional
+	// Reason the judge gave for the score
+	Reason string `json:"reason,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Error of a member that failed to answer. Failed members cannot win
+	Error string `json:"error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -121,6 +192,43 @@ type TokenUsage struct {
 	PromptTokens     int64 `json:"promptTokens,omitempty"`
 	CompletionTokens int64 `json:"completionTokens,omitempty"`
 	TotalTokens      int64 `json:"totalTokens,omitempty"`
+	// CachedPromptTokens are the prompt tokens read from the provider cache, included in PromptTokens
+	CachedPromptTokens int64 `json:"cachedPromptTokens,omitempty"`
+	// ReasoningTokens are the completion tokens a reasoning model used before answering, included in CompletionTokens
+	ReasoningTokens int64 `json:"reasoningTokens,omitempty"`
+}
+
+// ModelUsage is the token usage and cost of the calls to one Model
+type ModelUsage struct {
+	Model      string `json:"model"`
+	Namespace  string `json:"namespace,omitempty"`
+	TokenUsage `json:",inline"`
+	// +kubebuilder:validation:Optional
+	// Cost is set when the model has pricing
+	Cost *Cost `json:"cost,omitempty"`
+}
+
+// Cost is an amount of money as a decimal string
+type Cost struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewCost returns the amount rounded to a millionth, the smallest price per token of a price per million tokens
+func NewCost(amount float64, currency string) *Cost {
+	return &Cost{
+		Amount:   strconv.FormatFloat(math.Round(amount*1e6)/1e6, 'f', -1, 64),
+		Currency: currency,
+	}
+}
+
+// Value returns the amount as a number, or zero if it is not one
+func (c *Cost) Value() float64 {
+	if c == nil {
+		return 0
+	}
+	value, _ := strconv.ParseFloat(c.Amount, 64)
+	return value
 }
 
 type QueryStatus struct {
@@ -133,6 +241,12 @@ type QueryStatus struct {
 	Responses  []Response         `json:"responses,omitempty"`
 	TokenUsage TokenUsage         `json:"tokenUsage,omitempty"`
 	// +kubebuilder:validation:Optional
+	// ModelUsage breaks the token usage down by model
+	ModelUsage []ModelUsage `json:"modelUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost is the total cost of the models with pricing. It is not set when they use different currencies.
+	Cost *Cost `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
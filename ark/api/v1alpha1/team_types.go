@@ -9,22 +9,102 @@ import (
 type TeamMember struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// Conversation history this member receives. Overrides the team context
+	Context *TeamContextSpec `json:"context,omitempty"`
+}
+
+// TeamContextSpec controls how much of the team conversation a member sees. The member always
+// receives the original user input.
+type TeamContextSpec struct {
+	// "full" (default) passes the whole conversation, "lastMessages" only the most recent messages,
+	// "finalOutputs" the conversation without tool calls and tool results, and "summary" a summary
+	// of the conversation written by a model
+	// +kubebuilder:validation:Enum=full;lastMessages;finalOutputs;summary
+	Mode string `json:"mode,omitempty"`
+	// Number of most recent messages kept by the lastMessages mode
+	// +kubebuilder:validation:Minimum=1
+	LastMessages int `json:"lastMessages,omitempty"`
+	// Model that writes the summary in the summary mode. Defaults to the model named "default"
+	ModelRef *AgentModelRef `json:"modelRef,omitempty"`
 }
 
 type TeamSelectorSpec struct {
 	Agent          string `json:"agent,omitempty"`
 	SelectorPrompt string `json:"selectorPrompt,omitempty"`
+	// What to do when the selector agent keeps choosing a member that is not a valid candidate:
+	// "fallback" (default) picks the first candidate, "fail" fails the query and "terminate" ends
+	// the team with the responses so far
+	// +kubebuilder:validation:Enum=fail;fallback;terminate
+	InvalidSelectionPolicy string `json:"invalidSelectionPolicy,omitempty"`
 }
 
 type TeamGraphEdge struct {
 	From string `json:"from"`
 	To   string `json:"to"`
+	// Condition evaluated against the last message of the 'from' member. Edges without a
+	// condition always match
+	Condition *TeamGraphEdgeCondition `json:"condition,omitempty"`
+	// Edges from the same member are evaluated in descending priority, then in declaration order
+	Priority int `json:"priority,omitempty"`
+	// Fallback edge taken when no other edge from the same member matches
+	Default bool `json:"default,omitempty"`
+}
+
+// TeamGraphEdgeCondition matches the last message of a member. Exactly one field must be set.
+type TeamGraphEdgeCondition struct {
+	// Regular expression matched against the message content
+	Regex string `json:"regex,omitempty"`
+	// jq expression evaluated against the message content parsed as JSON. Matches when the first
+	// result is neither false nor null
+	JQ string `json:"jq,omitempty"`
+	// Case-insensitive substring of the message content
+	Keyword string `json:"keyword,omitempty"`
 }
 
 type TeamGraphSpec struct {
 	Edges []TeamGraphEdge `json:"edges"`
 }
 
+// TeamParallelSpec configures the parallel strategy, which runs members concurrently on the same
+// input, each with its own copy of the conversation history.
+type TeamParallelSpec struct {
+	// Members to run. Defaults to all team members
+	Members []string `json:"members,omitempty"`
+	// Agent that receives all member outputs and writes the final response. When empty, member
+	// outputs are concatenated with attribution
+	Aggregator string `json:"aggregator,omitempty"`
+	// Either "fail" (default), which cancels the remaining members when one fails, or "continue",
+	// which uses the outputs of the members that succeeded
+	// +kubebuilder:validation:Enum=fail;continue
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// TeamPlanSpec configures the plan strategy. The planner member writes a plan of steps assigned to
+// the other members, and the team runs the steps in dependency order, independent steps in parallel.
+type TeamPlanSpec struct {
+	// Member that writes the plan. It does not run plan steps itself
+	// +kubebuilder:validation:MinLength=1
+	Planner string `json:"planner"`
+	// How often the planner may revise the plan after a step fails. With 0 a failed step fails the team
+	// +kubebuilder:validation:Minimum=0
+	MaxReplans int `json:"maxReplans,omitempty"`
+}
+
+// TeamVoteSpec configures the vote strategy, which runs members concurrently on the same input and
+// returns the response of the winning member.
+type TeamVoteSpec struct {
+	// Members that answer. Defaults to all team members
+	Members []string `json:"members,omitempty"`
+	// Either "majority" (default), which picks the most common answer after normalizing case,
+	// whitespace and trailing punctuation, or "judge", which asks the judge agent to score each answer
+	// +kubebuilder:validation:Enum=majority;judge
+	Method string `json:"method,omitempty"`
+	// Agent that scores the answers for the judge method. It is not a team member
+	Judge string `json:"judge,omitempty"`
+	// Criteria the judge scores the answers against
+	Rubric string `json:"rubric,omitempty"`
+}
+
 type TeamSpec struct {
 	Members     []TeamMember      `json:"members"`
 	Strategy    string            `json:"strategy"`
@@ -32,6 +112,29 @@ type TeamSpec struct {
 	MaxTurns    *int              `json:"maxTurns,omitempty"`
 	Selector    *TeamSelectorSpec `json:"selector,omitempty"`
 	Graph       *TeamGraphSpec    `json:"graph,omitempty"`
+	Parallel    *TeamParallelSpec `json:"parallel,omitempty"`
+	Plan        *TeamPlanSpec     `json:"plan,omitempty"`
+	Vote        *TeamVoteSpec     `json:"vote,omitempty"`
+	// Default conversation history for members without their own context
+	Context *TeamContextSpec `json:"context,omitempty"`
+	// Conditions that end the team early. They are checked after every member turn
+	Termination *TeamTerminationSpec `json:"termination,omitempty"`
+}
+
+// TeamTerminationSpec ends a team as soon as any of its conditions is met. The team completes
+// successfully with the responses so far.
+type TeamTerminationSpec struct {
+	// Regular expression matched against the last message of a member
+	Regex string `json:"regex,omitempty"`
+	// Case-insensitive substring of the last message of a member
+	Keyword string `json:"keyword,omitempty"`
+	// Total tokens the team may use across all members
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens int64 `json:"maxTokens,omitempty"`
+	// Wall-clock time the team may run for (e.g., "30s", "5m")
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// Stop when a member responds with the same content as in its previous turn
+	NoProgress bool `json:"noProgress,omitempty"`
 }
 
 type TeamStatus struct {
@@ -108,6 +108,37 @@ type ToolSpec struct {
 	// This field is required only if Type = "builtin".
 	// +kubebuilder:validation:Optional
 	Builtin *BuiltinToolRef `json:"builtin,omitempty"`
+	// Steps applied in order to the tool output before it is returned to the caller
+	// +kubebuilder:validation:Optional
+	PostProcess []ToolOutputStep `json:"postProcess,omitempty"`
+}
+
+// ToolOutputStep is a single step of a tool output post-processing pipeline.
+// Each step receives the output of the previous step.
+type ToolOutputStep struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=jq;template;regex;table;redact
+	Type string `json:"type"`
+	// jq expression, Go template or regular expression, depending on the step type.
+	// Templates receive the parsed JSON output as .output and the raw text as .raw.
+	// For redact steps, text matching the expression is replaced
+	// +kubebuilder:validation:Optional
+	Expression string `json:"expression,omitempty"`
+	// Capture group returned by regex steps. Defaults to the whole match
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Group int `json:"group,omitempty"`
+	// Output format of table steps
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=markdown;csv
+	// +kubebuilder:default="markdown"
+	Format string `json:"format,omitempty"`
+	// JSON object keys whose values are replaced by redact steps, at any depth
+	// +kubebuilder:validation:Optional
+	Fields []string `json:"fields,omitempty"`
+	// Replacement text for redact steps. Defaults to [REDACTED]
+	// +kubebuilder:validation:Optional
+	Replacement string `json:"replacement,omitempty"`
 }
 
 type HTTPSpec struct {
@@ -126,6 +157,33 @@ type HTTPSpec struct {
 	// +kubebuilder:validation:Optional
 	// Parameters for body template processing
 	BodyParameters []Parameter `json:"bodyParameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Async enables polling for APIs that accept a request and complete it later
+	Async *HTTPAsyncSpec `json:"async,omitempty"`
+}
+
+// HTTPAsyncSpec configures polling of a status URL for long-running HTTP operations.
+// Polling starts when the initial response is a 202 with a Location header, or when
+// statusURLPath yields a URL, and is bounded by the query timeout.
+type HTTPAsyncSpec struct {
+	// +kubebuilder:validation:Optional
+	// jq expression extracting the status URL from the initial response body. Falls back to the Location header of a 202 response
+	StatusURLPath string `json:"statusURLPath,omitempty"`
+	// +kubebuilder:validation:Optional
+	// jq expression evaluated against each status response; polling completes when it is true.
+	// Defaults to the first status response that is not a 202
+	SuccessCondition string `json:"successCondition,omitempty"`
+	// +kubebuilder:validation:Optional
+	// jq expression evaluated against each status response; the tool fails when it is true
+	FailureCondition string `json:"failureCondition,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="2s"
+	// Interval before the first poll, doubled after each attempt
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// Upper bound for the interval between polls
+	MaxPollInterval *metav1.Duration `json:"maxPollInterval,omitempty"`
 }
 
 // Tool type constants
@@ -205,6 +263,13 @@ func (in *ToolSpec) DeepCopyInto(out *ToolSpec) {
 		*out = new(BuiltinToolRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PostProcess != nil {
+		in, out := &in.PostProcess, &out.PostProcess
+		*out = make([]ToolOutputStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 func (in *MCPServerRef) DeepCopyInto(out *MCPServerRef) {
@@ -237,4 +302,9 @@ func (in *HTTPSpec) DeepCopyInto(out *HTTPSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Async != nil {
+		in, out := &in.Async, &out.Async
+		*out = new(HTTPAsyncSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
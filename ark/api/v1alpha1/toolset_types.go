@@ -0,0 +1,50 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ToolSetRef references a ToolSet in the agent's namespace
+type ToolSetRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ToolSetSpec defines a reusable group of agent tools
+type ToolSetSpec struct {
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Tools in the set, using the same form as agent tools including partials and function filters.
+	// ToolSets cannot reference other ToolSets.
+	Tools []AgentTool `json:"tools"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Description",type="string",JSONPath=".spec.description"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ToolSet is the Schema for the toolsets API.
+type ToolSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ToolSetSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ToolSetList contains a list of ToolSet.
+type ToolSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ToolSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ToolSet{}, &ToolSetList{})
+}
@@ -398,6 +398,11 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(GenerationParameters)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Overrides != nil {
 		in, out := &in.Overrides, &out.Overrides
 		*out = make([]Override, len(*in))
@@ -427,6 +432,11 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentStatus.
@@ -452,6 +462,21 @@ func (in *AgentTool) DeepCopyInto(out *AgentTool) {
 		*out = new(ToolPartial)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MCPServer != nil {
+		in, out := &in.MCPServer, &out.MCPServer
+		*out = new(AgentToolMCPServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToolSet != nil {
+		in, out := &in.ToolSet, &out.ToolSet
+		*out = new(ToolSetRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTool.
@@ -464,6 +489,31 @@ func (in *AgentTool) DeepCopy() *AgentTool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentToolMCPServer) DeepCopyInto(out *AgentToolMCPServer) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentToolMCPServer.
+func (in *AgentToolMCPServer) DeepCopy() *AgentToolMCPServer {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentToolMCPServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentToolRef) DeepCopyInto(out *AgentToolRef) {
 	*out = *in
@@ -479,6 +529,42 @@ func (in *AgentToolRef) DeepCopy() *AgentToolRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnthropicModelConfig) DeepCopyInto(out *AnthropicModelConfig) {
+	*out = *in
+	in.BaseURL.DeepCopyInto(&out.BaseURL)
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnthropicModelConfig.
+func (in *AnthropicModelConfig) DeepCopy() *AnthropicModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AnthropicModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureModelConfig) DeepCopyInto(out *AzureModelConfig) {
 	*out = *in
@@ -730,6 +816,21 @@ func (in *ChildEvaluationStatus) DeepCopy() *ChildEvaluationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cost) DeepCopyInto(out *Cost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cost.
+func (in *Cost) DeepCopy() *Cost {
+	if in == nil {
+		return nil
+	}
+	out := new(Cost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectEvaluationConfig) DeepCopyInto(out *DirectEvaluationConfig) {
 	*out = *in
@@ -745,6 +846,31 @@ func (in *DirectEvaluationConfig) DeepCopy() *DirectEvaluationConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddingParameters) DeepCopyInto(out *EmbeddingParameters) {
+	*out = *in
+	if in.Dimensions != nil {
+		in, out := &in.Dimensions, &out.Dimensions
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddingParameters.
+func (in *EmbeddingParameters) DeepCopy() *EmbeddingParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddingParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Evaluation) DeepCopyInto(out *Evaluation) {
 	*out = *in
@@ -916,6 +1042,18 @@ func (in *EvaluationStatus) DeepCopyInto(out *EvaluationStatus) {
 		*out = new(TokenUsage)
 		**out = **in
 	}
+	if in.ModelUsage != nil {
+		in, out := &in.ModelUsage, &out.ModelUsage
+		*out = make([]ModelUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(Cost)
+		**out = **in
+	}
 	if in.Duration != nil {
 		in, out := &in.Duration, &out.Duration
 		*out = new(v1.Duration)
@@ -1097,6 +1235,135 @@ func (in *ExpressionRule) DeepCopy() *ExpressionRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeminiModelConfig) DeepCopyInto(out *GeminiModelConfig) {
+	*out = *in
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeminiModelConfig.
+func (in *GeminiModelConfig) DeepCopy() *GeminiModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GeminiModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerationParameters) DeepCopyInto(out *GenerationParameters) {
+	*out = *in
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(string)
+		**out = **in
+	}
+	if in.TopP != nil {
+		in, out := &in.TopP, &out.TopP
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Stop != nil {
+		in, out := &in.Stop, &out.Stop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PresencePenalty != nil {
+		in, out := &in.PresencePenalty, &out.PresencePenalty
+		*out = new(string)
+		**out = **in
+	}
+	if in.FrequencyPenalty != nil {
+		in, out := &in.FrequencyPenalty, &out.FrequencyPenalty
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerationParameters.
+func (in *GenerationParameters) DeepCopy() *GenerationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPAsyncSpec) DeepCopyInto(out *HTTPAsyncSpec) {
+	*out = *in
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxPollInterval != nil {
+		in, out := &in.MaxPollInterval, &out.MaxPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAsyncSpec.
+func (in *HTTPAsyncSpec) DeepCopy() *HTTPAsyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPAsyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSpec.
 func (in *HTTPSpec) DeepCopy() *HTTPSpec {
 	if in == nil {
@@ -1434,6 +1701,41 @@ func (in *Model) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelCapabilities) DeepCopyInto(out *ModelCapabilities) {
+	*out = *in
+	if in.ToolCalling != nil {
+		in, out := &in.ToolCalling, &out.ToolCalling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StructuredOutput != nil {
+		in, out := &in.StructuredOutput, &out.StructuredOutput
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Vision != nil {
+		in, out := &in.Vision, &out.Vision
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelCapabilities.
+func (in *ModelCapabilities) DeepCopy() *ModelCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
 	*out = *in
@@ -1452,6 +1754,21 @@ func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
 		*out = new(BedrockModelConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Anthropic != nil {
+		in, out := &in.Anthropic, &out.Anthropic
+		*out = new(AnthropicModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gemini != nil {
+		in, out := &in.Gemini, &out.Gemini
+		*out = new(GeminiModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Router != nil {
+		in, out := &in.Router, &out.Router
+		*out = new(RouterModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
@@ -1464,6 +1781,51 @@ func (in *ModelConfig) DeepCopy() *ModelConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelEmbeddingStatus) DeepCopyInto(out *ModelEmbeddingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelEmbeddingStatus.
+func (in *ModelEmbeddingStatus) DeepCopy() *ModelEmbeddingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelEmbeddingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelLimits) DeepCopyInto(out *ModelLimits) {
+	*out = *in
+	if in.RequestsPerMinute != nil {
+		in, out := &in.RequestsPerMinute, &out.RequestsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TokensPerMinute != nil {
+		in, out := &in.TokensPerMinute, &out.TokensPerMinute
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelLimits.
+func (in *ModelLimits) DeepCopy() *ModelLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelList) DeepCopyInto(out *ModelList) {
 	*out = *in
@@ -1497,37 +1859,142 @@ func (in *ModelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+func (in *ModelPricing) DeepCopyInto(out *ModelPricing) {
 	*out = *in
-	in.Model.DeepCopyInto(&out.Model)
-	in.Config.DeepCopyInto(&out.Config)
-	if in.PollInterval != nil {
-		in, out := &in.PollInterval, &out.PollInterval
-		*out = new(v1.Duration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
-func (in *ModelSpec) DeepCopy() *ModelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPricing.
+func (in *ModelPricing) DeepCopy() *ModelPricing {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelSpec)
+	out := new(ModelPricing)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+func (in *ModelProbe) DeepCopyInto(out *ModelProbe) {
 	*out = *in
-	if in.Conditions != nil {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelProbe.
+func (in *ModelProbe) DeepCopy() *ModelProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRoute) DeepCopyInto(out *ModelRoute) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinPromptTokens != nil {
+		in, out := &in.MinPromptTokens, &out.MinPromptTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPromptTokens != nil {
+		in, out := &in.MaxPromptTokens, &out.MaxPromptTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRoute.
+func (in *ModelRoute) DeepCopy() *ModelRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+	*out = *in
+	in.Model.DeepCopyInto(&out.Model)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Embedding != nil {
+		in, out := &in.Embedding, &out.Embedding
+		*out = new(EmbeddingParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(GenerationParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(ModelLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pricing != nil {
+		in, out := &in.Pricing, &out.Pricing
+		*out = new(ModelPricing)
+		**out = **in
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(ModelProbe)
+		**out = **in
+	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
+func (in *ModelSpec) DeepCopy() *ModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+	*out = *in
+	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ModelCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Embedding != nil {
+		in, out := &in.Embedding, &out.Embedding
+		*out = new(ModelEmbeddingStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
@@ -1540,6 +2007,27 @@ func (in *ModelStatus) DeepCopy() *ModelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelUsage) DeepCopyInto(out *ModelUsage) {
+	*out = *in
+	out.TokenUsage = in.TokenUsage
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(Cost)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelUsage.
+func (in *ModelUsage) DeepCopy() *ModelUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAIModelConfig) DeepCopyInto(out *OpenAIModelConfig) {
 	*out = *in
@@ -1799,6 +2287,11 @@ func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ToolPolicy != nil {
+		in, out := &in.ToolPolicy, &out.ToolPolicy
+		*out = new(QueryToolPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySpec.
@@ -1829,6 +2322,18 @@ func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
 		}
 	}
 	out.TokenUsage = in.TokenUsage
+	if in.ModelUsage != nil {
+		in, out := &in.ModelUsage, &out.ModelUsage
+		*out = make([]ModelUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(Cost)
+		**out = **in
+	}
 	if in.Duration != nil {
 		in, out := &in.Duration, &out.Duration
 		*out = new(v1.Duration)
@@ -1861,6 +2366,55 @@ func (in *QueryTarget) DeepCopy() *QueryTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryToolMatcher) DeepCopyInto(out *QueryToolMatcher) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(ToolAnnotationHints)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryToolMatcher.
+func (in *QueryToolMatcher) DeepCopy() *QueryToolMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryToolMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryToolPolicy) DeepCopyInto(out *QueryToolPolicy) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]QueryToolMatcher, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]QueryToolMatcher, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryToolPolicy.
+func (in *QueryToolPolicy) DeepCopy() *QueryToolPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryToolPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 	*out = *in
@@ -1896,6 +2450,11 @@ func (in *Response) DeepCopyInto(out *Response) {
 		*out = new(A2AMetadata)
 		**out = **in
 	}
+	if in.Vote != nil {
+		in, out := &in.Vote, &out.Vote
+		*out = new(VoteResult)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Response.
@@ -1908,6 +2467,28 @@ func (in *Response) DeepCopy() *Response {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterModelConfig) DeepCopyInto(out *RouterModelConfig) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]ModelRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterModelConfig.
+func (in *RouterModelConfig) DeepCopy() *RouterModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
 	*out = *in
@@ -1950,9 +2531,34 @@ func (in *Team) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamContextSpec) DeepCopyInto(out *TeamContextSpec) {
+	*out = *in
+	if in.ModelRef != nil {
+		in, out := &in.ModelRef, &out.ModelRef
+		*out = new(AgentModelRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamContextSpec.
+func (in *TeamContextSpec) DeepCopy() *TeamContextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamContextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamGraphEdge) DeepCopyInto(out *TeamGraphEdge) {
 	*out = *in
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(TeamGraphEdgeCondition)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamGraphEdge.
@@ -1965,13 +2571,30 @@ func (in *TeamGraphEdge) DeepCopy() *TeamGraphEdge {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamGraphEdgeCondition) DeepCopyInto(out *TeamGraphEdgeCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamGraphEdgeCondition.
+func (in *TeamGraphEdgeCondition) DeepCopy() *TeamGraphEdgeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamGraphEdgeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamGraphSpec) DeepCopyInto(out *TeamGraphSpec) {
 	*out = *in
 	if in.Edges != nil {
 		in, out := &in.Edges, &out.Edges
 		*out = make([]TeamGraphEdge, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -2020,6 +2643,11 @@ func (in *TeamList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamMember) DeepCopyInto(out *TeamMember) {
 	*out = *in
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = new(TeamContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamMember.
@@ -2032,6 +2660,41 @@ func (in *TeamMember) DeepCopy() *TeamMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamParallelSpec) DeepCopyInto(out *TeamParallelSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamParallelSpec.
+func (in *TeamParallelSpec) DeepCopy() *TeamParallelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamParallelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamPlanSpec) DeepCopyInto(out *TeamPlanSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamPlanSpec.
+func (in *TeamPlanSpec) DeepCopy() *TeamPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamSelectorSpec) DeepCopyInto(out *TeamSelectorSpec) {
 	*out = *in
@@ -2053,7 +2716,9 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 	if in.Members != nil {
 		in, out := &in.Members, &out.Members
 		*out = make([]TeamMember, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.MaxTurns != nil {
 		in, out := &in.MaxTurns, &out.MaxTurns
@@ -2070,6 +2735,31 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = new(TeamGraphSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Parallel != nil {
+		in, out := &in.Parallel, &out.Parallel
+		*out = new(TeamParallelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(TeamPlanSpec)
+		**out = **in
+	}
+	if in.Vote != nil {
+		in, out := &in.Vote, &out.Vote
+		*out = new(TeamVoteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = new(TeamContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Termination != nil {
+		in, out := &in.Termination, &out.Termination
+		*out = new(TeamTerminationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -2104,6 +2794,56 @@ func (in *TeamStatus) DeepCopy() *TeamStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamTerminationSpec) DeepCopyInto(out *TeamTerminationSpec) {
+	*out = *in
+	if in.MaxDuration != nil {
+		in, out := &in.MaxDuration, &out.MaxDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamTerminationSpec.
+func (in *TeamTerminationSpec) DeepCopy() *TeamTerminationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamTerminationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamToolRef.
+func (in *TeamToolRef) DeepCopy() *TeamToolRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamToolRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamVoteSpec) DeepCopyInto(out *TeamVoteSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamVoteSpec.
+func (in *TeamVoteSpec) DeepCopy() *TeamVoteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamVoteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenUsage) DeepCopyInto(out *TokenUsage) {
 	*out = *in
@@ -2146,6 +2886,41 @@ func (in *Tool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolAnnotationHints) DeepCopyInto(out *ToolAnnotationHints) {
+	*out = *in
+	if in.ReadOnlyHint != nil {
+		in, out := &in.ReadOnlyHint, &out.ReadOnlyHint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DestructiveHint != nil {
+		in, out := &in.DestructiveHint, &out.DestructiveHint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IdempotentHint != nil {
+		in, out := &in.IdempotentHint, &out.IdempotentHint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OpenWorldHint != nil {
+		in, out := &in.OpenWorldHint, &out.OpenWorldHint
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolAnnotationHints.
+func (in *ToolAnnotationHints) DeepCopy() *ToolAnnotationHints {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolAnnotationHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolAnnotations.
 func (in *ToolAnnotations) DeepCopy() *ToolAnnotations {
 	if in == nil {
@@ -2203,6 +2978,26 @@ func (in *ToolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolOutputStep) DeepCopyInto(out *ToolOutputStep) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolOutputStep.
+func (in *ToolOutputStep) DeepCopy() *ToolOutputStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolOutputStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolPartial) DeepCopyInto(out *ToolPartial) {
 	*out = *in
@@ -2223,6 +3018,101 @@ func (in *ToolPartial) DeepCopy() *ToolPartial {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSet) DeepCopyInto(out *ToolSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSet.
+func (in *ToolSet) DeepCopy() *ToolSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToolSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSetList) DeepCopyInto(out *ToolSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ToolSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSetList.
+func (in *ToolSetList) DeepCopy() *ToolSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToolSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSetRef) DeepCopyInto(out *ToolSetRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSetRef.
+func (in *ToolSetRef) DeepCopy() *ToolSetRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSetRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSetSpec) DeepCopyInto(out *ToolSetSpec) {
+	*out = *in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]AgentTool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSetSpec.
+func (in *ToolSetSpec) DeepCopy() *ToolSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSpec.
 func (in *ToolSpec) DeepCopy() *ToolSpec {
 	if in == nil {
@@ -2302,3 +3192,38 @@ func (in *ValueSource) DeepCopy() *ValueSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VoteCandidate) DeepCopyInto(out *VoteCandidate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VoteCandidate.
+func (in *VoteCandidate) DeepCopy() *VoteCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(VoteCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VoteResult) DeepCopyInto(out *VoteResult) {
+	*out = *in
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]VoteCandidate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VoteResult.
+func (in *VoteResult) DeepCopy() *VoteResult {
+	if in == nil {
+		return nil
+	}
+	out := new(VoteResult)
+	in.DeepCopyInto(out)
+	return out
+}
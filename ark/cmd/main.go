@@ -300,6 +300,7 @@ func setupWebhooks(mgr ctrl.Manager) {
 		{"Agent", webhookv1.SetupAgentWebhookWithManager},
 		{"Query", webhookv1.SetupQueryWebhookWithManager},
 		{"Tool", webhookv1.SetupToolWebhookWithManager},
+		{"ToolSet", webhookv1.SetupToolSetWebhookWithManager},
 		{"Model", webhookv1.SetupModelWebhookWithManager},
 		{"MCPServer", webhookv1.SetupMCPServerWebhookWithManager},
 		{"Evaluator", webhookv1.SetupEvaluatorWebhookWithManager},
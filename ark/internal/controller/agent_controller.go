@@ -5,10 +5,12 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -20,6 +22,8 @@ import (
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
 	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/labels"
 )
 
 const (
@@ -37,6 +41,7 @@ type AgentReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents/finalizers,verbs=update
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=toolsets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=a2aservers,verbs=get;list;watch
 
@@ -67,8 +72,22 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	// Check current condition
 	currentCondition := meta.FindStatusCondition(agent.Status.Conditions, AgentAvailable)
 
+	// Resolve ToolSet, MCP server and selector references into the effective tool set
+	tools, err := genai.ResolveAgentTools(ctx, r.Client, &agent)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to resolve agent tools")
+		return ctrl.Result{}, err
+	}
+	toolsChanged := r.setEffectiveTools(&agent, tools)
+
 	// Check all dependencies and determine new status
-	available, reason, message := r.checkDependencies(ctx, &agent)
+	var available bool
+	var reason, message string
+	if err != nil {
+		available, reason, message = false, "ToolSetNotFound", err.Error()
+	} else {
+		available, reason, message = r.checkDependencies(ctx, &agent, tools)
+	}
 
 	// Determine new status
 	var newStatus metav1.ConditionStatus
@@ -79,12 +98,15 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	// Only update if status actually changed
-	if currentCondition == nil || currentCondition.Status != newStatus || currentCondition.Reason != reason {
+	conditionChanged := currentCondition == nil || currentCondition.Status != newStatus || currentCondition.Reason != reason
+	if conditionChanged {
 		log.Info("agent status changed", "agent", agent.Name, "available", newStatus, "reason", reason)
 		r.setCondition(&agent, AgentAvailable, newStatus, reason, message)
 		if !available {
 			r.Eventing.AgentRecorder().DependencyUnavailable(ctx, &agent, message)
 		}
+	}
+	if conditionChanged || toolsChanged {
 		if err := r.updateStatus(ctx, &agent); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -94,7 +116,7 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 }
 
 // checkDependencies validates all agent dependencies and returns availability status
-func (r *AgentReconciler) checkDependencies(ctx context.Context, agent *arkv1alpha1.Agent) (available bool, reason, message string) {
+func (r *AgentReconciler) checkDependencies(ctx context.Context, agent *arkv1alpha1.Agent, tools []arkv1alpha1.AgentTool) (available bool, reason, message string) {
 	// Check A2AServer dependency (if agent is owned by an A2AServer)
 	if ok, msg := r.checkA2AServerDependency(ctx, agent); !ok {
 		return false, "A2AServerNotReady", msg
@@ -108,7 +130,7 @@ func (r *AgentReconciler) checkDependencies(ctx context.Context, agent *arkv1alp
 	}
 
 	// Check tool dependencies
-	if ok, msg := r.checkToolDependencies(ctx, agent); !ok {
+	if ok, msg := r.checkToolDependencies(ctx, agent, tools); !ok {
 		return false, "ToolNotFound", msg
 	}
 
@@ -145,9 +167,9 @@ func (r *AgentReconciler) checkModelDependency(ctx context.Context, agent *arkv1
 	return true, ""
 }
 
-// checkToolDependencies validates tool dependencies
-func (r *AgentReconciler) checkToolDependencies(ctx context.Context, agent *arkv1alpha1.Agent) (bool, string) {
-	for _, toolSpec := range agent.Spec.Tools {
+// checkToolDependencies validates tool dependencies against the resolved tool set
+func (r *AgentReconciler) checkToolDependencies(ctx context.Context, agent *arkv1alpha1.Agent, tools []arkv1alpha1.AgentTool) (bool, string) {
+	for _, toolSpec := range tools {
 		if toolSpec.Type == "custom" && toolSpec.Name != "" {
 
 			toolName := toolSpec.GetToolCRDName()
@@ -212,6 +234,19 @@ func (r *AgentReconciler) isA2AServerReady(a2aServer *arkv1prealpha1.A2AServer)
 	return false
 }
 
+// setEffectiveTools records the resolved tool names in the Agent status and reports whether they changed
+func (r *AgentReconciler) setEffectiveTools(agent *arkv1alpha1.Agent, tools []arkv1alpha1.AgentTool) bool {
+	effectiveTools := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		effectiveTools = append(effectiveTools, tool.Name)
+	}
+	if slices.Equal(agent.Status.Tools, effectiveTools) {
+		return false
+	}
+	agent.Status.Tools = effectiveTools
+	return true
+}
+
 // setCondition sets a condition on the Agent
 func (r *AgentReconciler) setCondition(agent *arkv1alpha1.Agent, conditionType string, status metav1.ConditionStatus, reason, message string) {
 	meta.SetStatusCondition(&agent.Status.Conditions, metav1.Condition{
@@ -244,6 +279,11 @@ func (r *AgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&arkv1alpha1.Tool{},
 			handler.EnqueueRequestsFromMapFunc(r.findAgentsForTool),
 		).
+		// Watch for ToolSet events and reconcile dependent agents
+		Watches(
+			&arkv1alpha1.ToolSet{},
+			handler.EnqueueRequestsFromMapFunc(r.findAgentsForToolSet),
+		).
 		// Watch for Model events and reconcile dependent agents
 		Watches(
 			&arkv1alpha1.Model{},
@@ -266,7 +306,19 @@ func (r *AgentReconciler) findAgentsForTool(ctx context.Context, obj client.Obje
 	}
 
 	return r.findAgentsForDependency(ctx, tool.Name, tool.Namespace, "tool", func(agent *arkv1alpha1.Agent) bool {
-		return r.agentDependsOnTool(agent, tool.Name)
+		return r.agentDependsOnTool(agent, tool.Name) || r.agentSelectsTool(agent, tool)
+	})
+}
+
+// findAgentsForToolSet finds agents that reference the given toolset
+func (r *AgentReconciler) findAgentsForToolSet(ctx context.Context, obj client.Object) []reconcile.Request {
+	toolSet, ok := obj.(*arkv1alpha1.ToolSet)
+	if !ok {
+		return nil
+	}
+
+	return r.findAgentsForDependency(ctx, toolSet.Name, toolSet.Namespace, "toolset", func(agent *arkv1alpha1.Agent) bool {
+		return r.agentDependsOnToolSet(agent, toolSet.Name)
 	})
 }
 
@@ -332,6 +384,39 @@ func (r *AgentReconciler) agentDependsOnTool(agent *arkv1alpha1.Agent, toolName
 			}
 		}
 	}
+	// Tools that no longer match an MCP server or selector reference are still listed
+	// in the status until the agent is reconciled again
+	return slices.Contains(agent.Status.Tools, toolName)
+}
+
+// agentSelectsTool checks if a tool is attached to an agent through an MCP server reference or a label selector
+func (r *AgentReconciler) agentSelectsTool(agent *arkv1alpha1.Agent, tool *arkv1alpha1.Tool) bool {
+	for _, toolSpec := range agent.Spec.Tools {
+		switch toolSpec.Type {
+		case genai.AgentToolTypeMCP:
+			if toolSpec.MCPServer != nil && tool.Labels[labels.MCPServerLabel] == toolSpec.MCPServer.Name {
+				return true
+			}
+		case genai.AgentToolTypeSelector:
+			if toolSpec.Selector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(toolSpec.Selector)
+			if err == nil && selector.Matches(k8slabels.Set(tool.Labels)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// agentDependsOnToolSet checks if an agent references a specific toolset
+func (r *AgentReconciler) agentDependsOnToolSet(agent *arkv1alpha1.Agent, toolSetName string) bool {
+	for _, toolSpec := range agent.Spec.Tools {
+		if toolSpec.Type == genai.AgentToolTypeToolSet && toolSpec.ToolSet != nil && toolSpec.ToolSet.Name == toolSetName {
+			return true
+		}
+	}
 	return false
 }
 
@@ -453,7 +453,7 @@ func (r *EvaluationReconciler) processDirectEvaluation(ctx context.Context, eval
 	}
 
 	// Complete evaluation with all results in one operation
-	if err := r.updateEvaluationComplete(ctx, evaluation, response, "Direct evaluation completed successfully"); err != nil {
+	if err := r.updateEvaluationComplete(ctx, evaluation, response, paramMap, "Direct evaluation completed successfully"); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -664,7 +664,7 @@ func (r *EvaluationReconciler) processQueryEvaluation(ctx context.Context, evalu
 	log.Info("Evaluation response received", "evaluation", evaluation.Name, "metadata", response.Metadata, "metadata_count", len(response.Metadata))
 
 	// Complete evaluation with all results including metadata annotations in one atomic operation
-	if err := r.updateEvaluationComplete(ctx, evaluation, response, "Query evaluation completed successfully"); err != nil {
+	if err := r.updateEvaluationComplete(ctx, evaluation, response, parameters, "Query evaluation completed successfully"); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -726,9 +726,30 @@ func (r *EvaluationReconciler) updateStatus(ctx context.Context, evaluation arkv
 	})
 }
 
-func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, evaluation arkv1alpha1.Evaluation, response *genai.EvaluationResponse, message string) error {
+// evaluatorModelUsage attributes the token usage of an evaluator to the model it was given in the
+// model.name and model.namespace parameters, priced when the model has pricing
+func (r *EvaluationReconciler) evaluatorModelUsage(ctx context.Context, evaluation arkv1alpha1.Evaluation, parameters map[string]string, usage *arkv1alpha1.TokenUsage) []arkv1alpha1.ModelUsage {
+	modelName := parameters[paramModelName]
+	if usage == nil || modelName == "" {
+		return nil
+	}
+	modelNamespace := parameters[paramModelNamespace]
+	if modelNamespace == "" {
+		modelNamespace = evaluation.Namespace
+	}
+
+	modelUsage, err := genai.PriceModelUsage(ctx, r.Client, modelName, modelNamespace, *usage)
+	if err != nil {
+		logf.FromContext(ctx).Info("Unable to price evaluator token usage", "evaluation", evaluation.Name, "model", modelName, "error", err.Error())
+	}
+	return []arkv1alpha1.ModelUsage{modelUsage}
+}
+
+func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, evaluation arkv1alpha1.Evaluation, response *genai.EvaluationResponse, parameters map[string]string, message string) error {
 	log := logf.FromContext(ctx)
 
+	modelUsage, cost := genai.SumModelUsage(r.evaluatorModelUsage(ctx, evaluation, parameters, response.TokenUsage))
+
 	evalKey := client.ObjectKey{
 		Name:      evaluation.Name,
 		Namespace: evaluation.Namespace,
@@ -770,6 +791,8 @@ func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, eva
 		latest.Status.Score = response.Score
 		latest.Status.Passed = response.Passed
 		latest.Status.TokenUsage = response.TokenUsage
+		latest.Status.ModelUsage = modelUsage
+		latest.Status.Cost = cost
 		latest.Status.Phase = statusDone
 		latest.Status.Message = message
 
@@ -907,6 +930,8 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 		TotalTokens:      0,
 	}
 
+	childModelUsage := make([][]arkv1alpha1.ModelUsage, 0, len(childEvaluations.Items))
+
 	// Aggregate results from all children
 	for _, child := range childEvaluations.Items {
 		// Count passed/failed
@@ -929,7 +954,10 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 			aggregatedTokenUsage.PromptTokens += child.Status.TokenUsage.PromptTokens
 			aggregatedTokenUsage.CompletionTokens += child.Status.TokenUsage.CompletionTokens
 			aggregatedTokenUsage.TotalTokens += child.Status.TokenUsage.TotalTokens
+			aggregatedTokenUsage.CachedPromptTokens += child.Status.TokenUsage.CachedPromptTokens
+			aggregatedTokenUsage.ReasoningTokens += child.Status.TokenUsage.ReasoningTokens
 		}
+		childModelUsage = append(childModelUsage, child.Status.ModelUsage)
 	}
 
 	// Calculate average score
@@ -951,6 +979,7 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 	parentEvaluation.Status.Phase = statusDone
 	parentEvaluation.Status.Message = message
 	parentEvaluation.Status.TokenUsage = &aggregatedTokenUsage
+	parentEvaluation.Status.ModelUsage, parentEvaluation.Status.Cost = genai.SumModelUsage(childModelUsage...)
 
 	r.setConditionCompleted(&parentEvaluation, metav1.ConditionTrue, "EvaluationCompleted", message)
 
@@ -1004,7 +1033,7 @@ func (r *EvaluationReconciler) processBaselineEvaluation(ctx context.Context, ev
 	}
 
 	// Complete evaluation with all results including metadata annotations using atomic update
-	if err := r.updateEvaluationComplete(ctx, evaluation, response, "Baseline evaluation completed successfully"); err != nil {
+	if err := r.updateEvaluationComplete(ctx, evaluation, response, paramMap, "Baseline evaluation completed successfully"); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -1097,7 +1126,7 @@ func (r *EvaluationReconciler) processEventEvaluation(ctx context.Context, evalu
 	}
 
 	// Complete evaluation with all results including metadata annotations using atomic update
-	if err := r.updateEvaluationComplete(ctx, evaluation, response, statusMessage); err != nil {
+	if err := r.updateEvaluationComplete(ctx, evaluation, response, paramMap, statusMessage); err != nil {
 		return ctrl.Result{}, err
 	}
 
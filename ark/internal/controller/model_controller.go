@@ -46,8 +46,11 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if err := r.Get(ctx, req.NamespacedName, &model); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			log.Error(err, "unable to fetch model", "model", req.NamespacedName)
+			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		// The model was deleted, so its rate limits no longer apply
+		genai.RemoveModelLimiter(req.Namespace, req.Name)
+		return ctrl.Result{}, nil
 	}
 
 	// Initialize conditions if empty
@@ -58,7 +61,7 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	// Probe the model to test whether it is available.
-	result := r.probeModel(ctx, model)
+	resolvedModel, result := r.probeModel(ctx, model)
 
 	if !result.Available {
 		changed, err := r.reconcileCondition(ctx, &model, ModelAvailable, metav1.ConditionFalse, "ModelProbeFailed", result.Message)
@@ -81,11 +84,19 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileEmbedding(ctx, &model, result); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileCapabilities(ctx, &model, resolvedModel); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Continue polling at regular interval
 	return ctrl.Result{RequeueAfter: model.Spec.PollInterval.Duration}, nil
 }
 
-func (r *ModelReconciler) probeModel(ctx context.Context, model arkv1alpha1.Model) genai.ProbeResult {
+func (r *ModelReconciler) probeModel(ctx context.Context, model arkv1alpha1.Model) (*genai.Model, genai.ProbeResult) {
 	noopTelemetryRecorder := telenoop.NewModelRecorder()
 	noopEventingRecorder := eventnoop.NewModelRecorder()
 	resolvedModel, err := genai.LoadModel(ctx, r.Client, &arkv1alpha1.AgentModelRef{
@@ -93,15 +104,62 @@ func (r *ModelReconciler) probeModel(ctx context.Context, model arkv1alpha1.Mode
 		Namespace: model.Namespace,
 	}, model.Namespace, nil, noopTelemetryRecorder, noopEventingRecorder)
 	if err != nil {
-		return genai.ProbeResult{
+		return nil, genai.ProbeResult{
 			Available:     false,
 			Message:       "Failed to load model configuration",
 			DetailedError: err,
 		}
 	}
 
-	result := genai.ProbeModel(ctx, resolvedModel)
-	return result
+	mode := genai.ProbeModeCompletion
+	if model.Spec.Probe != nil && model.Spec.Probe.Mode != "" {
+		mode = model.Spec.Probe.Mode
+	}
+	result := genai.ProbeModel(ctx, resolvedModel, mode)
+	return resolvedModel, result
+}
+
+// reconcileEmbedding records the size of the embeddings of embedding models. Probes that do not
+// embed, such as the listModels probe, keep the size observed before.
+func (r *ModelReconciler) reconcileEmbedding(ctx context.Context, model *arkv1alpha1.Model, result genai.ProbeResult) error {
+	if model.Spec.Task != genai.ModelTaskEmbedding {
+		if model.Status.Embedding == nil {
+			return nil
+		}
+		model.Status.Embedding = nil
+		return r.updateStatus(ctx, model)
+	}
+
+	if result.EmbeddingDimensions == 0 {
+		return nil
+	}
+	if model.Status.Embedding != nil && model.Status.Embedding.Dimensions == result.EmbeddingDimensions {
+		return nil
+	}
+	model.Status.Embedding = &arkv1alpha1.ModelEmbeddingStatus{Dimensions: result.EmbeddingDimensions}
+	return r.updateStatus(ctx, model)
+}
+
+// reconcileCapabilities detects the capabilities of the model once for every generation of the
+// spec, as detection makes several calls to the model. Embedding models have no chat capabilities.
+func (r *ModelReconciler) reconcileCapabilities(ctx context.Context, model *arkv1alpha1.Model, resolvedModel *genai.Model) error {
+	if model.Spec.Probe == nil || !model.Spec.Probe.DetectCapabilities || model.Spec.Task == genai.ModelTaskEmbedding {
+		if model.Status.Capabilities == nil {
+			return nil
+		}
+		model.Status.Capabilities = nil
+		return r.updateStatus(ctx, model)
+	}
+
+	if model.Status.Capabilities != nil && model.Status.Capabilities.ObservedGeneration == model.Generation {
+		return nil
+	}
+
+	capabilities := genai.DetectModelCapabilities(ctx, resolvedModel)
+	capabilities.ObservedGeneration = model.Generation
+	model.Status.Capabilities = &capabilities
+	logf.FromContext(ctx).Info("detected model capabilities", "model", model.Name, "capabilities", capabilities)
+	return r.updateStatus(ctx, model)
 }
 
 // reconcileCondition updates a condition on the Model and updates status
@@ -63,6 +63,7 @@ type QueryReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=teams,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=impersonate
 
 func (r *QueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -189,9 +190,31 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 	r.Telemetry.QueryRecorder().RecordSessionID(span, sessionId)
 	defer span.End()
 
+	// Teams save their progress after every turn, so a query interrupted by a controller restart
+	// continues from the last completed turn instead of starting over
+	checkpoints := genai.NewSecretCheckpointStore(r.Client, &obj)
+	resumed, err := checkpoints.HasCheckpoints(opCtx)
+	if err != nil {
+		log.Error(err, "Failed to check for query checkpoints", "query", namespacedName.String())
+	}
+	opCtx = genai.WithCheckpointStore(opCtx, checkpoints)
+	defer func() {
+		if opCtx.Err() == nil {
+			if err := checkpoints.Clear(opCtx); err != nil {
+				log.Error(err, "Failed to delete query checkpoints", "query", namespacedName.String())
+			}
+		}
+	}()
+
+	var startData map[string]string
+	if resumed {
+		log.Info("Resuming query from checkpoint", "query", namespacedName.String())
+		startData = map[string]string{"resumed": "true"}
+	}
+
 	opCtx = r.Eventing.QueryRecorder().InitializeQueryContext(opCtx, &obj)
 	opCtx = r.Eventing.QueryRecorder().StartTokenCollection(opCtx)
-	opCtx = r.Eventing.QueryRecorder().Start(opCtx, "QueryExecution", fmt.Sprintf("Executing query %s", obj.Name), nil)
+	opCtx = r.Eventing.QueryRecorder().Start(opCtx, "QueryExecution", fmt.Sprintf("Executing query %s", obj.Name), startData)
 
 	impersonatedClient, memory, err := r.setupQueryExecution(opCtx, obj, sessionId)
 	if err != nil {
@@ -223,10 +246,14 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 
 	tokenSummary := r.Eventing.QueryRecorder().GetTokenSummary(opCtx)
 	obj.Status.TokenUsage = tokenSummary
+	obj.Status.ModelUsage, obj.Status.Cost = genai.SumModelUsage(r.Eventing.QueryRecorder().GetModelUsage(opCtx))
 
 	if tokenSummary.TotalTokens > 0 {
 		r.Telemetry.QueryRecorder().RecordTokenUsage(span, tokenSummary.PromptTokens, tokenSummary.CompletionTokens, tokenSummary.TotalTokens)
 	}
+	if obj.Status.Cost != nil {
+		r.Telemetry.QueryRecorder().RecordCost(span, obj.Status.Cost.Value(), obj.Status.Cost.Currency)
+	}
 
 	queryStatus := r.determineQueryStatus(responses)
 	_ = r.updateStatus(opCtx, &obj, queryStatus)
@@ -241,6 +268,10 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 		"completionTokens": fmt.Sprintf("%d", tokenSummary.CompletionTokens),
 		"totalTokens":      fmt.Sprintf("%d", tokenSummary.TotalTokens),
 	}
+	if obj.Status.Cost != nil {
+		operationData["cost"] = obj.Status.Cost.Amount
+		operationData["currency"] = obj.Status.Cost.Currency
+	}
 	r.Eventing.QueryRecorder().Complete(opCtx, "QueryExecution", "Query execution completed", operationData)
 }
 
@@ -452,6 +483,8 @@ func (r *QueryReconciler) processTargetResults(resultChan chan targetResult) []a
 			// Skip targets that were delegated to external execution engines (executionResult == nil or messages == nil)
 		default:
 			response := r.createSuccessResponse(result.target, result.executionResult.Messages)
+			response.TerminationReason = result.executionResult.TerminationReason
+			response.Vote = result.executionResult.Vote
 			if result.executionResult.A2AResponse != nil {
 				response.A2A = &arkv1alpha1.A2AMetadata{
 					ContextID: result.executionResult.A2AResponse.ContextID,
@@ -849,6 +882,10 @@ func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query
 		return nil, fmt.Errorf("unable to get tool %v, error:%w", toolKey, err)
 	}
 
+	if !genai.ToolAllowedByPolicy(crd.Spec.ToolPolicy, toolName, &toolCRD) {
+		return nil, fmt.Errorf("tool %s is not allowed by the query tool policy", toolName)
+	}
+
 	// For tools, extract the content from the last message as tool arguments
 	lastMessage := inputMessages[len(inputMessages)-1]
 	var resolvedInput string
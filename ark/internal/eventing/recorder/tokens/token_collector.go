@@ -2,14 +2,66 @@ package tokens
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/openai/openai-go"
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
 )
 
-type tokenUsageKeyType struct{}
+type (
+	tokenUsageKeyType struct{}
+	modelUsageKeyType struct{}
+)
+
+var (
+	tokenUsageKey = tokenUsageKeyType{}
+	modelUsageKey = modelUsageKeyType{}
+)
+
+// modelUsage collects the usage per model. A collection started within another one, such as the
+// collection of a team within a query, also adds its usage to the enclosing collections.
+type modelUsage struct {
+	mu     sync.Mutex
+	models []*modelTotals
+	parent *modelUsage
+}
+
+type modelTotals struct {
+	model     string
+	namespace string
+	usage     arkv1alpha1.TokenUsage
+	cost      float64
+	currency  string
+}
 
-var tokenUsageKey = tokenUsageKeyType{}
+func (m *modelUsage) add(call eventing.ModelCallUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var totals *modelTotals
+	for _, existing := range m.models {
+		if existing.model == call.Model && existing.namespace == call.Namespace {
+			totals = existing
+			break
+		}
+	}
+	if totals == nil {
+		totals = &modelTotals{model: call.Model, namespace: call.Namespace}
+		m.models = append(m.models, totals)
+	}
+
+	totals.usage.PromptTokens += call.Usage.PromptTokens
+	totals.usage.CompletionTokens += call.Usage.CompletionTokens
+	totals.usage.TotalTokens += call.Usage.TotalTokens
+	totals.usage.CachedPromptTokens += call.Usage.CachedPromptTokens
+	totals.usage.ReasoningTokens += call.Usage.ReasoningTokens
+	if call.Currency != "" {
+		totals.cost += call.Cost
+		totals.currency = call.Currency
+	}
+}
 
 type TokenCollector struct{}
 
@@ -19,26 +71,56 @@ func NewTokenCollector() TokenCollector {
 
 func (tc *TokenCollector) StartTokenCollection(ctx context.Context) context.Context {
 	usage := &arkv1alpha1.TokenUsage{}
-	return context.WithValue(ctx, tokenUsageKey, usage)
+	ctx = context.WithValue(ctx, tokenUsageKey, usage)
+
+	parent, _ := ctx.Value(modelUsageKey).(*modelUsage)
+	return context.WithValue(ctx, modelUsageKey, &modelUsage{parent: parent})
 }
 
 func (tc *TokenCollector) AddTokens(ctx context.Context, promptTokens, completionTokens, totalTokens int64) {
+	tc.addTokens(ctx, arkv1alpha1.TokenUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      totalTokens,
+	})
+}
+
+func (tc *TokenCollector) addTokens(ctx context.Context, add arkv1alpha1.TokenUsage) {
 	usage, ok := ctx.Value(tokenUsageKey).(*arkv1alpha1.TokenUsage)
 	if !ok || usage == nil {
 		return
 	}
 
-	usage.PromptTokens += promptTokens
-	usage.CompletionTokens += completionTokens
-	usage.TotalTokens += totalTokens
+	// Members of parallel teams report usage concurrently
+	atomic.AddInt64(&usage.PromptTokens, add.PromptTokens)
+	atomic.AddInt64(&usage.CompletionTokens, add.CompletionTokens)
+	atomic.AddInt64(&usage.TotalTokens, add.TotalTokens)
+	atomic.AddInt64(&usage.CachedPromptTokens, add.CachedPromptTokens)
+	atomic.AddInt64(&usage.ReasoningTokens, add.ReasoningTokens)
 }
 
 func (tc *TokenCollector) AddTokenUsage(ctx context.Context, usage arkv1alpha1.TokenUsage) {
-	tc.AddTokens(ctx, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	tc.addTokens(ctx, usage)
 }
 
 func (tc *TokenCollector) AddCompletionUsage(ctx context.Context, usage openai.CompletionUsage) {
-	tc.AddTokens(ctx, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	tc.addTokens(ctx, arkv1alpha1.TokenUsage{
+		PromptTokens:       usage.PromptTokens,
+		CompletionTokens:   usage.CompletionTokens,
+		TotalTokens:        usage.TotalTokens,
+		CachedPromptTokens: usage.PromptTokensDetails.CachedTokens,
+		ReasoningTokens:    usage.CompletionTokensDetails.ReasoningTokens,
+	})
+}
+
+// AddModelUsage adds the usage of a model call to the totals and to the usage of the model
+func (tc *TokenCollector) AddModelUsage(ctx context.Context, usage eventing.ModelCallUsage) {
+	tc.addTokens(ctx, usage.Usage)
+
+	collection, _ := ctx.Value(modelUsageKey).(*modelUsage)
+	for ; collection != nil; collection = collection.parent {
+		collection.add(usage)
+	}
 }
 
 func (tc *TokenCollector) GetTokenSummary(ctx context.Context) arkv1alpha1.TokenUsage {
@@ -47,5 +129,36 @@ func (tc *TokenCollector) GetTokenSummary(ctx context.Context) arkv1alpha1.Token
 		return arkv1alpha1.TokenUsage{}
 	}
 
-	return *usage
+	return arkv1alpha1.TokenUsage{
+		PromptTokens:       atomic.LoadInt64(&usage.PromptTokens),
+		CompletionTokens:   atomic.LoadInt64(&usage.CompletionTokens),
+		TotalTokens:        atomic.LoadInt64(&usage.TotalTokens),
+		CachedPromptTokens: atomic.LoadInt64(&usage.CachedPromptTokens),
+		ReasoningTokens:    atomic.LoadInt64(&usage.ReasoningTokens),
+	}
+}
+
+// GetModelUsage returns the usage per model in the order the models were first called
+func (tc *TokenCollector) GetModelUsage(ctx context.Context) []arkv1alpha1.ModelUsage {
+	collection, ok := ctx.Value(modelUsageKey).(*modelUsage)
+	if !ok || collection == nil {
+		return nil
+	}
+
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	var result []arkv1alpha1.ModelUsage
+	for _, totals := range collection.models {
+		usage := arkv1alpha1.ModelUsage{
+			Model:      totals.model,
+			Namespace:  totals.namespace,
+			TokenUsage: totals.usage,
+		}
+		if totals.currency != "" {
+			usage.Cost = arkv1alpha1.NewCost(totals.cost, totals.currency)
+		}
+		result = append(result, usage)
+	}
+	return result
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/stretchr/testify/assert"
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
 )
 
 func TestTokenCollector_StartTokenCollection(t *testing.T) {
@@ -85,6 +86,9 @@ func TestTokenCollector_AddCompletionUsage(t *testing.T) {
 		PromptTokens:     100,
 		CompletionTokens: 50,
 		TotalTokens:      150,
+		CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{
+			ReasoningTokens: 30,
+		},
 	}
 	tc.AddCompletionUsage(ctx, completionUsage)
 
@@ -92,6 +96,7 @@ func TestTokenCollector_AddCompletionUsage(t *testing.T) {
 	assert.Equal(t, int64(100), usage.PromptTokens)
 	assert.Equal(t, int64(50), usage.CompletionTokens)
 	assert.Equal(t, int64(150), usage.TotalTokens)
+	assert.Equal(t, int64(30), usage.ReasoningTokens)
 }
 
 func TestTokenCollector_GetTokenSummary_NoCollection(t *testing.T) {
@@ -123,3 +128,67 @@ func TestTokenCollector_GetTokenSummary_WrongType(t *testing.T) {
 	assert.Equal(t, int64(0), usage.CompletionTokens)
 	assert.Equal(t, int64(0), usage.TotalTokens)
 }
+
+func TestTokenCollector_AddModelUsage(t *testing.T) {
+	tc := NewTokenCollector()
+	ctx := tc.StartTokenCollection(context.Background())
+
+	tc.AddModelUsage(ctx, eventing.ModelCallUsage{
+		Model:     "gpt-4o",
+		Namespace: "default",
+		Usage:     arkv1alpha1.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150, CachedPromptTokens: 40, ReasoningTokens: 20},
+		Cost:      0.25,
+		Currency:  "USD",
+	})
+	tc.AddModelUsage(ctx, eventing.ModelCallUsage{
+		Model:     "claude",
+		Namespace: "default",
+		Usage:     arkv1alpha1.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	})
+	tc.AddModelUsage(ctx, eventing.ModelCallUsage{
+		Model:     "gpt-4o",
+		Namespace: "default",
+		Usage:     arkv1alpha1.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150},
+		Cost:      0.5,
+		Currency:  "USD",
+	})
+
+	assert.Equal(t, arkv1alpha1.TokenUsage{PromptTokens: 210, CompletionTokens: 105, TotalTokens: 315, CachedPromptTokens: 40, ReasoningTokens: 20}, tc.GetTokenSummary(ctx))
+
+	usage := tc.GetModelUsage(ctx)
+	assert.Len(t, usage, 2)
+	assert.Equal(t, "gpt-4o", usage[0].Model)
+	assert.Equal(t, int64(300), usage[0].TotalTokens)
+	assert.Equal(t, int64(40), usage[0].CachedPromptTokens)
+	assert.Equal(t, int64(20), usage[0].ReasoningTokens)
+	assert.Equal(t, &arkv1alpha1.Cost{Amount: "0.75", Currency: "USD"}, usage[0].Cost)
+	assert.Equal(t, "claude", usage[1].Model)
+	assert.Nil(t, usage[1].Cost)
+}
+
+func TestTokenCollector_AddModelUsage_Nested(t *testing.T) {
+	tc := NewTokenCollector()
+	queryCtx := tc.StartTokenCollection(context.Background())
+	teamCtx := tc.StartTokenCollection(queryCtx)
+
+	tc.AddModelUsage(teamCtx, eventing.ModelCallUsage{
+		Model:     "gpt-4o",
+		Namespace: "default",
+		Usage:     arkv1alpha1.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150},
+	})
+
+	assert.Len(t, tc.GetModelUsage(teamCtx), 1)
+	assert.Equal(t, tc.GetModelUsage(teamCtx), tc.GetModelUsage(queryCtx))
+
+	// Totals of nested collections are forwarded by the team itself
+	assert.Equal(t, int64(0), tc.GetTokenSummary(queryCtx).TotalTokens)
+}
+
+func TestTokenCollector_GetModelUsage_NoCollection(t *testing.T) {
+	tc := NewTokenCollector()
+	ctx := context.Background()
+
+	tc.AddModelUsage(ctx, eventing.ModelCallUsage{Model: "gpt-4o", Usage: arkv1alpha1.TokenUsage{TotalTokens: 10}})
+
+	assert.Nil(t, tc.GetModelUsage(ctx))
+}
@@ -1,6 +1,10 @@
 package recorder
 
 import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
 	"mckinsey.com/ark/internal/eventing"
 	"mckinsey.com/ark/internal/eventing/recorder/operations"
 )
@@ -16,3 +20,7 @@ func NewToolRecorder(emitter eventing.EventEmitter) eventing.ToolRecorder {
 		emitter:          emitter,
 	}
 }
+
+func (t *toolRecorder) ToolsHidden(ctx context.Context, obj runtime.Object, reason string) {
+	t.emitter.EmitNormal(ctx, obj, "ToolsHidden", reason)
+}
@@ -21,6 +21,18 @@ type TokenCollector interface {
 	AddTokenUsage(ctx context.Context, usage arkv1alpha1.TokenUsage)
 	AddCompletionUsage(ctx context.Context, usage openai.CompletionUsage)
 	GetTokenSummary(ctx context.Context) arkv1alpha1.TokenUsage
+	AddModelUsage(ctx context.Context, usage ModelCallUsage)
+	GetModelUsage(ctx context.Context) []arkv1alpha1.ModelUsage
+}
+
+// ModelCallUsage is the token usage of a call to a Model resource. Currency is empty when the
+// model has no pricing.
+type ModelCallUsage struct {
+	Model     string
+	Namespace string
+	Usage     arkv1alpha1.TokenUsage
+	Cost      float64
+	Currency  string
 }
 
 type ModelRecorder interface {
@@ -72,6 +84,7 @@ type QueryRecorder interface {
 
 type ToolRecorder interface {
 	OperationTracker
+	ToolsHidden(ctx context.Context, obj runtime.Object, reason string)
 }
 
 type MemoryRecorder interface {
@@ -31,6 +31,7 @@ type Agent struct {
 	ExecutionEngine   *arkv1alpha1.ExecutionEngineRef
 	Annotations       map[string]string
 	OutputSchema      *runtime.RawExtension
+	Generation        *GenerationParameters
 	client            client.Client
 }
 
@@ -50,7 +51,7 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 	ctx = a.eventingRecorder.Start(ctx, "AgentExecution", fmt.Sprintf("Executing agent %s", a.FullName()), operationData)
 
 	result, err := a.executeAgent(ctx, userInput, history, memory, eventStream)
-	if err != nil {
+	if err != nil && !IsHandoffTeam(err) {
 		a.telemetryRecorder.RecordError(span, err)
 		if !IsTerminateTeam(err) {
 			a.eventingRecorder.Fail(ctx, "AgentExecution", fmt.Sprintf("Agent execution failed: %v", err), err, operationData)
@@ -60,15 +61,24 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 
 	a.telemetryRecorder.RecordSuccess(span)
 	a.eventingRecorder.Complete(ctx, "AgentExecution", "Agent execution completed successfully", operationData)
-	return result, nil
+	// A handoff is returned with the messages that led to it
+	return result, err
 }
 
 func (a *Agent) executeAgent(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	ctx, err := enterCall(ctx, a.client, CallFrame{Kind: MemberTypeAgent, Namespace: a.Namespace, Name: a.Name})
+	if err != nil {
+		return nil, err
+	}
+
 	if a.ExecutionEngine != nil {
 		return a.executeWithExecutionEngineRouter(ctx, userInput, history, eventStream)
 	}
 
 	messages, err := a.executeLocally(ctx, userInput, history, memory, eventStream)
+	if IsHandoffTeam(err) {
+		return &ExecutionResult{Messages: messages}, err
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +140,7 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 	a.Model.OutputSchema = a.OutputSchema
 	// Truncate schema name to 64 chars for OpenAI API compatibility - name is purely an identifier
 	a.Model.SchemaName = fmt.Sprintf("%.64s", fmt.Sprintf("namespace-%s-agent-%s", a.Namespace, a.Name))
+	a.Model.GenerationOverrides = a.Generation
 
 	response, err := a.Model.ChatCompletion(ctx, agentMessages, eventStream, 1, tools)
 	if err != nil {
@@ -165,6 +176,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 }
 
 func (a *Agent) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, agentMessages, newMessages *[]Message) error {
+	var handoff error
 	for _, tc := range toolCalls {
 		if ctx.Err() != nil {
 			return ctx.Err()
@@ -174,11 +186,18 @@ func (a *Agent) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCom
 		*agentMessages = append(*agentMessages, toolMessage)
 		*newMessages = append(*newMessages, toolMessage)
 
+		// Remaining tool calls still need results before the next member takes over
+		if IsHandoffTeam(err) {
+			if handoff == nil {
+				handoff = err
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	return handoff
 }
 
 // executeLocally executes the agent using the built-in OpenAI-compatible engine
@@ -221,7 +240,7 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 
 		if err := a.executeToolCalls(ctx, choice.Message.ToolCalls, &agentMessages, &newMessages); err != nil {
 			logger := logf.FromContext(ctx)
-			if !IsTerminateTeam(err) {
+			if !IsTerminateTeam(err) && !IsHandoffTeam(err) {
 				logger.Error(err, "Tool execution failed", "agent", a.FullName())
 			}
 			return newMessages, err
@@ -363,6 +382,11 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 		}
 	}
 
+	generation, err := ParseGenerationParameters(crd.Spec.Generation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generation parameters for agent %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+
 	query, err := MakeQuery(queryCrd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make query from context for agent %s/%s: %w", crd.Namespace, crd.Name, err)
@@ -393,6 +417,7 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 		ExecutionEngine:   crd.Spec.ExecutionEngine,
 		Annotations:       crd.Annotations,
 		OutputSchema:      crd.Spec.OutputSchema,
+		Generation:        generation,
 		client:            k8sClient,
 	}, nil
 }
@@ -3,15 +3,20 @@ package genai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/labels"
 	"mckinsey.com/ark/internal/telemetry"
 )
 
@@ -61,18 +66,214 @@ func (p *MCPClientPool) Close() error {
 }
 
 func (r *ToolRegistry) registerTools(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) error {
-	for _, agentTool := range agent.Spec.Tools {
+	agentTools, err := ResolveAgentTools(ctx, k8sClient, agent)
+	if err != nil {
+		return err
+	}
+
+	var hidden []string
+	for _, agentTool := range agentTools {
 		if err := r.registerTool(ctx, k8sClient, agentTool, agent.Namespace, telemetryProvider, eventingProvider); err != nil {
+			if errors.Is(err, errToolHiddenByPolicy) {
+				hidden = append(hidden, agentTool.Name)
+				continue
+			}
 			return err
 		}
 	}
+
+	if len(hidden) > 0 {
+		reason := fmt.Sprintf("Query tool policy hid tools from agent %s: %s", agent.Name, strings.Join(hidden, ", "))
+		logf.FromContext(ctx).Info("tools hidden by query tool policy", "agent", agent.Name, "tools", hidden)
+		if query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query); ok {
+			eventingProvider.ToolRecorder().ToolsHidden(ctx, query, reason)
+		}
+	}
 	return nil
 }
 
+// ResolveAgentTools expands ToolSet, MCP server and selector references in the agent's tool list
+// into individual tools. Tools listed directly on the agent take precedence over tools from
+// ToolSets, and both take precedence over expanded tools with the same name. Expanded tools are
+// returned in name order so the effective set is stable.
+func ResolveAgentTools(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent) ([]arkv1alpha1.AgentTool, error) {
+	agentTools, err := ExpandToolSets(ctx, k8sClient, agent.Spec.Tools, agent.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]arkv1alpha1.AgentTool, 0, len(agentTools))
+	seen := make(map[string]bool)
+
+	for _, agentTool := range agentTools {
+		if agentTool.Type == AgentToolTypeMCP || agentTool.Type == AgentToolTypeSelector {
+			continue
+		}
+		if seen[agentTool.Name] {
+			continue
+		}
+		resolved = append(resolved, agentTool)
+		seen[agentTool.Name] = true
+	}
+
+	for _, agentTool := range agentTools {
+		if agentTool.Type != AgentToolTypeMCP && agentTool.Type != AgentToolTypeSelector {
+			continue
+		}
+		tools, err := ListExpandedTools(ctx, k8sClient, agentTool, agent.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tool := range tools {
+			if seen[tool.Name] {
+				continue
+			}
+			seen[tool.Name] = true
+			resolved = append(resolved, arkv1alpha1.AgentTool{
+				Type:      AgentToolTypeCustom,
+				Name:      tool.Name,
+				Functions: agentTool.Functions,
+			})
+		}
+	}
+
+	return resolved, nil
+}
+
+// ExpandToolSets replaces ToolSet references with the tools they contain. Tools listed directly
+// are returned first, followed by the ToolSet tools in reference order.
+func ExpandToolSets(ctx context.Context, k8sClient client.Client, agentTools []arkv1alpha1.AgentTool, namespace string) ([]arkv1alpha1.AgentTool, error) {
+	expanded := make([]arkv1alpha1.AgentTool, 0, len(agentTools))
+	var fromToolSets []arkv1alpha1.AgentTool
+
+	for _, agentTool := range agentTools {
+		if agentTool.Type != AgentToolTypeToolSet {
+			expanded = append(expanded, agentTool)
+			continue
+		}
+		if agentTool.ToolSet == nil || agentTool.ToolSet.Name == "" {
+			return nil, fmt.Errorf("toolset tool reference requires toolSet.name")
+		}
+
+		var toolSet arkv1alpha1.ToolSet
+		key := types.NamespacedName{Name: agentTool.ToolSet.Name, Namespace: namespace}
+		if err := k8sClient.Get(ctx, key, &toolSet); err != nil {
+			return nil, fmt.Errorf("failed to get toolset %v: %w", key, err)
+		}
+
+		for _, setTool := range toolSet.Spec.Tools {
+			if setTool.Type == AgentToolTypeToolSet {
+				return nil, fmt.Errorf("toolset %s cannot reference another toolset", toolSet.Name)
+			}
+			fromToolSets = append(fromToolSets, setTool)
+		}
+	}
+
+	return append(expanded, fromToolSets...), nil
+}
+
+// ListExpandedTools returns the Tools an MCP server or selector tool entry attaches, in name order
+func ListExpandedTools(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, namespace string) ([]arkv1alpha1.Tool, error) {
+	switch agentTool.Type {
+	case AgentToolTypeMCP:
+		return listMCPServerTools(ctx, k8sClient, agentTool.MCPServer, namespace)
+	case AgentToolTypeSelector:
+		return listSelectedTools(ctx, k8sClient, agentTool.Selector, namespace)
+	}
+	return nil, fmt.Errorf("tool type %s does not expand to tools", agentTool.Type)
+}
+
+func listMCPServerTools(ctx context.Context, k8sClient client.Client, ref *arkv1alpha1.AgentToolMCPServer, namespace string) ([]arkv1alpha1.Tool, error) {
+	if ref == nil || ref.Name == "" {
+		return nil, fmt.Errorf("mcp tool reference requires mcpServer.name")
+	}
+
+	var toolList arkv1alpha1.ToolList
+	if err := k8sClient.List(ctx, &toolList, client.InNamespace(namespace), client.MatchingLabels{labels.MCPServerLabel: ref.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list tools for MCP server %s: %w", ref.Name, err)
+	}
+
+	tools := make([]arkv1alpha1.Tool, 0, len(toolList.Items))
+	for _, tool := range toolList.Items {
+		if len(ref.Include) > 0 && !matchesMCPToolName(tool, ref.Include) {
+			continue
+		}
+		if matchesMCPToolName(tool, ref.Exclude) {
+			continue
+		}
+		tools = append(tools, tool)
+	}
+	sortToolsByName(tools)
+	return tools, nil
+}
+
+func listSelectedTools(ctx context.Context, k8sClient client.Client, selector *metav1.LabelSelector, namespace string) ([]arkv1alpha1.Tool, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("selector tool reference requires a selector")
+	}
+	// An empty selector would attach every Tool in the namespace, so it attaches none
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return nil, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool selector: %w", err)
+	}
+
+	var toolList arkv1alpha1.ToolList
+	if err := k8sClient.List(ctx, &toolList, &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list tools for selector: %w", err)
+	}
+
+	tools := toolList.Items
+	sortToolsByName(tools)
+	return tools, nil
+}
+
+func matchesMCPToolName(tool arkv1alpha1.Tool, names []string) bool {
+	for _, name := range names {
+		if tool.Name == name {
+			return true
+		}
+		if tool.Spec.MCP != nil && tool.Spec.MCP.ToolName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortToolsByName(tools []arkv1alpha1.Tool) {
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+}
+
 func CreateToolExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, mcpPool *MCPClientPool, mcpSettings map[string]MCPSettings, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (ToolExecutor, error) {
+	executor, err := createTypedToolExecutor(ctx, k8sClient, tool, namespace, mcpPool, mcpSettings, telemetryProvider, eventingProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Post-processing is part of the tool itself, so it runs before any agent-level partial or function filtering
+	if len(tool.Spec.PostProcess) > 0 {
+		executor = &PostProcessToolExecutor{
+			BaseExecutor: executor,
+			Steps:        tool.Spec.PostProcess,
+		}
+	}
+
+	return executor, nil
+}
+
+func createTypedToolExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, mcpPool *MCPClientPool, mcpSettings map[string]MCPSettings, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (ToolExecutor, error) {
 	switch tool.Spec.Type {
 	case ToolTypeHTTP:
-		return createHTTPExecutor(k8sClient, tool, namespace)
+		return createHTTPExecutor(k8sClient, tool, namespace, eventingProvider)
 	case ToolTypeMCP:
 		return createMCPExecutor(ctx, k8sClient, tool, namespace, mcpPool, mcpSettings)
 	case ToolTypeAgent:
@@ -139,7 +340,7 @@ func createBuiltinExecutor(tool *arkv1alpha1.Tool) (ToolExecutor, error) {
 	}
 }
 
-func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
+func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, eventingProvider eventing.Provider) (ToolExecutor, error) {
 	if tool.Spec.HTTP == nil {
 		return nil, fmt.Errorf("http spec is required for tool %s", tool.Name)
 	}
@@ -147,6 +348,7 @@ func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespa
 		K8sClient:     k8sClient,
 		ToolName:      tool.Name,
 		ToolNamespace: namespace,
+		Eventing:      eventingProvider.ToolRecorder(),
 	}, nil
 }
 
@@ -225,6 +427,10 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 		return fmt.Errorf("failed to get tool %s: %w", toolName, err)
 	}
 
+	if !ToolAllowedByPolicy(queryToolPolicy(ctx), agentTool.Name, tool) {
+		return errToolHiddenByPolicy
+	}
+
 	toolDef := CreateToolFromCRD(tool)
 
 	// Set the exposed name (the name the agent will see)
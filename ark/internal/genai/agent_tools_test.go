@@ -569,3 +569,235 @@ func TestTeamToolExecutor_Execute(t *testing.T) {
 		t.Skip("Requires full setup with models and agents - better suited for integration tests")
 	})
 }
+
+func TestResolveAgentTools(t *testing.T) {
+	mcpTool := func(name, server, toolName string) *arkv1alpha1.Tool {
+		return &arkv1alpha1.Tool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"mcp/server": server},
+			},
+			Spec: arkv1alpha1.ToolSpec{
+				Type: ToolTypeMCP,
+				MCP: &arkv1alpha1.MCPToolRef{
+					MCPServerRef: arkv1alpha1.MCPServerRef{Name: server},
+					ToolName:     toolName,
+				},
+			},
+		}
+	}
+	httpTool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "weather",
+			Namespace: "default",
+			Labels:    map[string]string{"category": "weather"},
+		},
+		Spec: arkv1alpha1.ToolSpec{Type: ToolTypeHTTP},
+	}
+	otherNamespaceTool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "forecast",
+			Namespace: "other",
+			Labels:    map[string]string{"category": "weather"},
+		},
+		Spec: arkv1alpha1.ToolSpec{Type: ToolTypeHTTP},
+	}
+
+	k8sClient := setupTestClientForTools([]client.Object{
+		mcpTool("github-search-code", "github", "search_code"),
+		mcpTool("github-create-issue", "github", "create_issue"),
+		mcpTool("github-delete-repo", "github", "delete_repo"),
+		mcpTool("jira-create-issue", "jira", "create_issue"),
+		httpTool,
+		otherNamespaceTool,
+	})
+
+	tests := []struct {
+		name     string
+		tools    []arkv1alpha1.AgentTool
+		expected []string
+	}{
+		{
+			name: "mcp server attaches all generated tools in name order",
+			tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeMCP, MCPServer: &arkv1alpha1.AgentToolMCPServer{Name: "github"}},
+			},
+			expected: []string{"github-create-issue", "github-delete-repo", "github-search-code"},
+		},
+		{
+			name: "mcp server include and exclude filters",
+			tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeMCP, MCPServer: &arkv1alpha1.AgentToolMCPServer{
+					Name:    "github",
+					Include: []string{"search_code", "github-delete-repo", "create_issue"},
+					Exclude: []string{"delete_repo"},
+				}},
+			},
+			expected: []string{"github-create-issue", "github-search-code"},
+		},
+		{
+			name: "selector matches tools in the agent namespace only",
+			tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeSelector, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "weather"}}},
+			},
+			expected: []string{"weather"},
+		},
+		{
+			name: "empty selector matches no tools",
+			tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeSelector, Selector: &metav1.LabelSelector{}},
+			},
+			expected: []string{},
+		},
+		{
+			name: "explicit tools come first and are not duplicated",
+			tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeSelector, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"mcp/server": "jira"}}},
+				{Type: AgentToolTypeCustom, Name: "jira-create-issue", Description: "Open a ticket"},
+			},
+			expected: []string{"jira-create-issue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+				Spec:       arkv1alpha1.AgentSpec{Tools: tt.tools},
+			}
+
+			resolved, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(resolved))
+			for _, tool := range resolved {
+				require.Equal(t, AgentToolTypeCustom, tool.Type)
+				names = append(names, tool.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("explicit tool keeps its agent level settings", func(t *testing.T) {
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeCustom, Name: "jira-create-issue", Description: "Open a ticket"},
+				{Type: AgentToolTypeMCP, MCPServer: &arkv1alpha1.AgentToolMCPServer{Name: "jira"}},
+			}},
+		}
+
+		resolved, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+		require.NoError(t, err)
+		require.Len(t, resolved, 1)
+		require.Equal(t, "Open a ticket", resolved[0].Description)
+	})
+
+	t.Run("mcp reference without server name fails", func(t *testing.T) {
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeMCP},
+			}},
+		}
+
+		_, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+		require.Error(t, err)
+	})
+}
+
+func TestResolveAgentToolsWithToolSets(t *testing.T) {
+	weatherTool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"},
+		Spec:       arkv1alpha1.ToolSpec{Type: ToolTypeHTTP},
+	}
+	toolSet := &arkv1alpha1.ToolSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "common", Namespace: "default"},
+		Spec: arkv1alpha1.ToolSetSpec{
+			Tools: []arkv1alpha1.AgentTool{
+				{
+					Type: AgentToolTypeCustom,
+					Name: "weather-celsius",
+					Partial: &arkv1alpha1.ToolPartial{
+						Name:       "weather",
+						Parameters: []arkv1alpha1.ToolFunction{{Name: "units", Value: "celsius"}},
+					},
+				},
+				{Type: AgentToolTypeCustom, Name: "weather", Description: "From the toolset"},
+				{Type: AgentToolTypeBuiltIn, Name: BuiltinToolNoop},
+			},
+		},
+	}
+	nestedToolSet := &arkv1alpha1.ToolSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "nested", Namespace: "default"},
+		Spec: arkv1alpha1.ToolSetSpec{
+			Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeToolSet, ToolSet: &arkv1alpha1.ToolSetRef{Name: "common"}},
+			},
+		},
+	}
+	k8sClient := setupTestClientForTools([]client.Object{weatherTool, toolSet, nestedToolSet})
+
+	t.Run("toolset tools are added after direct tools", func(t *testing.T) {
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeToolSet, ToolSet: &arkv1alpha1.ToolSetRef{Name: "common"}},
+				{Type: AgentToolTypeCustom, Name: "weather", Description: "From the agent"},
+			}},
+		}
+
+		resolved, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+		require.NoError(t, err)
+		require.Len(t, resolved, 3)
+		require.Equal(t, "weather", resolved[0].Name)
+		require.Equal(t, "From the agent", resolved[0].Description)
+		require.Equal(t, "weather-celsius", resolved[1].Name)
+		require.NotNil(t, resolved[1].Partial)
+		require.Equal(t, BuiltinToolNoop, resolved[2].Name)
+	})
+
+	t.Run("missing toolset fails", func(t *testing.T) {
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeToolSet, ToolSet: &arkv1alpha1.ToolSetRef{Name: "missing"}},
+			}},
+		}
+
+		_, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+		require.Error(t, err)
+	})
+
+	t.Run("nested toolsets are rejected", func(t *testing.T) {
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeToolSet, ToolSet: &arkv1alpha1.ToolSetRef{Name: "nested"}},
+			}},
+		}
+
+		_, err := ResolveAgentTools(context.Background(), k8sClient, agent)
+		require.ErrorContains(t, err, "cannot reference another toolset")
+	})
+}
+
+func TestGetToolTypeOfWrappedExecutors(t *testing.T) {
+	registry := NewToolRegistry(nil, noop.NewProvider().ToolRecorder(), eventnoop.NewProvider().ToolRecorder())
+	httpExecutor := &HTTPExecutor{ToolName: "weather"}
+	mcpExecutor := &MCPExecutor{ToolName: "search"}
+
+	registry.RegisterTool(ToolDefinition{Name: "weather"}, &FilteredToolExecutor{
+		BaseExecutor: &PartialToolExecutor{
+			BaseExecutor: &PostProcessToolExecutor{BaseExecutor: httpExecutor},
+		},
+	})
+	registry.RegisterTool(ToolDefinition{Name: "search"}, &PostProcessToolExecutor{BaseExecutor: mcpExecutor})
+	registry.RegisterTool(ToolDefinition{Name: "noop"}, &PartialToolExecutor{BaseExecutor: &NoopExecutor{}})
+
+	require.Equal(t, "custom", registry.GetToolType("weather"))
+	require.Equal(t, "mcp", registry.GetToolType("search"))
+	require.Equal(t, "builtin", registry.GetToolType("noop"))
+	require.Equal(t, "unknown", registry.GetToolType("missing"))
+}
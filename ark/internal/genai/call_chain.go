@@ -0,0 +1,140 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ExecutionConfigMapName is the optional per-namespace ConfigMap holding execution limits
+	ExecutionConfigMapName = "ark-config-execution"
+	// MaxNestingDepthKey sets how many agents and teams may be nested in one execution
+	MaxNestingDepthKey = "maxNestingDepth"
+	// DefaultMaxNestingDepth applies when the namespace does not configure a limit
+	DefaultMaxNestingDepth = 10
+)
+
+const callChainKey contextKey = "callChain"
+
+// CallFrame identifies an agent or team in the chain of nested executions
+type CallFrame struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (f CallFrame) String() string {
+	return f.Kind + "/" + f.Name
+}
+
+type callChain struct {
+	frames   []CallFrame
+	maxDepth int
+}
+
+// NestingError is returned when an agent or team calls itself, directly or through other agents
+// and teams, or when calls are nested deeper than the namespace allows
+type NestingError struct {
+	Chain    []CallFrame
+	Cycle    bool
+	MaxDepth int
+}
+
+func (e *NestingError) Error() string {
+	if e.Cycle {
+		return fmt.Sprintf("call cycle detected: %s", formatCallChain(e.Chain))
+	}
+	return fmt.Sprintf("maximum nesting depth of %d exceeded: %s", e.MaxDepth, formatCallChain(e.Chain))
+}
+
+func IsNestingError(err error) bool {
+	var nestingErr *NestingError
+	return errors.As(err, &nestingErr)
+}
+
+// CallChain returns the agents and teams that are executing, outermost first
+func CallChain(ctx context.Context) []CallFrame {
+	if chain, ok := ctx.Value(callChainKey).(*callChain); ok {
+		return slices.Clone(chain.frames)
+	}
+	return nil
+}
+
+// enterCall adds an agent or team to the call chain. The nesting limit is read from the namespace of
+// the outermost call and applies to the whole chain.
+func enterCall(ctx context.Context, k8sClient client.Client, frame CallFrame) (context.Context, error) {
+	chain, ok := ctx.Value(callChainKey).(*callChain)
+	if !ok {
+		maxDepth, err := GetMaxNestingDepth(ctx, k8sClient, frame.Namespace)
+		if err != nil {
+			return ctx, err
+		}
+		chain = &callChain{maxDepth: maxDepth}
+	}
+	return chain.push(ctx, frame)
+}
+
+// enterTeamLoad adds a team to the call chain while its members are loaded. Outside of an execution
+// only cycles are checked, the nesting limit is enforced once the team executes.
+func enterTeamLoad(ctx context.Context, frame CallFrame) (context.Context, error) {
+	chain, ok := ctx.Value(callChainKey).(*callChain)
+	if !ok {
+		chain = &callChain{maxDepth: math.MaxInt}
+	}
+	return chain.push(ctx, frame)
+}
+
+func (chain *callChain) push(ctx context.Context, frame CallFrame) (context.Context, error) {
+	frames := append(slices.Clone(chain.frames), frame)
+	if slices.Contains(chain.frames, frame) {
+		return ctx, &NestingError{Chain: frames, Cycle: true}
+	}
+	if len(frames) > chain.maxDepth {
+		return ctx, &NestingError{Chain: frames, MaxDepth: chain.maxDepth}
+	}
+
+	return context.WithValue(ctx, callChainKey, &callChain{frames: frames, maxDepth: chain.maxDepth}), nil
+}
+
+// GetMaxNestingDepth loads the nesting limit of a namespace from its execution ConfigMap
+// Returns DefaultMaxNestingDepth if the ConfigMap or the key does not exist
+func GetMaxNestingDepth(ctx context.Context, k8sClient client.Client, namespace string) (int, error) {
+	if k8sClient == nil {
+		return DefaultMaxNestingDepth, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ExecutionConfigMapName, Namespace: namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return DefaultMaxNestingDepth, nil
+		}
+		return 0, fmt.Errorf("failed to get execution ConfigMap: %w", err)
+	}
+
+	value, ok := cm.Data[MaxNestingDepthKey]
+	if !ok {
+		return DefaultMaxNestingDepth, nil
+	}
+	maxDepth, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || maxDepth < 1 {
+		return 0, fmt.Errorf("execution ConfigMap %s/%s: %s must be a positive integer, got %q", namespace, ExecutionConfigMapName, MaxNestingDepthKey, value)
+	}
+	return maxDepth, nil
+}
+
+func formatCallChain(frames []CallFrame) string {
+	names := make([]string, len(frames))
+	for i, frame := range frames {
+		names[i] = frame.String()
+	}
+	return strings.Join(names, " -> ")
+}
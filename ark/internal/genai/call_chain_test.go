@@ -0,0 +1,128 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// delegatingTeamMember calls another member with the same context, like an agent calling a team tool
+type delegatingTeamMember struct {
+	name     string
+	delegate TeamMember
+	chain    []CallFrame
+}
+
+func (m *delegatingTeamMember) GetName() string        { return m.name }
+func (m *delegatingTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *delegatingTeamMember) GetDescription() string { return "" }
+
+func (m *delegatingTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	m.chain = CallChain(ctx)
+	if m.delegate == nil {
+		return &ExecutionResult{Messages: []Message{NewAssistantMessage(m.name + " reply")}}, nil
+	}
+	return m.delegate.Execute(ctx, userInput, history, memory, eventStream)
+}
+
+func newCallChainTestTeam(name string, k8sClient client.Client, members ...TeamMember) *Team {
+	return &Team{
+		Name:              name,
+		Namespace:         "default",
+		Strategy:          "sequential",
+		Members:           members,
+		Client:            k8sClient,
+		telemetryRecorder: noop.NewProvider().TeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+}
+
+func newCallChainTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = arkv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestCallChain(t *testing.T) {
+	t.Run("tracks nested teams", func(t *testing.T) {
+		leaf := &delegatingTeamMember{name: "leaf"}
+		inner := newCallChainTestTeam("inner", nil, leaf)
+		outer := newCallChainTestTeam("outer", nil, inner)
+
+		_, err := outer.Execute(context.Background(), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []CallFrame{
+			{Kind: MemberTypeTeam, Namespace: "default", Name: "outer"},
+			{Kind: MemberTypeTeam, Namespace: "default", Name: "inner"},
+		}, leaf.chain)
+	})
+
+	t.Run("fails fast on a cycle", func(t *testing.T) {
+		caller := &delegatingTeamMember{name: "caller"}
+		team := newCallChainTestTeam("research", nil, caller)
+		caller.delegate = team
+
+		_, err := team.Execute(context.Background(), NewUserMessage("go"), nil, nil, nil)
+		require.True(t, IsNestingError(err))
+		require.EqualError(t, err, "call cycle detected: team/research -> team/research")
+	})
+
+	t.Run("enforces the namespace nesting depth", func(t *testing.T) {
+		k8sClient := newCallChainTestClient(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ExecutionConfigMapName, Namespace: "default"},
+			Data:       map[string]string{MaxNestingDepthKey: "2"},
+		})
+		leaf := newCallChainTestTeam("leaf", k8sClient, &delegatingTeamMember{name: "writer"})
+		middle := newCallChainTestTeam("middle", k8sClient, leaf)
+		outer := newCallChainTestTeam("outer", k8sClient, middle)
+
+		_, err := outer.Execute(context.Background(), NewUserMessage("go"), nil, nil, nil)
+		require.EqualError(t, err, "maximum nesting depth of 2 exceeded: team/outer -> team/middle -> team/leaf")
+
+		_, err = middle.Execute(context.Background(), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an invalid nesting depth", func(t *testing.T) {
+		k8sClient := newCallChainTestClient(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ExecutionConfigMapName, Namespace: "default"},
+			Data:       map[string]string{MaxNestingDepthKey: "none"},
+		})
+
+		_, err := GetMaxNestingDepth(context.Background(), k8sClient, "default")
+		require.ErrorContains(t, err, "maxNestingDepth must be a positive integer")
+
+		maxDepth, err := GetMaxNestingDepth(context.Background(), k8sClient, "other")
+		require.NoError(t, err)
+		require.Equal(t, DefaultMaxNestingDepth, maxDepth)
+	})
+
+	t.Run("detects teams that contain themselves when loading", func(t *testing.T) {
+		k8sClient := newCallChainTestClient(
+			&arkv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"},
+				Spec:       arkv1alpha1.TeamSpec{Strategy: "sequential", Members: []arkv1alpha1.TeamMember{{Name: "b", Type: MemberTypeTeam}}},
+			},
+			&arkv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"},
+				Spec:       arkv1alpha1.TeamSpec{Strategy: "sequential", Members: []arkv1alpha1.TeamMember{{Name: "a", Type: MemberTypeTeam}}},
+			},
+		)
+		var crd arkv1alpha1.Team
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "a", Namespace: "default"}, &crd))
+
+		_, err := MakeTeam(context.Background(), k8sClient, &crd, noop.NewProvider(), eventnoop.NewProvider())
+		require.ErrorContains(t, err, "call cycle detected: team/a -> team/b -> team/a")
+	})
+}
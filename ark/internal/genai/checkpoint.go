@@ -0,0 +1,302 @@
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const checkpointStoreKey contextKey = "checkpointStore"
+
+// maxCheckpointKeyLength is the longest Secret key a call chain is written as before it is hashed
+const maxCheckpointKeyLength = 253
+
+// maxCheckpointSecretSize is the most checkpoint data a Secret holds, the Kubernetes limit of 1 MiB
+// less room for the object metadata
+const maxCheckpointSecretSize = 1<<20 - 16<<10
+
+// TeamCheckpoint is the progress of a team after its last completed turn
+type TeamCheckpoint struct {
+	// Strategy the team ran with. Checkpoints of another strategy are ignored
+	Strategy string `json:"strategy"`
+	// Turn to execute next
+	Turn int `json:"turn"`
+	// Member that executes the next turn, or for the selector strategy the member of the last turn
+	Member string `json:"member,omitempty"`
+	// Members a handoff team went through so far
+	HandoffChain []string `json:"handoffChain,omitempty"`
+	// Messages the team produced so far
+	Messages []Message `json:"messages"`
+}
+
+// CheckpointStore persists team checkpoints so that an interrupted query can resume. Keys identify
+// a team by its call chain, so nested teams have their own checkpoints.
+type CheckpointStore interface {
+	Load(ctx context.Context, key string) (*TeamCheckpoint, error)
+	Save(ctx context.Context, key string, checkpoint *TeamCheckpoint) error
+	Delete(ctx context.Context, key string) error
+}
+
+// WithCheckpointStore makes teams executed with the returned context save and resume checkpoints
+func WithCheckpointStore(ctx context.Context, store CheckpointStore) context.Context {
+	return context.WithValue(ctx, checkpointStoreKey, store)
+}
+
+func checkpointStoreFrom(ctx context.Context) CheckpointStore {
+	store, _ := ctx.Value(checkpointStoreKey).(CheckpointStore)
+	return store
+}
+
+// checkpointKey turns a call chain into a Secret key, such as team.research_agent.planner_team.review
+func checkpointKey(frames []CallFrame) string {
+	parts := make([]string, len(frames))
+	for i, frame := range frames {
+		parts[i] = frame.Kind + "." + frame.Name
+	}
+	key := strings.Join(parts, "_")
+	if len(key) > maxCheckpointKeyLength {
+		sum := sha256.Sum256([]byte(key))
+		return "chain." + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// resumeCheckpoint returns the progress saved by an earlier, interrupted execution of the team.
+// Checkpoints of another strategy or naming a member the team no longer has are ignored, as are
+// handoff and graph checkpoints without the member of the next turn.
+func (t *teamRun) resumeCheckpoint(ctx context.Context) *TeamCheckpoint {
+	store := checkpointStoreFrom(ctx)
+	if store == nil {
+		return nil
+	}
+
+	checkpoint, err := store.Load(ctx, checkpointKey(CallChain(ctx)))
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to load team checkpoint, starting from the first turn", "team", t.FullName())
+		return nil
+	}
+	if checkpoint == nil || checkpoint.Strategy != t.Strategy {
+		return nil
+	}
+	if checkpoint.Member == "" && (t.Strategy == "handoff" || t.Strategy == "graph") {
+		return nil
+	}
+	if checkpoint.Member != "" && !slices.ContainsFunc(t.Members, func(m TeamMember) bool { return m.GetName() == checkpoint.Member }) {
+		return nil
+	}
+
+	t.resumedTurn = checkpoint.Turn
+	return checkpoint
+}
+
+// saveCheckpoint records the progress of the team after a completed turn. Failing to save does not
+// fail the team. It is reported as a warning event on the query, and an interrupted query then
+// resumes from an earlier checkpoint or starts the team over.
+func (t *teamRun) saveCheckpoint(ctx context.Context, checkpoint TeamCheckpoint, newMessages []Message) {
+	store := checkpointStoreFrom(ctx)
+	if store == nil {
+		return
+	}
+
+	checkpoint.Strategy = t.Strategy
+	checkpoint.Messages = newMessages
+	if err := store.Save(ctx, checkpointKey(CallChain(ctx)), &checkpoint); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to save team checkpoint", "team", t.FullName(), "turn", checkpoint.Turn)
+		t.eventingRecorder.Fail(ctx, "TeamCheckpoint", fmt.Sprintf("Failed to save checkpoint of team %s: %v", t.Name, err), err, map[string]string{
+			"teamName": t.Name,
+			"strategy": t.Strategy,
+			"turn":     fmt.Sprintf("%d", checkpoint.Turn),
+		})
+	}
+}
+
+// clearCheckpoint removes the checkpoint of a team that finished, so that running the team again in
+// the same query starts from the first turn
+func (t *teamRun) clearCheckpoint(ctx context.Context) {
+	store := checkpointStoreFrom(ctx)
+	if store == nil {
+		return
+	}
+
+	if err := store.Delete(ctx, checkpointKey(CallChain(ctx))); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to delete team checkpoint", "team", t.FullName())
+	}
+}
+
+// CheckpointSecretName returns the name of the Secret holding the checkpoints of a query
+func CheckpointSecretName(queryName string) string {
+	return queryName + "-checkpoint"
+}
+
+// SecretCheckpointStore keeps the team checkpoints of a query in a Secret owned by the query, so the
+// checkpoints are removed together with the query. Checkpoints hold the conversation, which is why
+// they are not kept in a ConfigMap.
+type SecretCheckpointStore struct {
+	client client.Client
+	query  *arkv1alpha1.Query
+	mu     sync.Mutex
+	// current is the Secret as last written, which the cache may not have caught up with yet
+	current *corev1.Secret
+}
+
+func NewSecretCheckpointStore(k8sClient client.Client, query *arkv1alpha1.Query) *SecretCheckpointStore {
+	return &SecretCheckpointStore{client: k8sClient, query: query}
+}
+
+func (s *SecretCheckpointStore) Load(ctx context.Context, key string) (*TeamCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, err := s.get(ctx)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, nil
+	}
+
+	var checkpoint TeamCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s in Secret %s: %w", key, secret.Name, err)
+	}
+	return &checkpoint, nil
+}
+
+func (s *SecretCheckpointStore) Save(ctx context.Context, key string, checkpoint *TeamCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to serialize checkpoint: %w", err)
+	}
+
+	return s.update(ctx, func(secret *corev1.Secret) {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[key] = data
+	})
+}
+
+func (s *SecretCheckpointStore) Delete(ctx context.Context, key string) error {
+	return s.update(ctx, func(secret *corev1.Secret) {
+		delete(secret.Data, key)
+	})
+}
+
+// HasCheckpoints reports whether an earlier execution of the query saved progress
+func (s *SecretCheckpointStore) HasCheckpoints(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, err := s.get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return secret != nil && len(secret.Data) > 0, nil
+}
+
+// Clear deletes the Secret once the query has finished
+func (s *SecretCheckpointStore) Clear(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: CheckpointSecretName(s.query.Name), Namespace: s.query.Namespace}}
+	s.current = nil
+	if err := s.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete checkpoint Secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+// get returns the checkpoint Secret, or nil when it does not exist
+func (s *SecretCheckpointStore) get(ctx context.Context) (*corev1.Secret, error) {
+	if s.current != nil {
+		return s.current, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: CheckpointSecretName(s.query.Name), Namespace: s.query.Namespace}
+	if err := s.client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get checkpoint Secret %s: %w", key.Name, err)
+	}
+	s.current = secret
+	return secret, nil
+}
+
+// update applies a change to the Secret, creating it when needed. An empty Secret is kept until
+// Clear, so that a stale cache cannot bring back deleted checkpoints.
+func (s *SecretCheckpointStore) update(ctx context.Context, mutate func(secret *corev1.Secret)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		current, err := s.get(ctx)
+		if err != nil {
+			return err
+		}
+
+		var secret *corev1.Secret
+		if current != nil {
+			secret = current.DeepCopy()
+		} else {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            CheckpointSecretName(s.query.Name),
+					Namespace:       s.query.Namespace,
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(s.query, arkv1alpha1.GroupVersion.WithKind("Query"))},
+				},
+				Type: corev1.SecretTypeOpaque,
+			}
+		}
+		mutate(secret)
+
+		// The API server would reject the write, so fail with the reason instead
+		if size := secretDataSize(secret); size > maxCheckpointSecretSize {
+			return fmt.Errorf("checkpoints of query %s need %d bytes, more than the %d bytes a Secret can hold", s.query.Name, size, maxCheckpointSecretSize)
+		}
+
+		switch {
+		case current == nil && len(secret.Data) == 0:
+			return nil
+		case current == nil:
+			err = s.client.Create(ctx, secret)
+		default:
+			err = s.client.Update(ctx, secret)
+		}
+		if err != nil {
+			// Read the Secret again before retrying, another writer may have changed it
+			s.current = nil
+			return err
+		}
+		s.current = secret
+		return nil
+	})
+}
+
+func secretDataSize(secret *corev1.Secret) int {
+	size := 0
+	for key, value := range secret.Data {
+		size += len(key) + len(value)
+	}
+	return size
+}
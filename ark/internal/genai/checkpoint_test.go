@@ -0,0 +1,327 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/mock"
+)
+
+// memoryCheckpointStore keeps checkpoints in memory and records every save
+type memoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]*TeamCheckpoint
+	saved       []TeamCheckpoint
+	saveErr     error
+}
+
+func newMemoryCheckpointStore() *memoryCheckpointStore {
+	return &memoryCheckpointStore{checkpoints: make(map[string]*TeamCheckpoint)}
+}
+
+func (s *memoryCheckpointStore) Load(ctx context.Context, key string) (*TeamCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[key], nil
+}
+
+func (s *memoryCheckpointStore) Save(ctx context.Context, key string, checkpoint *TeamCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.checkpoints[key] = checkpoint
+	s.saved = append(s.saved, *checkpoint)
+	return nil
+}
+
+func (s *memoryCheckpointStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, key)
+	return nil
+}
+
+// cancellingTeamMember simulates the controller shutting down while the member runs
+type cancellingTeamMember struct {
+	name   string
+	cancel context.CancelFunc
+}
+
+func (m *cancellingTeamMember) GetName() string        { return m.name }
+func (m *cancellingTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *cancellingTeamMember) GetDescription() string { return "" }
+
+func (m *cancellingTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	m.cancel()
+	return nil, context.Canceled
+}
+
+// failureRecordingTeamRecorder records the operations that failed
+type failureRecordingTeamRecorder struct {
+	eventing.TeamRecorder
+	mu     sync.Mutex
+	failed []string
+}
+
+func (r *failureRecordingTeamRecorder) Fail(ctx context.Context, operation, message string, err error, data map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, operation+": "+message)
+}
+
+func newCheckpointTestTeam(strategy string, members ...TeamMember) (*Team, *mock.MockTeamRecorder) {
+	recorder := mock.NewTeamRecorder()
+	return &Team{
+		Name:              "research",
+		Namespace:         "default",
+		Strategy:          strategy,
+		Members:           members,
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}, recorder
+}
+
+func TestTeamCheckpoints(t *testing.T) {
+	t.Run("saves progress after every turn and clears it when the team finishes", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		team, _ := newCheckpointTestTeam("sequential",
+			&scriptedTeamMember{name: "researcher", responses: []string{"sources"}},
+			&scriptedTeamMember{name: "writer", responses: []string{"report"}})
+
+		_, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, store.saved, 2)
+		require.Equal(t, 1, store.saved[0].Turn)
+		require.Len(t, store.saved[0].Messages, 1)
+		require.Equal(t, 2, store.saved[1].Turn)
+		require.Len(t, store.saved[1].Messages, 2)
+		require.Empty(t, store.checkpoints)
+	})
+
+	t.Run("keeps the checkpoint when the execution is interrupted", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		ctx, cancel := context.WithCancel(WithCheckpointStore(context.Background(), store))
+		defer cancel()
+		team, _ := newCheckpointTestTeam("sequential",
+			&scriptedTeamMember{name: "researcher", responses: []string{"sources"}},
+			&cancellingTeamMember{name: "writer", cancel: cancel})
+
+		_, err := team.Execute(ctx, NewUserMessage("go"), nil, nil, nil)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 1, store.checkpoints["team.research"].Turn)
+	})
+
+	t.Run("resumes a sequential team after the last completed turn", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "sequential", Turn: 1, Messages: []Message{NewAssistantMessage("sources")}}
+		researcher := &scriptedTeamMember{name: "researcher", responses: []string{"other sources"}}
+		writer := &scriptedTeamMember{name: "writer", responses: []string{"report"}}
+		team, recorder := newCheckpointTestTeam("sequential", researcher, writer)
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, researcher.inputs, "completed turns are not repeated")
+		require.Equal(t, "sources", messageText(writer.histories[0][0]))
+		require.Len(t, result.Messages, 2)
+		require.Equal(t, "report", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, 1, recorder.Tracer.FindSpan("team.execution").Attributes["team.resumed_from_turn"])
+	})
+
+	t.Run("resumes a round-robin team with the next member", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "round-robin", Turn: 3, Messages: []Message{
+			NewAssistantMessage("one"), NewAssistantMessage("two"), NewAssistantMessage("three"),
+		}}
+		first := &scriptedTeamMember{name: "first", responses: []string{"five"}}
+		second := &scriptedTeamMember{name: "second", responses: []string{"four"}}
+		team, _ := newCheckpointTestTeam("round-robin", first, second)
+		maxTurns := 5
+		team.MaxTurns = &maxTurns
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, second.inputs, 1)
+		require.Len(t, first.inputs, 1)
+		require.Len(t, result.Messages, 5)
+		require.Equal(t, "five", ExtractLastAssistantMessageContent(result.Messages))
+	})
+
+	t.Run("resumes a handoff team with the member control was handed to", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "handoff", Turn: 1, Member: "billing", HandoffChain: []string{"triage", "billing"}, Messages: []Message{NewAssistantMessage("transferring")}}
+		triage := &scriptedTeamMember{name: "triage", responses: []string{"hello"}}
+		billing := &scriptedTeamMember{name: "billing", responses: []string{"refund issued"}}
+		team, recorder := newCheckpointTestTeam("handoff", triage, billing)
+		maxTurns := 5
+		team.MaxTurns = &maxTurns
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("refund"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, triage.inputs)
+		require.Equal(t, "refund issued", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, "triage -> billing", recorder.Tracer.FindSpan("team.execution").Attributes["team.handoff_chain"])
+	})
+
+	t.Run("ignores a handoff checkpoint without a member", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "handoff", Turn: 1, HandoffChain: []string{"triage"}, Messages: []Message{NewAssistantMessage("stale")}}
+		triage := &scriptedTeamMember{name: "triage", responses: []string{"hello"}}
+		team, _ := newCheckpointTestTeam("handoff", triage)
+		maxTurns := 5
+		team.MaxTurns = &maxTurns
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("refund"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, triage.inputs, 1)
+		require.Equal(t, "hello", ExtractLastAssistantMessageContent(result.Messages))
+		require.Len(t, result.Messages, 1)
+	})
+
+	t.Run("ignores a checkpoint of another strategy", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "round-robin", Turn: 1, Messages: []Message{NewAssistantMessage("stale")}}
+		researcher := &scriptedTeamMember{name: "researcher", responses: []string{"sources"}}
+		team, _ := newCheckpointTestTeam("sequential", researcher)
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, researcher.inputs, 1)
+		require.Len(t, result.Messages, 1)
+	})
+
+	t.Run("reports a checkpoint that cannot be saved without failing the team", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.saveErr = errors.New("too large")
+		team, _ := newCheckpointTestTeam("sequential", &scriptedTeamMember{name: "researcher", responses: []string{"sources"}})
+		events := &failureRecordingTeamRecorder{TeamRecorder: eventnoop.NewProvider().TeamRecorder()}
+		team.eventingRecorder = events
+
+		result, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "sources", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, []string{"TeamCheckpoint: Failed to save checkpoint of team research: too large"}, events.failed)
+	})
+
+	for _, strategy := range []string{"parallel", "vote"} {
+		t.Run("does not checkpoint the "+strategy+" strategy", func(t *testing.T) {
+			store := newMemoryCheckpointStore()
+			store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: strategy, Turn: 1, Messages: []Message{NewAssistantMessage("stale")}}
+			researcher := &scriptedTeamMember{name: "researcher", responses: []string{"sources"}}
+			writer := &scriptedTeamMember{name: "writer", responses: []string{"sources"}}
+			team, _ := newCheckpointTestTeam(strategy, researcher, writer)
+
+			_, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+			require.NoError(t, err)
+			require.Empty(t, store.saved)
+			require.Len(t, researcher.inputs, 1, "members run again")
+			require.Len(t, writer.inputs, 1)
+		})
+	}
+
+	t.Run("does not checkpoint the plan strategy", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		store.checkpoints["team.research"] = &TeamCheckpoint{Strategy: "plan", Turn: 2, Messages: []Message{NewAssistantMessage("stale")}}
+		planner := &scriptedTeamMember{name: "planner", responses: []string{`{"steps": [{"id": "write", "member": "writer", "task": "Write"}]}`}}
+		writer := &scriptedTeamMember{name: "writer", responses: []string{"report"}}
+		team, _ := newCheckpointTestTeam("plan", planner, writer)
+		team.Plan = &arkv1alpha1.TeamPlanSpec{Planner: "planner"}
+
+		_, err := team.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, store.saved)
+		require.Len(t, planner.inputs, 1, "the planner runs again")
+		require.Len(t, writer.inputs, 1)
+	})
+
+	t.Run("keys checkpoints of nested teams by call chain", func(t *testing.T) {
+		store := newMemoryCheckpointStore()
+		inner, _ := newCheckpointTestTeam("sequential", &scriptedTeamMember{name: "writer", responses: []string{"report"}})
+		inner.Name = "writers"
+		outer, _ := newCheckpointTestTeam("sequential", inner)
+
+		_, err := outer.Execute(WithCheckpointStore(context.Background(), store), NewUserMessage("go"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, store.saved, 2)
+		require.Equal(t, "team.research_team.writers", checkpointKey([]CallFrame{
+			{Kind: MemberTypeTeam, Name: "research"}, {Kind: MemberTypeTeam, Name: "writers"},
+		}))
+	})
+}
+
+func TestCheckpointKey(t *testing.T) {
+	require.Equal(t, "agent.planner_team.research", checkpointKey([]CallFrame{
+		{Kind: MemberTypeAgent, Name: "planner"}, {Kind: MemberTypeTeam, Name: "research"},
+	}))
+
+	long := make([]CallFrame, 20)
+	for i := range long {
+		long[i] = CallFrame{Kind: MemberTypeTeam, Name: strings.Repeat("x", 20)}
+	}
+	key := checkpointKey(long)
+	require.True(t, strings.HasPrefix(key, "chain."))
+	require.LessOrEqual(t, len(key), maxCheckpointKeyLength)
+}
+
+func TestSecretCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "weekly-report", Namespace: "default", UID: "query-uid"}}
+	k8sClient := newCallChainTestClient(query)
+	store := NewSecretCheckpointStore(k8sClient, query)
+
+	hasCheckpoints, err := store.HasCheckpoints(ctx)
+	require.NoError(t, err)
+	require.False(t, hasCheckpoints)
+
+	checkpoint, err := store.Load(ctx, "team.research")
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+
+	require.NoError(t, store.Save(ctx, "team.research", &TeamCheckpoint{Strategy: "sequential", Turn: 1, Messages: []Message{NewAssistantMessage("sources")}}))
+	require.NoError(t, store.Save(ctx, "team.research", &TeamCheckpoint{Strategy: "sequential", Turn: 2, Messages: []Message{NewAssistantMessage("sources"), NewAssistantMessage("report")}}))
+
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "weekly-report-checkpoint", Namespace: "default"}, secret))
+	require.Equal(t, corev1.SecretTypeOpaque, secret.Type)
+	require.Len(t, secret.OwnerReferences, 1)
+	require.Equal(t, "Query", secret.OwnerReferences[0].Kind)
+	require.Equal(t, "weekly-report", secret.OwnerReferences[0].Name)
+	require.Contains(t, string(secret.Data["team.research"]), `"turn":2`)
+
+	// A checkpoint over the size limit is refused and the previous one is kept
+	large := &TeamCheckpoint{Strategy: "sequential", Turn: 3, Messages: []Message{NewAssistantMessage(strings.Repeat("x", maxCheckpointSecretSize))}}
+	require.ErrorContains(t, store.Save(ctx, "team.research", large), "more than the")
+
+	// A new store, as after a controller restart, reads the checkpoint back from the cluster
+	restarted := NewSecretCheckpointStore(k8sClient, query)
+	hasCheckpoints, err = restarted.HasCheckpoints(ctx)
+	require.NoError(t, err)
+	require.True(t, hasCheckpoints)
+	checkpoint, err = restarted.Load(ctx, "team.research")
+	require.NoError(t, err)
+	require.Equal(t, 2, checkpoint.Turn)
+	require.Equal(t, "report", ExtractLastAssistantMessageContent(checkpoint.Messages))
+
+	require.NoError(t, restarted.Delete(ctx, "team.research"))
+	hasCheckpoints, err = restarted.HasCheckpoints(ctx)
+	require.NoError(t, err)
+	require.False(t, hasCheckpoints)
+
+	require.NoError(t, restarted.Clear(ctx))
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: "weekly-report-checkpoint", Namespace: "default"}, secret)
+	require.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, restarted.Clear(ctx), "clearing twice is not an error")
+}
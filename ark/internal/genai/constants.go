@@ -7,15 +7,54 @@ const (
 
 // Model type constants
 const (
-	ModelTypeAzure   = "azure"
-	ModelTypeOpenAI  = "openai"
-	ModelTypeBedrock = "bedrock"
+	ModelTypeAzure     = "azure"
+	ModelTypeOpenAI    = "openai"
+	ModelTypeBedrock   = "bedrock"
+	ModelTypeAnthropic = "anthropic"
+	ModelTypeGemini    = "gemini"
+	ModelTypeRouter    = "router"
+)
+
+// Model task constants
+const (
+	ModelTaskCompletion = "completion"
+	ModelTaskEmbedding  = "embedding"
+)
+
+// Model API constants for the OpenAI and Azure model types
+const (
+	ModelAPIChat      = "chat"
+	ModelAPIResponses = "responses"
+)
+
+// Model router strategy constants
+const (
+	RouterStrategyWeighted = "weighted"
+	RouterStrategyLatency  = "latency"
+)
+
+// Model capability constants, as listed in the routes of routers
+const (
+	CapabilityToolCalling      = "toolCalling"
+	CapabilityStructuredOutput = "structuredOutput"
+	CapabilityStreaming        = "streaming"
+	CapabilityVision           = "vision"
+)
+
+// Model probe mode constants
+const (
+	ProbeModeCompletion = "completion"
+	ProbeModeListModels = "listModels"
+	ProbeModeDisabled   = "disabled"
 )
 
 // Agent tool type constants
 const (
-	AgentToolTypeBuiltIn = "built-in"
-	AgentToolTypeCustom  = "custom"
+	AgentToolTypeBuiltIn  = "built-in"
+	AgentToolTypeCustom   = "custom"
+	AgentToolTypeMCP      = "mcp"
+	AgentToolTypeSelector = "selector"
+	AgentToolTypeToolSet  = "toolset"
 )
 
 // Role constants for execution engine messages
@@ -35,9 +74,25 @@ const (
 	ToolTypeBuiltin = "builtin"
 )
 
+// Tool output post-processing step type constants
+const (
+	PostProcessJQ       = "jq"
+	PostProcessTemplate = "template"
+	PostProcessRegex    = "regex"
+	PostProcessTable    = "table"
+	PostProcessRedact   = "redact"
+)
+
+// Table format constants for table post-processing steps
+const (
+	TableFormatMarkdown = "markdown"
+	TableFormatCSV      = "csv"
+)
+
 // Team member type constants
 const (
 	MemberTypeAgent = "agent"
+	MemberTypeTeam  = "team"
 )
 
 // Built-in tool name constants
@@ -45,3 +100,6 @@ const (
 	BuiltinToolNoop      = "noop"
 	BuiltinToolTerminate = "terminate"
 )
+
+// HandoffToolPrefix prefixes the transfer tools given to members of handoff teams
+const HandoffToolPrefix = "transfer_to_"
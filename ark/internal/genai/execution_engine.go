@@ -53,6 +53,8 @@ type ExecutionEngineModel struct {
 	Name   string         `json:"name"`
 	Type   string         `json:"type"`
 	Config map[string]any `json:"config,omitempty"`
+	// Generation holds the generation parameters of the model with the agent overrides applied
+	Generation *GenerationParameters `json:"generation,omitempty"`
 }
 
 // Parameter represents a parameter for template processing
@@ -286,9 +288,10 @@ func buildAgentConfig(agent *Agent) (AgentConfig, error) {
 		Description: agent.Description,
 		Parameters:  parameters,
 		Model: ExecutionEngineModel{
-			Name:   agent.Model.Model,
-			Type:   agent.Model.Type,
-			Config: modelConfig,
+			Name:       agent.Model.Model,
+			Type:       agent.Model.Type,
+			Config:     modelConfig,
+			Generation: agent.Model.Generation.withOverrides(agent.Generation),
 		},
 		OutputSchema: agent.OutputSchema,
 	}, nil
@@ -318,6 +321,10 @@ func buildModelConfig(model *Model) map[string]any {
 			modelConfig["openai"] = configProvider.BuildConfig()
 		case ModelTypeBedrock:
 			modelConfig["bedrock"] = configProvider.BuildConfig()
+		case ModelTypeAnthropic:
+			modelConfig["anthropic"] = configProvider.BuildConfig()
+		case ModelTypeGemini:
+			modelConfig["gemini"] = configProvider.BuildConfig()
 		}
 	}
 
@@ -1,6 +1,12 @@
 package genai
 
+import arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+
 type ExecutionResult struct {
 	Messages    []Message
 	A2AResponse *A2AResponse
+	// TerminationReason explains why a team stopped early, if it did
+	TerminationReason string
+	// Vote holds the candidates and the winner of a vote team
+	Vote *arkv1alpha1.VoteResult
 }
@@ -0,0 +1,157 @@
+package genai
+
+import (
+	"fmt"
+	"strconv"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// GenerationParameters are the parsed generation settings of a model or agent. Fields that are
+// nil or empty are not sent, so the provider default or the matching property applies.
+type GenerationParameters struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	MaxTokens        *int64   `json:"maxTokens,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+	ReasoningEffort  string   `json:"reasoningEffort,omitempty"`
+	PresencePenalty  *float64 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequencyPenalty,omitempty"`
+}
+
+// ParseGenerationParameters parses the generation settings of a Model or Agent and checks their ranges
+func ParseGenerationParameters(spec *arkv1alpha1.GenerationParameters) (*GenerationParameters, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	params := &GenerationParameters{
+		MaxTokens:       spec.MaxTokens,
+		Stop:            spec.Stop,
+		Seed:            spec.Seed,
+		ReasoningEffort: spec.ReasoningEffort,
+	}
+
+	decimals := []struct {
+		name     string
+		value    *string
+		min, max float64
+		target   **float64
+	}{
+		{"temperature", spec.Temperature, 0, 2, &params.Temperature},
+		{"topP", spec.TopP, 0, 1, &params.TopP},
+		{"presencePenalty", spec.PresencePenalty, -2, 2, &params.PresencePenalty},
+		{"frequencyPenalty", spec.FrequencyPenalty, -2, 2, &params.FrequencyPenalty},
+	}
+	for _, decimal := range decimals {
+		if decimal.value == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(*decimal.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number: %q", decimal.name, *decimal.value)
+		}
+		if value < decimal.min || value > decimal.max {
+			return nil, fmt.Errorf("%s must be between %g and %g, got %g", decimal.name, decimal.min, decimal.max, value)
+		}
+		*decimal.target = &value
+	}
+
+	if params.MaxTokens != nil && *params.MaxTokens < 1 {
+		return nil, fmt.Errorf("maxTokens must be at least 1, got %d", *params.MaxTokens)
+	}
+	for i, stop := range params.Stop {
+		if stop == "" {
+			return nil, fmt.Errorf("stop[%d] must not be empty", i)
+		}
+	}
+
+	return params, nil
+}
+
+// withOverrides returns the parameters with every field that is set in overrides replaced, so
+// agent settings take precedence over the settings of their model
+func (g *GenerationParameters) withOverrides(overrides *GenerationParameters) *GenerationParameters {
+	if overrides == nil {
+		return g
+	}
+	if g == nil {
+		return overrides
+	}
+
+	merged := *g
+	if overrides.Temperature != nil {
+		merged.Temperature = overrides.Temperature
+	}
+	if overrides.TopP != nil {
+		merged.TopP = overrides.TopP
+	}
+	if overrides.MaxTokens != nil {
+		merged.MaxTokens = overrides.MaxTokens
+	}
+	if len(overrides.Stop) > 0 {
+		merged.Stop = overrides.Stop
+	}
+	if overrides.Seed != nil {
+		merged.Seed = overrides.Seed
+	}
+	if overrides.ReasoningEffort != "" {
+		merged.ReasoningEffort = overrides.ReasoningEffort
+	}
+	if overrides.PresencePenalty != nil {
+		merged.PresencePenalty = overrides.PresencePenalty
+	}
+	if overrides.FrequencyPenalty != nil {
+		merged.FrequencyPenalty = overrides.FrequencyPenalty
+	}
+	return &merged
+}
+
+// generationFieldNames names the request fields a provider uses for the generation parameters,
+// parameters without a name are not supported by the provider and are not sent
+type generationFieldNames struct {
+	temperature      string
+	topP             string
+	maxTokens        string
+	stop             string
+	seed             string
+	presencePenalty  string
+	frequencyPenalty string
+}
+
+// fields returns the parameters that are set as request fields named by names
+func (g *GenerationParameters) fields(names generationFieldNames) map[string]any {
+	fields := make(map[string]any)
+	if g == nil {
+		return fields
+	}
+
+	set := func(name string, value any) {
+		if name != "" {
+			fields[name] = value
+		}
+	}
+	if g.Temperature != nil {
+		set(names.temperature, *g.Temperature)
+	}
+	if g.TopP != nil {
+		set(names.topP, *g.TopP)
+	}
+	if g.MaxTokens != nil {
+		set(names.maxTokens, *g.MaxTokens)
+	}
+	if len(g.Stop) > 0 {
+		set(names.stop, g.Stop)
+	}
+	if g.Seed != nil {
+		set(names.seed, *g.Seed)
+	}
+	if g.PresencePenalty != nil {
+		set(names.presencePenalty, *g.PresencePenalty)
+	}
+	if g.FrequencyPenalty != nil {
+		set(names.frequencyPenalty, *g.FrequencyPenalty)
+	}
+	return fields
+}
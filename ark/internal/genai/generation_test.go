@@ -0,0 +1,134 @@
+package genai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestParseGenerationParameters(t *testing.T) {
+	temperature, topP, penalty := "0.7", "0.95", "-0.5"
+	maxTokens, seed := int64(512), int64(42)
+
+	params, err := ParseGenerationParameters(&arkv1alpha1.GenerationParameters{
+		Temperature:      &temperature,
+		TopP:             &topP,
+		MaxTokens:        &maxTokens,
+		Stop:             []string{"END"},
+		Seed:             &seed,
+		ReasoningEffort:  "high",
+		FrequencyPenalty: &penalty,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0.7, *params.Temperature)
+	require.Equal(t, 0.95, *params.TopP)
+	require.Equal(t, int64(512), *params.MaxTokens)
+	require.Equal(t, []string{"END"}, params.Stop)
+	require.Equal(t, int64(42), *params.Seed)
+	require.Equal(t, "high", params.ReasoningEffort)
+	require.Nil(t, params.PresencePenalty)
+	require.Equal(t, -0.5, *params.FrequencyPenalty)
+
+	params, err = ParseGenerationParameters(nil)
+	require.NoError(t, err)
+	require.Nil(t, params)
+
+	tooHigh, notNumber, zero := "1.5", "warm", int64(0)
+	for _, tc := range []struct {
+		spec *arkv1alpha1.GenerationParameters
+		err  string
+	}{
+		{&arkv1alpha1.GenerationParameters{TopP: &tooHigh}, "topP must be between 0 and 1, got 1.5"},
+		{&arkv1alpha1.GenerationParameters{Temperature: &notNumber}, `temperature must be a number: "warm"`},
+		{&arkv1alpha1.GenerationParameters{MaxTokens: &zero}, "maxTokens must be at least 1"},
+		{&arkv1alpha1.GenerationParameters{Stop: []string{""}}, "stop[0] must not be empty"},
+	} {
+		_, err := ParseGenerationParameters(tc.spec)
+		require.ErrorContains(t, err, tc.err)
+	}
+}
+
+func TestGenerationOverrides(t *testing.T) {
+	modelTemperature, agentTemperature := 1.0, 0.0
+	maxTokens := int64(2048)
+	model := &GenerationParameters{Temperature: &modelTemperature, MaxTokens: &maxTokens, Stop: []string{"END"}}
+	agent := &GenerationParameters{Temperature: &agentTemperature, ReasoningEffort: "low"}
+
+	merged := model.withOverrides(agent)
+	require.Equal(t, 0.0, *merged.Temperature)
+	require.Equal(t, int64(2048), *merged.MaxTokens)
+	require.Equal(t, []string{"END"}, merged.Stop)
+	require.Equal(t, "low", merged.ReasoningEffort)
+	require.Equal(t, 1.0, *model.Temperature, "the model parameters must not change")
+
+	require.Same(t, model, model.withOverrides(nil))
+	require.Same(t, agent, (*GenerationParameters)(nil).withOverrides(agent))
+}
+
+func TestApplyGenerationToParams(t *testing.T) {
+	temperature, maxTokens := 0.2, int64(300)
+	params := openai.ChatCompletionNewParams{Model: "gpt-4o"}
+	applyPropertiesToParams(map[string]string{"temperature": "0.9", "max_tokens": "100", "top_p": "0.5"}, &params)
+	applyGenerationToParams(&GenerationParameters{Temperature: &temperature, MaxTokens: &maxTokens, Stop: []string{"END"}}, &params)
+
+	body, err := json.Marshal(params)
+	require.NoError(t, err)
+	var request map[string]any
+	require.NoError(t, json.Unmarshal(body, &request))
+	require.Equal(t, 0.2, request["temperature"])
+	require.Equal(t, 0.5, request["top_p"])
+	require.Equal(t, float64(300), request["max_completion_tokens"])
+	require.NotContains(t, request, "max_tokens")
+	require.Equal(t, []any{"END"}, request["stop"])
+	require.NotContains(t, request, "n")
+}
+
+func TestProviderGenerationMappings(t *testing.T) {
+	temperature, topP, penalty := 0.3, 0.8, 0.5
+	maxTokens, seed := int64(256), int64(7)
+	generation := &GenerationParameters{
+		Temperature:     &temperature,
+		TopP:            &topP,
+		MaxTokens:       &maxTokens,
+		Stop:            []string{"END"},
+		Seed:            &seed,
+		ReasoningEffort: "medium",
+		PresencePenalty: &penalty,
+	}
+
+	anthropic := &AnthropicProvider{Model: "claude-sonnet-4-5", Properties: map[string]string{"temperature": "1"}}
+	anthropic.SetGenerationParameters(generation)
+	body, err := anthropic.buildRequest([]Message{NewUserMessage("hi")}, false)
+	require.NoError(t, err)
+	var request map[string]any
+	require.NoError(t, json.Unmarshal(body, &request))
+	require.Equal(t, 0.3, request["temperature"])
+	require.Equal(t, 0.8, request["top_p"])
+	require.Equal(t, float64(256), request["max_tokens"])
+	require.Equal(t, []any{"END"}, request["stop_sequences"])
+	require.NotContains(t, request, "seed")
+	require.NotContains(t, request, "presence_penalty")
+
+	gemini := &GeminiProvider{Model: "gemini-2.5-flash", generation: generation}
+	config := gemini.generationConfig(1)
+	require.Equal(t, 0.3, config["temperature"])
+	require.Equal(t, 0.8, config["topP"])
+	require.Equal(t, int64(256), config["maxOutputTokens"])
+	require.Equal(t, []string{"END"}, config["stopSequences"])
+	require.Equal(t, int64(7), config["seed"])
+	require.Equal(t, 0.5, config["presencePenalty"])
+	require.Equal(t, map[string]any{"thinkingBudget": 8192}, config["thinkingConfig"])
+
+	bedrock := NewBedrockModel("anthropic.claude-3-haiku", "us-east-1", "", "", "", "", "", map[string]string{"max_tokens": "100", "top_k": "40"})
+	bedrock.SetGenerationParameters(generation)
+	inference := bedrock.inferenceConfig()
+	require.Equal(t, int32(256), *inference.MaxTokens)
+	require.Equal(t, float32(0.3), *inference.Temperature)
+	require.Equal(t, float32(0.8), *inference.TopP)
+	require.Equal(t, []string{"END"}, inference.StopSequences)
+	require.Equal(t, map[string]any{"top_k": float64(40)}, bedrock.additionalFields())
+}
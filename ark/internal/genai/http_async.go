@@ -0,0 +1,245 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	defaultAsyncPollInterval    = 2 * time.Second
+	defaultAsyncMaxPollInterval = 30 * time.Second
+	maxAsyncStatusRedirects     = 10
+)
+
+// pollAsyncResult follows a long-running HTTP operation until its status satisfies the success
+// or failure condition. If the initial response does not point to a status URL, it is returned
+// as the result unchanged. Polling stops when the context (bounded by the query timeout) ends.
+func (h *HTTPExecutor) pollAsyncResult(ctx context.Context, call ToolCall, asyncSpec *arkv1alpha1.HTTPAsyncSpec, httpClient *http.Client, requestURL *url.URL, resp *http.Response, body []byte, headers http.Header) (ToolResult, error) {
+	log := logf.FromContext(ctx).WithValues("tool", h.ToolName, "toolID", call.ID)
+
+	statusURL, err := resolveAsyncStatusURL(asyncSpec, requestURL, resp, body)
+	if err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: fmt.Sprintf("failed to resolve status URL: %v", err),
+		}, fmt.Errorf("failed to resolve status URL: %w", err)
+	}
+	if statusURL == "" {
+		// The operation completed synchronously
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Content: string(body)}, nil
+	}
+
+	interrupted := func(checks int) (ToolResult, error) {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: fmt.Sprintf("operation did not complete after %d status checks: %v", checks, ctx.Err()),
+		}, fmt.Errorf("polling %s: %w", statusURL, ctx.Err())
+	}
+
+	interval, maxInterval := asyncPollIntervals(asyncSpec)
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return interrupted(attempt - 1)
+		case <-time.After(interval):
+		}
+
+		operationData := map[string]string{
+			"toolName":  h.ToolName,
+			"toolId":    call.ID,
+			"statusURL": statusURL,
+			"attempt":   strconv.Itoa(attempt),
+		}
+		pollCtx := h.startPollEvent(ctx, operationData)
+
+		statusCode, statusBody, err := fetchAsyncStatus(pollCtx, httpClient, requestURL, statusURL, headers)
+		if err != nil {
+			h.failPollEvent(pollCtx, err, operationData)
+			// The context can end while a status check is in flight
+			if ctx.Err() != nil {
+				return interrupted(attempt - 1)
+			}
+			return ToolResult{
+				ID:    call.ID,
+				Name:  call.Function.Name,
+				Error: fmt.Sprintf("failed to check operation status: %v", err),
+			}, fmt.Errorf("failed to check operation status: %w", err)
+		}
+		operationData["statusCode"] = strconv.Itoa(statusCode)
+
+		done, err := evaluateAsyncStatus(asyncSpec, statusCode, statusBody)
+		if err != nil {
+			h.failPollEvent(pollCtx, err, operationData)
+			return ToolResult{
+				ID:      call.ID,
+				Name:    call.Function.Name,
+				Content: statusBody,
+				Error:   err.Error(),
+			}, err
+		}
+		h.completePollEvent(pollCtx, done, operationData)
+
+		if done {
+			log.Info("HTTP operation completed", "statusURL", statusURL, "attempts", attempt)
+			return ToolResult{ID: call.ID, Name: call.Function.Name, Content: statusBody}, nil
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// resolveAsyncStatusURL returns the URL to poll, or an empty string if the response is final. When
+// the status URL path finds nothing, the Location header of a 202 response is used instead.
+func resolveAsyncStatusURL(asyncSpec *arkv1alpha1.HTTPAsyncSpec, requestURL *url.URL, resp *http.Response, body []byte) (string, error) {
+	var location string
+	if asyncSpec.StatusURLPath != "" {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			if resp.StatusCode != http.StatusAccepted || resp.Header.Get("Location") == "" {
+				return "", fmt.Errorf("response is not JSON, cannot evaluate status URL path '%s': %w", asyncSpec.StatusURLPath, err)
+			}
+		} else {
+			results, err := runJQ(asyncSpec.StatusURLPath, data)
+			if err != nil {
+				return "", err
+			}
+			if len(results) > 0 {
+				if value, ok := results[0].(string); ok {
+					location = value
+				}
+			}
+		}
+	}
+	if location == "" && resp.StatusCode == http.StatusAccepted {
+		location = resp.Header.Get("Location")
+	}
+
+	if location == "" {
+		return "", nil
+	}
+
+	statusURL, err := requestURL.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid status URL '%s': %w", location, err)
+	}
+	return statusURL.String(), nil
+}
+
+// fetchAsyncStatus checks the status of the operation. The headers of the tool request carry
+// credentials, so they are only sent to a status URL with the same scheme and host.
+func fetchAsyncStatus(ctx context.Context, httpClient *http.Client, requestURL *url.URL, statusURL string, headers http.Header) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if sameOrigin(req.URL, requestURL) {
+		req.Header = headers.Clone()
+	}
+
+	// Redirects to another host must not carry the credentials either
+	pollClient := *httpClient
+	pollClient.CheckRedirect = func(redirect *http.Request, via []*http.Request) error {
+		if len(via) >= maxAsyncStatusRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxAsyncStatusRedirects)
+		}
+		if !sameOrigin(redirect.URL, requestURL) {
+			redirect.Header = make(http.Header)
+		}
+		return nil
+	}
+
+	resp, err := pollClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, "", fmt.Errorf("HTTP error %d: %s (URL: %s)", resp.StatusCode, resp.Status, statusURL)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host
+}
+
+// evaluateAsyncStatus reports whether the operation has completed, returning an error when the
+// failure condition matches
+func evaluateAsyncStatus(asyncSpec *arkv1alpha1.HTTPAsyncSpec, statusCode int, body string) (bool, error) {
+	if asyncSpec.FailureCondition != "" {
+		failed, err := evaluateJQCondition(asyncSpec.FailureCondition, body)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate failure condition: %w", err)
+		}
+		if failed {
+			return false, fmt.Errorf("operation failed: failure condition '%s' matched", asyncSpec.FailureCondition)
+		}
+	}
+
+	if asyncSpec.SuccessCondition == "" {
+		return statusCode != http.StatusAccepted, nil
+	}
+
+	succeeded, err := evaluateJQCondition(asyncSpec.SuccessCondition, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate success condition: %w", err)
+	}
+	return succeeded, nil
+}
+
+func asyncPollIntervals(asyncSpec *arkv1alpha1.HTTPAsyncSpec) (time.Duration, time.Duration) {
+	interval := defaultAsyncPollInterval
+	if asyncSpec.PollInterval != nil && asyncSpec.PollInterval.Duration > 0 {
+		interval = asyncSpec.PollInterval.Duration
+	}
+	maxInterval := defaultAsyncMaxPollInterval
+	if asyncSpec.MaxPollInterval != nil && asyncSpec.MaxPollInterval.Duration > 0 {
+		maxInterval = asyncSpec.MaxPollInterval.Duration
+	}
+	return interval, max(interval, maxInterval)
+}
+
+func (h *HTTPExecutor) startPollEvent(ctx context.Context, operationData map[string]string) context.Context {
+	if h.Eventing == nil {
+		return ctx
+	}
+	return h.Eventing.Start(ctx, "HTTPToolPoll", fmt.Sprintf("Checking status of tool %s (attempt %s)", h.ToolName, operationData["attempt"]), operationData)
+}
+
+func (h *HTTPExecutor) completePollEvent(ctx context.Context, done bool, operationData map[string]string) {
+	if h.Eventing == nil {
+		return
+	}
+	message := "Operation still in progress"
+	if done {
+		message = "Operation completed"
+	}
+	h.Eventing.Complete(ctx, "HTTPToolPoll", message, operationData)
+}
+
+func (h *HTTPExecutor) failPollEvent(ctx context.Context, err error, operationData map[string]string) {
+	if h.Eventing == nil {
+		return
+	}
+	h.Eventing.Fail(ctx, "HTTPToolPoll", fmt.Sprintf("Status check failed: %v", err), err, operationData)
+}
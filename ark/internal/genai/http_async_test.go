@@ -0,0 +1,231 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newAsyncHTTPExecutor(serverURL string, async *arkv1alpha1.HTTPAsyncSpec, headers ...arkv1alpha1.Header) *HTTPExecutor {
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "async-tool", Namespace: "default"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type: ToolTypeHTTP,
+			HTTP: &arkv1alpha1.HTTPSpec{
+				URL:     serverURL + "/jobs",
+				Method:  "POST",
+				Headers: headers,
+				Async:   async,
+			},
+		},
+	}
+	return &HTTPExecutor{
+		K8sClient:     setupTestClientForTools([]client.Object{tool}),
+		ToolName:      tool.Name,
+		ToolNamespace: tool.Namespace,
+	}
+}
+
+func fastPolling() *arkv1alpha1.HTTPAsyncSpec {
+	return &arkv1alpha1.HTTPAsyncSpec{
+		PollInterval:    &metav1.Duration{Duration: time.Millisecond},
+		MaxPollInterval: &metav1.Duration{Duration: 5 * time.Millisecond},
+	}
+}
+
+func TestHTTPExecutorAsync(t *testing.T) {
+	asyncCall := ToolCall{
+		ID:       "call-1",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "async-tool"},
+		Type:     "function",
+	}
+
+	t.Run("polls location header until operation completes", func(t *testing.T) {
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/jobs":
+				w.Header().Set("Location", "/jobs/42")
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"state":"queued"}`))
+			case "/jobs/42":
+				if polls.Add(1) < 3 {
+					w.WriteHeader(http.StatusAccepted)
+					_, _ = w.Write([]byte(`{"state":"running"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"state":"done","result":"ok"}`))
+			}
+		}))
+		defer server.Close()
+
+		executor := newAsyncHTTPExecutor(server.URL, fastPolling())
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, `{"state":"done","result":"ok"}`, result.Content)
+		require.Equal(t, int32(3), polls.Load())
+	})
+
+	t.Run("uses status url path and success condition", func(t *testing.T) {
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/jobs":
+				_, _ = w.Write([]byte(`{"links":{"status":"/status/7"}}`))
+			case "/status/7":
+				if polls.Add(1) < 2 {
+					_, _ = w.Write([]byte(`{"state":"running"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"state":"succeeded"}`))
+			}
+		}))
+		defer server.Close()
+
+		async := fastPolling()
+		async.StatusURLPath = ".links.status"
+		async.SuccessCondition = `.state == "succeeded"`
+
+		executor := newAsyncHTTPExecutor(server.URL, async)
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, `{"state":"succeeded"}`, result.Content)
+		require.Equal(t, int32(2), polls.Load())
+	})
+
+	t.Run("reports failure condition as tool error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/jobs" {
+				w.Header().Set("Location", "/jobs/1")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_, _ = w.Write([]byte(`{"state":"failed","reason":"quota exceeded"}`))
+		}))
+		defer server.Close()
+
+		async := fastPolling()
+		async.FailureCondition = `.state == "failed"`
+
+		executor := newAsyncHTTPExecutor(server.URL, async)
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.Error(t, err)
+		require.Contains(t, result.Error, "failure condition")
+		require.Contains(t, result.Content, "quota exceeded")
+	})
+
+	t.Run("falls back to location header when status url path finds nothing", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/jobs":
+				w.Header().Set("Location", "/jobs/9")
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"state":"queued"}`))
+			case "/jobs/9":
+				_, _ = w.Write([]byte(`{"state":"done"}`))
+			}
+		}))
+		defer server.Close()
+
+		async := fastPolling()
+		async.StatusURLPath = ".links.status"
+
+		executor := newAsyncHTTPExecutor(server.URL, async)
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, `{"state":"done"}`, result.Content)
+	})
+
+	t.Run("fails when status url path is set and the response is not json", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`accepted`))
+		}))
+		defer server.Close()
+
+		async := fastPolling()
+		async.StatusURLPath = ".links.status"
+
+		executor := newAsyncHTTPExecutor(server.URL, async)
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.Error(t, err)
+		require.Contains(t, result.Error, "response is not JSON")
+	})
+
+	t.Run("sends request headers only to status urls on the same host", func(t *testing.T) {
+		var statusAuthorization atomic.Value
+		statusServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			statusAuthorization.Store(r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"state":"done"}`))
+		}))
+		defer statusServer.Close()
+
+		var sameHostAuthorization atomic.Value
+		var crossHost atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/jobs":
+				if crossHost.Load() {
+					w.Header().Set("Location", statusServer.URL+"/jobs/1")
+				} else {
+					w.Header().Set("Location", "/jobs/1")
+				}
+				w.WriteHeader(http.StatusAccepted)
+			case "/jobs/1":
+				sameHostAuthorization.Store(r.Header.Get("Authorization"))
+				_, _ = w.Write([]byte(`{"state":"done"}`))
+			}
+		}))
+		defer server.Close()
+
+		authorization := arkv1alpha1.Header{Name: "Authorization", Value: arkv1alpha1.HeaderValue{Value: "Bearer secret"}}
+
+		executor := newAsyncHTTPExecutor(server.URL, fastPolling(), authorization)
+		_, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer secret", sameHostAuthorization.Load())
+
+		crossHost.Store(true)
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, `{"state":"done"}`, result.Content)
+		require.Equal(t, "", statusAuthorization.Load())
+	})
+
+	t.Run("returns synchronous responses unchanged", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"result":"immediate"}`))
+		}))
+		defer server.Close()
+
+		executor := newAsyncHTTPExecutor(server.URL, fastPolling())
+		result, err := executor.Execute(context.Background(), asyncCall)
+		require.NoError(t, err)
+		require.Equal(t, `{"result":"immediate"}`, result.Content)
+	})
+
+	t.Run("stops polling when context ends", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "/jobs/1")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		executor := newAsyncHTTPExecutor(server.URL, fastPolling())
+		result, err := executor.Execute(ctx, asyncCall)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Contains(t, result.Error, "did not complete")
+	})
+}
@@ -59,17 +59,9 @@ func (f *FilteredToolExecutor) applyJQFilter(content, jqExpr string) (string, er
 		return content, nil
 	}
 
-	iter := query.Run(data)
-	var results []interface{}
-	for {
-		v, ok := iter.Next()
-		if !ok {
-			break
-		}
-		if err, ok := v.(error); ok {
-			return "", fmt.Errorf("jq query execution error: %w", err)
-		}
-		results = append(results, v)
+	results, err := runJQQuery(query, data)
+	if err != nil {
+		return "", err
 	}
 
 	if len(results) == 0 {
@@ -90,3 +82,54 @@ func (f *FilteredToolExecutor) applyJQFilter(content, jqExpr string) (string, er
 
 	return string(filteredBytes), nil
 }
+
+// runJQ parses and evaluates a jq expression against data, returning every result
+func runJQ(jqExpr string, data interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(jqExpr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jq expression '%s': %w", jqExpr, err)
+	}
+	return runJQQuery(query, data)
+}
+
+func runJQQuery(query *gojq.Query, data interface{}) ([]interface{}, error) {
+	iter := query.Run(data)
+	var results []interface{}
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, fmt.Errorf("jq query execution error: %w", err)
+		}
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+// evaluateJQCondition reports whether the first result of a jq expression against a JSON
+// document is truthy, following jq semantics where only false and null are falsy
+func evaluateJQCondition(jqExpr, content string) (bool, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return false, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	results, err := runJQ(jqExpr, data)
+	if err != nil {
+		return false, err
+	}
+	if len(results) == 0 {
+		return false, nil
+	}
+
+	switch v := results[0].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	default:
+		return true, nil
+	}
+}
@@ -54,17 +54,51 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 	}
 
 	resolver := common.NewValueSourceResolver(k8sClient)
-	model, err := resolver.ResolveValueSource(ctx, modelCRD.Spec.Model, namespace)
+	// Routers have no provider model of their own, their calls are named after the router
+	model := modelName
+	if modelCRD.Spec.Type != ModelTypeRouter {
+		model, err = resolver.ResolveValueSource(ctx, modelCRD.Spec.Model, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve model: %w", err)
+		}
+	}
+
+	generation, err := ParseGenerationParameters(modelCRD.Spec.Generation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generation parameters for model %s/%s: %w", namespace, modelName, err)
+	}
+
+	pricing, err := parseModelPricing(modelCRD.Spec.Pricing)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve model: %w", err)
+		return nil, fmt.Errorf("invalid pricing for model %s/%s: %w", namespace, modelName, err)
 	}
 
 	modelInstance := &Model{
+		Name:              modelName,
+		Namespace:         namespace,
 		Model:             model,
 		Type:              modelCRD.Spec.Type,
+		Task:              modelCRD.Spec.Task,
+		Generation:        generation,
+		pricing:           pricing,
+		capabilities:      modelCRD.Status.Capabilities,
 		telemetryRecorder: telemetryRecorder,
 		eventingRecorder:  eventingRecorder,
 	}
+	if embedding := modelCRD.Spec.Embedding; embedding != nil {
+		modelInstance.embeddingDimensions = embedding.Dimensions
+		if embedding.BatchSize != nil {
+			modelInstance.embeddingBatchSize = int(*embedding.BatchSize)
+		}
+	}
+	if modelCRD.Spec.Limits != nil {
+		// Calls wait in the queue of the namespace they are made from, which may differ from the model namespace
+		modelInstance.limiter = modelLimiters.get(modelLimiterKey(namespace, modelName), modelCRD.Spec.Limits)
+		modelInstance.limiterNamespace = defaultNamespace
+	} else {
+		// The limits were removed, or the model never had any
+		modelLimiters.remove(modelLimiterKey(namespace, modelName))
+	}
 
 	switch modelCRD.Spec.Type {
 	case ModelTypeAzure:
@@ -79,6 +113,18 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		if err := loadBedrockConfig(ctx, resolver, modelCRD.Spec.Config.Bedrock, namespace, model, modelInstance); err != nil {
 			return nil, err
 		}
+	case ModelTypeAnthropic:
+		if err := loadAnthropicConfig(ctx, resolver, modelCRD.Spec.Config.Anthropic, namespace, modelInstance, additionalHeaders); err != nil {
+			return nil, err
+		}
+	case ModelTypeGemini:
+		if err := loadGeminiConfig(ctx, resolver, modelCRD.Spec.Config.Gemini, namespace, modelInstance, additionalHeaders); err != nil {
+			return nil, err
+		}
+	case ModelTypeRouter:
+		if err := loadRouterConfig(ctx, k8sClient, modelCRD.Spec.Config.Router, namespace, defaultNamespace, modelInstance, additionalHeaders); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported model type: %s", modelCRD.Spec.Type)
 	}
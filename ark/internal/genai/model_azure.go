@@ -57,6 +57,7 @@ func loadAzureConfig(ctx context.Context, resolver *common.ValueSourceResolver,
 		BaseURL:    baseURL,
 		APIKey:     apiKey,
 		APIVersion: apiVersion,
+		API:        config.API,
 		Headers:    headers,
 		Properties: properties,
 	}
@@ -0,0 +1,141 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// capabilityTestImage is a 1x1 PNG sent to test vision support
+const capabilityTestImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8BQDwAEhQGAhKmMIQAAAABJRU5ErkJggg=="
+
+var capabilityTestSchema = []byte(`{"type":"object","properties":{"ok":{"type":"boolean"}},"required":["ok"],"additionalProperties":false}`)
+
+// DetectModelCapabilities tests the features of the model with one small call each. A feature is
+// unsupported when the provider rejects the request, other errors such as timeouts leave it unknown,
+// as does ctx ending.
+func DetectModelCapabilities(ctx context.Context, model *Model) arkv1alpha1.ModelCapabilities {
+	return arkv1alpha1.ModelCapabilities{
+		ToolCalling: detectCapability(ctx, model, detectToolCalling),
+		Streaming:   detectCapability(ctx, model, detectStreaming),
+		Vision:      detectCapability(ctx, model, detectVision),
+		// Last, as the output schema stays set on the provider
+		StructuredOutput: detectCapability(ctx, model, detectStructuredOutput),
+	}
+}
+
+func detectCapability(ctx context.Context, model *Model, detect func(context.Context, *Model) (bool, error)) *bool {
+	ctx = contextWithProbeMode(ctx)
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	supported, err := detect(ctx, model)
+	if err != nil {
+		if !isRejectedRequest(err) {
+			return nil
+		}
+		supported = false
+	}
+	return &supported
+}
+
+// detectToolCalling asks the model to call a tool, which only models with tool calling do
+func detectToolCalling(ctx context.Context, model *Model) (bool, error) {
+	tool := openai.ChatCompletionToolParam{
+		Type: "function",
+		Function: shared.FunctionDefinitionParam{
+			Name:        "get_time",
+			Description: openai.String("Returns the current time"),
+			Parameters:  shared.FunctionParameters{"type": "object", "properties": map[string]any{}},
+		},
+	}
+	messages := []Message{NewUserMessage("Call the get_time tool.")}
+
+	response, err := model.ChatCompletion(ctx, messages, nil, 1, []openai.ChatCompletionToolParam{tool})
+	if err != nil {
+		return false, err
+	}
+	return len(response.Choices) > 0 && len(response.Choices[0].Message.ToolCalls) > 0, nil
+}
+
+func detectStreaming(ctx context.Context, model *Model) (bool, error) {
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("Say hello.")}, discardEventStream{}, 1)
+	return err == nil, err
+}
+
+func detectVision(ctx context.Context, model *Model) (bool, error) {
+	message := Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What color is this image?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: capabilityTestImage}),
+	}))
+
+	_, err := model.ChatCompletion(ctx, []Message{message}, nil, 1)
+	return err == nil, err
+}
+
+// detectStructuredOutput requests output matching a schema and checks that the output parses
+func detectStructuredOutput(ctx context.Context, model *Model) (bool, error) {
+	model.OutputSchema = &runtime.RawExtension{Raw: capabilityTestSchema}
+	model.SchemaName = "capability_check"
+	defer func() {
+		model.OutputSchema = nil
+		model.Provider.SetOutputSchema(nil, "")
+	}()
+
+	response, err := model.ChatCompletion(ctx, []Message{NewUserMessage("Reply with ok set to true.")}, nil, 1)
+	if err != nil || len(response.Choices) == 0 {
+		return false, err
+	}
+
+	var output struct {
+		OK *bool `json:"ok"`
+	}
+	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &output); err != nil {
+		return false, nil
+	}
+	return output.OK != nil, nil
+}
+
+// isRejectedRequest reports whether the provider rejected a request as invalid, which is how
+// providers respond to features a model does not support
+func isRejectedRequest(err error) bool {
+	var statusCode int
+
+	var openaiErr *openai.Error
+	var anthropicErr *AnthropicError
+	var geminiErr *GeminiError
+	var httpErr *smithyhttp.ResponseError
+	var apiErr smithy.APIError
+	switch {
+	case errors.As(err, &openaiErr):
+		statusCode = openaiErr.StatusCode
+	case errors.As(err, &anthropicErr):
+		statusCode = anthropicErr.StatusCode
+	case errors.As(err, &geminiErr):
+		statusCode = geminiErr.StatusCode
+	case errors.As(err, &httpErr):
+		statusCode = httpErr.HTTPStatusCode()
+	case errors.As(err, &apiErr):
+		// Bedrock validation errors
+		return apiErr.ErrorCode() == "ValidationException"
+	}
+	return statusCode == http.StatusBadRequest || statusCode == http.StatusNotFound || statusCode == http.StatusUnprocessableEntity
+}
+
+// discardEventStream receives the chunks of a streamed completion that is only tested
+type discardEventStream struct{}
+
+func (discardEventStream) StreamChunk(ctx context.Context, chunk interface{}) error { return nil }
+func (discardEventStream) NotifyCompletion(ctx context.Context) error               { return nil }
+func (discardEventStream) Close() error                                             { return nil }
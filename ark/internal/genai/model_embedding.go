@@ -0,0 +1,115 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/openai/openai-go"
+
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// defaultEmbeddingBatchSize is the most texts sent in one embedding request when the Model does
+// not set a batch size
+const defaultEmbeddingBatchSize = 100
+
+// EmbeddingProvider is implemented by providers that can embed texts
+type EmbeddingProvider interface {
+	// Embed returns one embedding for every input, in the order of the inputs. Dimensions is nil
+	// for the default size of the model.
+	Embed(ctx context.Context, inputs []string, dimensions *int64) (*EmbeddingResponse, error)
+}
+
+// EmbeddingResponse holds the embeddings of one request and the tokens of its inputs
+type EmbeddingResponse struct {
+	Embeddings   [][]float64
+	PromptTokens int64
+}
+
+// Embed turns the inputs into embeddings, one for every input in the order of the inputs. Inputs
+// beyond the batch size of the model are sent in further requests.
+func (m *Model) Embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	provider, ok := m.Provider.(EmbeddingProvider)
+	if !ok {
+		return nil, fmt.Errorf("embeddings are not supported for model type: %s", m.Type)
+	}
+	if m.Task != ModelTaskEmbedding {
+		return nil, fmt.Errorf("model %s/%s is not an embedding model", m.Namespace, m.Name)
+	}
+
+	ctx, span := m.telemetryRecorder.StartModelExecution(ctx, m.Model, m.Type)
+	defer span.End()
+
+	operationData := map[string]string{
+		"model":     m.Model,
+		"modelType": m.Type,
+		"inputs":    strconv.Itoa(len(inputs)),
+	}
+	ctx = m.eventingRecorder.Start(ctx, "Embedding", fmt.Sprintf("Embedding %d inputs with model %s", len(inputs), m.Model), operationData)
+
+	m.telemetryRecorder.RecordInput(span, inputs)
+	m.telemetryRecorder.RecordModelDetails(span, m.Model, m.Type)
+
+	batchSize := m.embeddingBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultEmbeddingBatchSize
+	}
+
+	embeddings := make([][]float64, 0, len(inputs))
+	var usage openai.CompletionUsage
+	for start := 0; start < len(inputs); start += batchSize {
+		batch := inputs[start:min(start+batchSize, len(inputs))]
+		response, err := m.embedBatch(ctx, span, provider, batch, operationData)
+		if err != nil {
+			m.telemetryRecorder.RecordError(span, err)
+			m.eventingRecorder.Fail(ctx, "Embedding", fmt.Sprintf("Embedding failed: %v", err), err, operationData)
+			return nil, err
+		}
+		embeddings = append(embeddings, response.Embeddings...)
+		usage.PromptTokens += response.PromptTokens
+	}
+	usage.TotalTokens = usage.PromptTokens
+
+	if len(embeddings) > 0 {
+		operationData["dimensions"] = strconv.Itoa(len(embeddings[0]))
+	}
+	m.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, 0, usage.TotalTokens)
+	m.telemetryRecorder.RecordSuccess(span)
+	m.eventingRecorder.Complete(ctx, "Embedding", "Embedding completed successfully", operationData)
+	m.recordUsage(ctx, span, usage)
+
+	return embeddings, nil
+}
+
+func (m *Model) embedBatch(ctx context.Context, span telemetry.Span, provider EmbeddingProvider, batch []string, operationData map[string]string) (*EmbeddingResponse, error) {
+	reservation, err := m.waitForLimits(ctx, span, func() int64 { return estimateEmbeddingTokens(batch) }, operationData)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := provider.Embed(ctx, batch, m.embeddingDimensions)
+	if err != nil {
+		reservation.releaseUsage(nil)
+		return nil, err
+	}
+	reservation.releaseUsage(&openai.CompletionUsage{PromptTokens: response.PromptTokens, TotalTokens: response.PromptTokens})
+
+	if len(response.Embeddings) != len(batch) {
+		return nil, fmt.Errorf("model returned %d embeddings for %d inputs", len(response.Embeddings), len(batch))
+	}
+	for i, embedding := range response.Embeddings {
+		if len(embedding) == 0 {
+			return nil, fmt.Errorf("model returned no embedding for input %d", i)
+		}
+	}
+	return response, nil
+}
+
+func estimateEmbeddingTokens(inputs []string) int64 {
+	size := 0
+	for _, input := range inputs {
+		size += len(input)
+	}
+	return int64(size/charactersPerToken) + 1
+}
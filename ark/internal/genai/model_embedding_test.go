@@ -0,0 +1,140 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"mckinsey.com/ark/internal/eventing"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// newEmbeddingStub returns an OpenAI compatible embeddings endpoint. The embedding of an input
+// starts with the length of the input, and embeddings are returned in reverse order to test their
+// indexes.
+func newEmbeddingStub() *providerStub {
+	return &providerStub{path: "/embeddings", respond: respondWithEmbeddings}
+}
+
+func respondWithEmbeddings(w http.ResponseWriter, request map[string]any) error {
+	inputs, ok := request["input"].([]any)
+	if !ok {
+		return fmt.Errorf("request without a list of inputs")
+	}
+	var data []map[string]any
+	for i := len(inputs) - 1; i >= 0; i-- {
+		embedding := []float64{float64(len(inputs[i].(string))), 0.5, 0.25}
+		data = append(data, map[string]any{"object": "embedding", "index": i, "embedding": embedding})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]any{
+		"object": "list",
+		"model":  request["model"],
+		"data":   data,
+		"usage":  map[string]any{"prompt_tokens": 2 * len(inputs), "total_tokens": 2 * len(inputs)},
+	})
+}
+
+// usageRecorder records the model usage reported to the token collectors
+type usageRecorder struct {
+	eventing.ModelRecorder
+	usage []eventing.ModelCallUsage
+}
+
+func (r *usageRecorder) AddModelUsage(ctx context.Context, usage eventing.ModelCallUsage) {
+	r.usage = append(r.usage, usage)
+}
+
+func newEmbeddingTestModel(baseURL string) (*Model, *usageRecorder) {
+	recorder := &usageRecorder{ModelRecorder: eventnoop.NewModelRecorder()}
+	return &Model{
+		Name:              "embeddings",
+		Namespace:         "default",
+		Model:             "text-embedding-3-small",
+		Type:              ModelTypeOpenAI,
+		Task:              ModelTaskEmbedding,
+		Provider:          &OpenAIProvider{Model: "text-embedding-3-small", BaseURL: baseURL, APIKey: "sk-test"},
+		telemetryRecorder: noop.NewModelRecorder(),
+		eventingRecorder:  recorder,
+	}, recorder
+}
+
+func TestModelEmbed(t *testing.T) {
+	stub := newEmbeddingStub()
+	server := stub.start(t)
+	model, recorder := newEmbeddingTestModel(server.URL)
+	model.embeddingBatchSize = 2
+	model.embeddingDimensions = ptr.To(int64(3))
+
+	embeddings, err := model.Embed(context.Background(), []string{"a", "bb", "ccc", "dddd", "eeeee"})
+	require.NoError(t, err)
+	require.Len(t, embeddings, 5)
+	for i, embedding := range embeddings {
+		require.Equal(t, float64(i+1), embedding[0], "embeddings are in the order of the inputs")
+	}
+
+	require.Len(t, stub.requests, 3, "inputs are sent in batches")
+	require.Equal(t, []any{"eeeee"}, stub.requests[2]["input"])
+	require.Equal(t, float64(3), stub.requests[0]["dimensions"])
+
+	require.Len(t, recorder.usage, 1)
+	require.Equal(t, "embeddings", recorder.usage[0].Model)
+	require.Equal(t, int64(10), recorder.usage[0].Usage.PromptTokens)
+	require.Equal(t, int64(0), recorder.usage[0].Usage.CompletionTokens)
+}
+
+func TestModelEmbedTask(t *testing.T) {
+	stub := newEmbeddingStub()
+	server := stub.start(t)
+	model, _ := newEmbeddingTestModel(server.URL)
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, nil, 1)
+	require.ErrorContains(t, err, "is an embedding model and cannot complete chats")
+
+	model.Task = ModelTaskCompletion
+	_, err = model.Embed(context.Background(), []string{"Hello"})
+	require.ErrorContains(t, err, "is not an embedding model")
+
+	model.Task = ModelTaskEmbedding
+	model.Provider = &AnthropicProvider{}
+	_, err = model.Embed(context.Background(), []string{"Hello"})
+	require.ErrorContains(t, err, "embeddings are not supported for model type: openai")
+	require.Empty(t, stub.requests)
+}
+
+func TestProbeEmbeddingModel(t *testing.T) {
+	stub := newEmbeddingStub()
+	server := stub.start(t)
+	model, recorder := newEmbeddingTestModel(server.URL)
+
+	result := ProbeModel(context.Background(), model, ProbeModeCompletion)
+	require.True(t, result.Available, result.DetailedError)
+	require.Equal(t, int64(3), result.EmbeddingDimensions)
+	require.Len(t, stub.requests, 1)
+	require.Empty(t, recorder.usage, "probes are not recorded as model usage")
+}
+
+func TestGeminiEmbed(t *testing.T) {
+	stub := &providerStub{body: `{"embeddings": [{"values": [0.1, 0.2]}, {"values": [0.3, 0.4]}]}`}
+	server := stub.start(t)
+	provider := newGeminiTestProvider(server.URL, nil)
+	provider.Model = "gemini-embedding-001"
+
+	response, err := provider.Embed(context.Background(), []string{"first", "second"}, ptr.To(int64(2)))
+	require.NoError(t, err)
+	require.Equal(t, [][]float64{{0.1, 0.2}, {0.3, 0.4}}, response.Embeddings)
+
+	require.Equal(t, "/v1beta/models/gemini-embedding-001:batchEmbedContents", stub.paths[0])
+	requests := stub.requests[0]["requests"].([]any)
+	require.Len(t, requests, 2)
+	first := requests[0].(map[string]any)
+	require.Equal(t, "models/gemini-embedding-001", first["model"])
+	require.Equal(t, float64(2), first["outputDimensionality"])
+	require.Equal(t, "first", first["content"].(map[string]any)["parts"].([]any)[0].(map[string]any)["text"])
+}
@@ -0,0 +1,87 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadGeminiConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.GeminiModelConfig, namespace string, model *Model, additionalHeaders map[string]string) error {
+	if config == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+
+	resolve := func(valueSource *arkv1alpha1.ValueSource, field string) (string, error) {
+		if valueSource == nil {
+			return "", nil
+		}
+		value, err := resolver.ResolveValueSource(ctx, *valueSource, namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve Gemini %s: %w", field, err)
+		}
+		return value, nil
+	}
+
+	baseURL, err := resolve(config.BaseURL, "baseURL")
+	if err != nil {
+		return err
+	}
+	apiKey, err := resolve(config.APIKey, "apiKey")
+	if err != nil {
+		return err
+	}
+	serviceAccount, err := resolve(config.ServiceAccount, "serviceAccount")
+	if err != nil {
+		return err
+	}
+	project, err := resolve(config.Project, "project")
+	if err != nil {
+		return err
+	}
+	location, err := resolve(config.Location, "location")
+	if err != nil {
+		return err
+	}
+
+	if apiKey == "" && serviceAccount == "" {
+		return fmt.Errorf("gemini configuration requires an apiKey or a serviceAccount")
+	}
+
+	headers, err := resolveModelHeaders(ctx, resolver.Client, config.Headers, namespace)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range additionalHeaders {
+		headers[k] = v
+	}
+
+	var properties map[string]string
+	if config.Properties != nil {
+		properties = make(map[string]string)
+		for key, valueSource := range config.Properties {
+			value, err := resolver.ResolveValueSource(ctx, valueSource, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve Gemini property %s: %w", key, err)
+			}
+			properties[key] = value
+		}
+	}
+
+	geminiProvider := &GeminiProvider{
+		Model:          model.Model,
+		BaseURL:        baseURL,
+		APIKey:         apiKey,
+		ServiceAccount: serviceAccount,
+		Project:        project,
+		Location:       location,
+		Headers:        headers,
+		Properties:     properties,
+	}
+	model.Provider = geminiProvider
+	model.Properties = properties
+
+	return nil
+}
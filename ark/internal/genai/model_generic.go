@@ -3,11 +3,13 @@ package genai
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/metrics"
 	"mckinsey.com/ark/internal/telemetry"
 )
 
@@ -15,6 +17,7 @@ type ChatCompletionProvider interface {
 	ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error)
 	ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error)
 	SetOutputSchema(schema *runtime.RawExtension, schemaName string)
+	SetGenerationParameters(params *GenerationParameters)
 }
 
 type ConfigProvider interface {
@@ -22,23 +25,45 @@ type ConfigProvider interface {
 }
 
 type Model struct {
-	Model             string
-	Type              string
-	Properties        map[string]string
-	Provider          ChatCompletionProvider
-	OutputSchema      *runtime.RawExtension
-	SchemaName        string
-	telemetryRecorder telemetry.ModelRecorder
-	eventingRecorder  eventing.ModelRecorder
+	// Name and Namespace are those of the Model resource, Model is the name of the provider model
+	Name      string
+	Namespace string
+	Model     string
+	Type      string
+	// Task is completion or embedding, models with an empty task are completion models
+	Task         string
+	Properties   map[string]string
+	Provider     ChatCompletionProvider
+	OutputSchema *runtime.RawExtension
+	SchemaName   string
+	// limiter enforces the limits of the Model resource, calls wait in the queue of limiterNamespace
+	limiter          *modelLimiter
+	limiterNamespace string
+	pricing          *modelPricing
+	// capabilities are those detected for the Model resource, used by routers to choose a route
+	capabilities *arkv1alpha1.ModelCapabilities
+	// embeddingDimensions and embeddingBatchSize configure the calls to embedding models
+	embeddingDimensions *int64
+	embeddingBatchSize  int
+	// Generation holds the generation parameters of the Model resource
+	Generation *GenerationParameters
+	// GenerationOverrides holds the generation parameters of the calling agent, which take precedence
+	GenerationOverrides *GenerationParameters
+	telemetryRecorder   telemetry.ModelRecorder
+	eventingRecorder    eventing.ModelRecorder
 }
 
 func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStream EventStreamInterface, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	if m.Provider == nil {
 		return nil, nil
 	}
+	if m.Task == ModelTaskEmbedding {
+		return nil, fmt.Errorf("model %s/%s is an embedding model and cannot complete chats", m.Namespace, m.Name)
+	}
 
 	ctx, span := m.telemetryRecorder.StartModelExecution(ctx, m.Model, m.Type)
 	defer span.End()
+	ctx = contextWithModelSpan(ctx, span)
 
 	operationData := map[string]string{
 		"model":     m.Model,
@@ -57,9 +82,17 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 	if m.OutputSchema != nil {
 		m.Provider.SetOutputSchema(m.OutputSchema, m.SchemaName)
 	}
+	generation := m.Generation.withOverrides(m.GenerationOverrides)
+	m.Provider.SetGenerationParameters(generation)
+
+	reservation, err := m.waitForLimits(ctx, span, func() int64 { return estimateTokens(messages, tools, generation) }, operationData)
+	if err != nil {
+		m.telemetryRecorder.RecordError(span, err)
+		m.eventingRecorder.Fail(ctx, "LLMCall", fmt.Sprintf("Model call failed: %v", err), err, operationData)
+		return nil, err
+	}
 
 	var response *openai.ChatCompletion
-	var err error
 
 	if eventStream != nil {
 		response, err = m.Provider.ChatCompletionStream(ctx, messages, n, func(chunk *openai.ChatCompletionChunk) error {
@@ -69,6 +102,7 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 	} else {
 		response, err = m.Provider.ChatCompletion(ctx, messages, n, tools...)
 	}
+	reservation.release(response)
 
 	if err != nil {
 		m.telemetryRecorder.RecordError(span, err)
@@ -88,13 +122,60 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 	}
 
 	m.telemetryRecorder.RecordTokenUsage(span, response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Usage.TotalTokens)
+	if reasoningTokens := response.Usage.CompletionTokensDetails.ReasoningTokens; reasoningTokens > 0 {
+		m.telemetryRecorder.RecordReasoningTokens(span, reasoningTokens)
+	}
 	m.telemetryRecorder.RecordSuccess(span)
 	m.eventingRecorder.Complete(ctx, "LLMCall", "Model call completed successfully", operationData)
-	m.eventingRecorder.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{
-		PromptTokens:     response.Usage.PromptTokens,
-		CompletionTokens: response.Usage.CompletionTokens,
-		TotalTokens:      response.Usage.TotalTokens,
-	})
+	// The models a router calls record their own usage and cost
+	if _, isRouter := m.Provider.(*RouterProvider); !isRouter {
+		m.recordUsage(ctx, span, response.Usage)
+	}
 
 	return response, nil
 }
+
+// recordUsage attributes the usage of a call to the Model resource and prices it. Probes and
+// capability checks are not recorded, so they do not add to the token and cost metrics.
+func (m *Model) recordUsage(ctx context.Context, span telemetry.Span, completionUsage openai.CompletionUsage) {
+	if IsProbeContext(ctx) {
+		return
+	}
+
+	usage := eventing.ModelCallUsage{
+		Model:     m.Name,
+		Namespace: m.Namespace,
+		Usage: arkv1alpha1.TokenUsage{
+			PromptTokens:       completionUsage.PromptTokens,
+			CompletionTokens:   completionUsage.CompletionTokens,
+			TotalTokens:        completionUsage.TotalTokens,
+			CachedPromptTokens: completionUsage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens:    completionUsage.CompletionTokensDetails.ReasoningTokens,
+		},
+	}
+	if m.pricing != nil {
+		usage.Cost = m.pricing.cost(usage.Usage)
+		usage.Currency = m.pricing.currency
+		m.telemetryRecorder.RecordCost(span, usage.Cost, usage.Currency)
+	}
+
+	m.eventingRecorder.AddModelUsage(ctx, usage)
+	metrics.RecordModelUsage(m.Namespace, m.Name, usage.Usage, usage.Cost, usage.Currency)
+}
+
+// waitForLimits waits until the limits of the model allow the call, which uses the estimated
+// tokens. Probes are not limited, so they neither wait behind queries nor use their capacity.
+func (m *Model) waitForLimits(ctx context.Context, span telemetry.Span, estimate func() int64, operationData map[string]string) (*rateLimitReservation, error) {
+	if m.limiter == nil || IsProbeContext(ctx) {
+		return nil, nil
+	}
+
+	reservation, err := m.limiter.acquire(ctx, m.limiterNamespace, estimate())
+	if err != nil {
+		return nil, fmt.Errorf("waiting for model limits: %w", err)
+	}
+
+	m.telemetryRecorder.RecordRateLimit(span, reservation.waited, int64(reservation.tokens))
+	operationData["rateLimitWaitMs"] = strconv.FormatInt(reservation.waited.Milliseconds(), 10)
+	return reservation, nil
+}
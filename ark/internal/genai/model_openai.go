@@ -48,6 +48,7 @@ func loadOpenAIConfig(ctx context.Context, resolver *common.ValueSourceResolver,
 		Model:      model.Model,
 		BaseURL:    baseURL,
 		APIKey:     apiKey,
+		API:        config.API,
 		Headers:    headers,
 		Properties: properties,
 	}
@@ -29,31 +29,98 @@ type ProbeResult struct {
 	Available     bool
 	Message       string // Stable message for status condition
 	DetailedError error  // Full error for logging
+	// EmbeddingDimensions is the size of the embedding returned to the probe of an embedding model
+	EmbeddingDimensions int64
 }
 
-// ProbeModel tests if a model is available
-func ProbeModel(ctx context.Context, model *Model) ProbeResult {
-	timeout := 30 * time.Second
+// ModelListProber is implemented by providers that support the listModels probe mode
+type ModelListProber interface {
+	// ProbeModelList checks the model with the models endpoint of the provider
+	ProbeModelList(ctx context.Context) error
+}
+
+var (
+	errModelNotListed        = errors.New("model is not listed by the provider")
+	errListModelsUnsupported = errors.New("listModels probe mode is not supported for model type")
+)
+
+const (
+	probeTimeout = 30 * time.Second
+	// probeMaxTokens is enough for the model to show it responds
+	probeMaxTokens = 1
+)
+
+// ProbeModel tests if a model is available with the probe mode, completion when empty
+func ProbeModel(ctx context.Context, model *Model, mode string) ProbeResult {
+	if mode == ProbeModeDisabled {
+		return ProbeResult{
+			Available: true,
+			Message:   "Model probe is disabled",
+		}
+	}
+
 	probeCtx := contextWithProbeMode(context.Background())
-	probeCtx, cancel := context.WithTimeout(probeCtx, timeout)
+	probeCtx, cancel := context.WithTimeout(probeCtx, probeTimeout)
 	defer cancel()
 
-	testMessages := []Message{NewUserMessage("Hello")}
-
-	_, err := model.ChatCompletion(probeCtx, testMessages, nil, 1)
+	var err error
+	var dimensions int64
+	switch {
+	case mode == ProbeModeListModels:
+		err = probeModelList(probeCtx, model)
+	case model.Task == ModelTaskEmbedding:
+		dimensions, err = probeEmbedding(probeCtx, model)
+	default:
+		err = probeCompletion(probeCtx, model)
+	}
 	if err != nil {
 		return ProbeResult{
 			Available:     false,
-			Message:       extractStableError(err, timeout),
+			Message:       extractStableError(err, probeTimeout),
 			DetailedError: err,
 		}
 	}
 
 	return ProbeResult{
-		Available:     true,
-		Message:       "Model is available",
-		DetailedError: nil,
+		Available:           true,
+		Message:             "Model is available",
+		DetailedError:       nil,
+		EmbeddingDimensions: dimensions,
+	}
+}
+
+// probeEmbedding embeds a single word, which also shows the size of the embeddings
+func probeEmbedding(ctx context.Context, model *Model) (int64, error) {
+	embeddings, err := model.Embed(ctx, []string{"Hello"})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(embeddings[0])), nil
+}
+
+// probeCompletion requests a completion of a single token. The generation parameters of the model
+// are not used, a reasoning budget for example would not fit in the completion. They are restored
+// afterwards, as the model is also used to detect its capabilities.
+func probeCompletion(ctx context.Context, model *Model) error {
+	generation, overrides := model.Generation, model.GenerationOverrides
+	defer func() {
+		model.Generation, model.GenerationOverrides = generation, overrides
+	}()
+
+	maxTokens := int64(probeMaxTokens)
+	model.Generation = &GenerationParameters{MaxTokens: &maxTokens}
+	model.GenerationOverrides = nil
+
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("Hello")}, nil, 1)
+	return err
+}
+
+func probeModelList(ctx context.Context, model *Model) error {
+	prober, ok := model.Provider.(ModelListProber)
+	if !ok {
+		return fmt.Errorf("%w: %s", errListModelsUnsupported, model.Type)
 	}
+	return prober.ProbeModelList(ctx)
 }
 
 // Returns a stable error message suitable for a 'condition'. If error messages
@@ -66,12 +133,31 @@ func extractStableError(err error, timeout time.Duration) string {
 		return fmt.Sprintf("Probe failed (timeout after %d seconds)", int(timeout.Seconds()))
 	}
 
+	if errors.Is(err, errModelNotListed) {
+		return "Model not found in models list"
+	}
+	if errors.Is(err, errListModelsUnsupported) {
+		return err.Error()
+	}
+
 	// OpenAI API error
 	var openaiErr *openai.Error
 	if errors.As(err, &openaiErr) {
 		return fmt.Sprintf("%s (%d)", openaiErr.Message, openaiErr.StatusCode)
 	}
 
+	// Anthropic API error
+	var anthropicErr *AnthropicError
+	if errors.As(err, &anthropicErr) {
+		return fmt.Sprintf("%s (%d)", anthropicErr.Message, anthropicErr.StatusCode)
+	}
+
+	// Gemini or Vertex AI API error
+	var geminiErr *GeminiError
+	if errors.As(err, &geminiErr) {
+		return fmt.Sprintf("%s (%d)", geminiErr.Message, geminiErr.StatusCode)
+	}
+
 	// AWS Smithy API error with HTTP response
 	var httpErr *smithyhttp.ResponseError
 	if errors.As(err, &httpErr) {
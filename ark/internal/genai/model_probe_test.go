@@ -0,0 +1,162 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// newChatStub returns an OpenAI compatible server for gpt-4o that supports tools, structured output
+// and streaming but rejects images
+func newChatStub() *providerStub {
+	return &providerStub{
+		routes:  map[string]string{"/models": `{"object": "list", "data": [{"id": "gpt-4o", "object": "model", "created": 0, "owned_by": "openai"}]}`},
+		respond: respondToChat,
+	}
+}
+
+func respondToChat(w http.ResponseWriter, request map[string]any) error {
+	messages, ok := request["messages"].([]any)
+	if !ok || len(messages) == 0 {
+		return fmt.Errorf("request without messages")
+	}
+	if _, isText := messages[0].(map[string]any)["content"].(string); !isText {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"message": "image input is not supported", "type": "invalid_request_error"}}`))
+		return nil
+	}
+
+	if request["stream"] == true {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"id": "c1", "object": "chat.completion.chunk", "created": 0, "model": "gpt-4o", "choices": [{"index": 0, "delta": {"content": "Hi"}, "finish_reason": "stop"}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		return nil
+	}
+
+	message := `{"role": "assistant", "content": "Hi"}`
+	switch {
+	case request["tools"] != nil:
+		message = `{"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_time", "arguments": "{}"}}]}`
+	case request["response_format"] != nil:
+		message = `{"role": "assistant", "content": "{\"ok\": true}"}`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"id": "c1", "object": "chat.completion", "created": 0, "model": "gpt-4o",
+		"choices": [{"index": 0, "finish_reason": "stop", "message": ` + message + `}],
+		"usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}}`))
+	return nil
+}
+
+func newProbeTestModel(baseURL, model string) *Model {
+	return &Model{
+		Name:              "test-model",
+		Namespace:         "default",
+		Model:             model,
+		Type:              ModelTypeOpenAI,
+		Provider:          &OpenAIProvider{Model: model, BaseURL: baseURL, APIKey: "sk-test"},
+		telemetryRecorder: noop.NewModelRecorder(),
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+}
+
+func TestProbeModel(t *testing.T) {
+	t.Run("completion of a single token", func(t *testing.T) {
+		stub := newChatStub()
+		server := stub.start(t)
+		model := newProbeTestModel(server.URL, "gpt-4o")
+		temperature := 0.2
+		model.Generation = &GenerationParameters{Temperature: &temperature}
+
+		result := ProbeModel(context.Background(), model, ProbeModeCompletion)
+		require.True(t, result.Available, result.DetailedError)
+		require.Len(t, stub.requests, 1)
+		require.Equal(t, float64(1), stub.requests[0]["max_completion_tokens"])
+		require.NotContains(t, stub.requests[0], "temperature")
+		require.Equal(t, &temperature, model.Generation.Temperature, "the generation parameters are restored")
+	})
+
+	t.Run("capabilities detected after the probe", func(t *testing.T) {
+		stub := newChatStub()
+		server := stub.start(t)
+		model := newProbeTestModel(server.URL, "gpt-4o")
+
+		result := ProbeModel(context.Background(), model, ProbeModeCompletion)
+		require.True(t, result.Available, result.DetailedError)
+		capabilities := DetectModelCapabilities(context.Background(), model)
+		require.True(t, *capabilities.ToolCalling)
+		require.True(t, *capabilities.StructuredOutput)
+		for _, request := range stub.requests[1:] {
+			require.NotContains(t, request, "max_completion_tokens", "capability checks are not limited to the probe token")
+		}
+	})
+
+	t.Run("models endpoint", func(t *testing.T) {
+		stub := newChatStub()
+		server := stub.start(t)
+
+		result := ProbeModel(context.Background(), newProbeTestModel(server.URL, "gpt-4o"), ProbeModeListModels)
+		require.True(t, result.Available, result.DetailedError)
+		require.Empty(t, stub.requests, "no completion is requested")
+
+		result = ProbeModel(context.Background(), newProbeTestModel(server.URL, "gpt-5"), ProbeModeListModels)
+		require.False(t, result.Available)
+		require.Equal(t, "Model not found in models list", result.Message)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := ProbeModel(context.Background(), newProbeTestModel("http://127.0.0.1:0", "gpt-4o"), ProbeModeDisabled)
+		require.True(t, result.Available)
+		require.Equal(t, "Model probe is disabled", result.Message)
+	})
+
+	t.Run("models endpoint not supported", func(t *testing.T) {
+		model := &Model{Type: ModelTypeBedrock, Provider: &BedrockModel{}}
+		result := ProbeModel(context.Background(), model, ProbeModeListModels)
+		require.False(t, result.Available)
+		require.Equal(t, "listModels probe mode is not supported for model type: bedrock", result.Message)
+	})
+}
+
+func TestDetectModelCapabilities(t *testing.T) {
+	stub := newChatStub()
+	server := stub.start(t)
+	model := newProbeTestModel(server.URL, "gpt-4o")
+	recorder := &usageRecorder{ModelRecorder: eventnoop.NewModelRecorder()}
+	model.eventingRecorder = recorder
+
+	capabilities := DetectModelCapabilities(context.Background(), model)
+	require.True(t, *capabilities.ToolCalling)
+	require.True(t, *capabilities.StructuredOutput)
+	require.True(t, *capabilities.Streaming)
+	require.False(t, *capabilities.Vision)
+	require.Nil(t, model.OutputSchema, "the test schema is removed")
+	require.Empty(t, recorder.usage, "capability checks are not recorded as model usage")
+
+	// Capabilities are unknown when calls fail for other reasons than an invalid request
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": {"message": "invalid api key", "type": "invalid_request_error"}}`))
+	}))
+	t.Cleanup(unauthorized.Close)
+	capabilities = DetectModelCapabilities(context.Background(), newProbeTestModel(unauthorized.URL, "gpt-4o"))
+	require.Nil(t, capabilities.ToolCalling)
+	require.Nil(t, capabilities.Vision)
+
+	// Capabilities are unknown when the context of the caller ends
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	capabilities = DetectModelCapabilities(cancelled, newProbeTestModel(server.URL, "gpt-4o"))
+	require.Nil(t, capabilities.ToolCalling)
+	require.Nil(t, capabilities.StructuredOutput)
+	require.Nil(t, capabilities.Streaming)
+	require.Nil(t, capabilities.Vision)
+}
@@ -5,30 +5,25 @@ import (
 	"strconv"
 
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
 	"github.com/openai/openai-go/shared"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// applyPropertiesToParams merges the model properties into the request parameters. Values that are
+// valid JSON, such as numbers, are merged as JSON so that they keep their type.
 func applyPropertiesToParams(properties map[string]string, params *openai.ChatCompletionNewParams) {
-	setDefaults := func() {
-		params.Temperature = openai.Float(1.0)
-		params.N = openai.Int(1)
-	}
-
 	if len(properties) == 0 {
-		setDefaults()
 		return
 	}
 
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
-		setDefaults()
 		return
 	}
 
 	var paramsMap map[string]any
 	if err := json.Unmarshal(paramsJSON, &paramsMap); err != nil {
-		setDefaults()
 		return
 	}
 
@@ -36,14 +31,7 @@ func applyPropertiesToParams(properties map[string]string, params *openai.ChatCo
 		if value == "" {
 			continue
 		}
-		paramsMap[key] = value
-	}
-
-	if _, exists := properties["temperature"]; !exists {
-		paramsMap["temperature"] = 1.0
-	}
-	if _, exists := properties["n"]; !exists {
-		paramsMap["n"] = 1
+		paramsMap[key] = parsePropertyValue(value)
 	}
 
 	updatedJSON, err := json.Marshal(paramsMap)
@@ -54,6 +42,39 @@ func applyPropertiesToParams(properties map[string]string, params *openai.ChatCo
 	_ = json.Unmarshal(updatedJSON, params)
 }
 
+// applyGenerationToParams sets the generation parameters on the request, replacing properties of the same meaning
+func applyGenerationToParams(generation *GenerationParameters, params *openai.ChatCompletionNewParams) {
+	if generation == nil {
+		return
+	}
+	if generation.Temperature != nil {
+		params.Temperature = openai.Float(*generation.Temperature)
+	}
+	if generation.TopP != nil {
+		params.TopP = openai.Float(*generation.TopP)
+	}
+	if generation.MaxTokens != nil {
+		// max_tokens is deprecated and rejected by reasoning models
+		params.MaxTokens = param.Opt[int64]{}
+		params.MaxCompletionTokens = openai.Int(*generation.MaxTokens)
+	}
+	if len(generation.Stop) > 0 {
+		params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: generation.Stop}
+	}
+	if generation.Seed != nil {
+		params.Seed = openai.Int(*generation.Seed)
+	}
+	if generation.ReasoningEffort != "" {
+		params.ReasoningEffort = shared.ReasoningEffort(generation.ReasoningEffort)
+	}
+	if generation.PresencePenalty != nil {
+		params.PresencePenalty = openai.Float(*generation.PresencePenalty)
+	}
+	if generation.FrequencyPenalty != nil {
+		params.FrequencyPenalty = openai.Float(*generation.FrequencyPenalty)
+	}
+}
+
 // getFloatProperty extracts a float property with a default value
 func getFloatProperty(properties map[string]string, key string, defaultValue float64) float64 {
 	if value, exists := properties[key]; exists {
@@ -74,6 +95,16 @@ func getIntProperty(properties map[string]string, key string, defaultValue int)
 	return defaultValue
 }
 
+// parsePropertyValue returns property values that are valid JSON, such as numbers or arrays, as
+// JSON and any other value as a string
+func parsePropertyValue(value string) any {
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+	return parsed
+}
+
 // applyStructuredOutputToParams applies structured output schema to OpenAI parameters
 func applyStructuredOutputToParams(outputSchema *runtime.RawExtension, schemaName string, params *openai.ChatCompletionNewParams) {
 	if outputSchema != nil && outputSchema.Raw != nil {
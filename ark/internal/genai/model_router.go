@@ -0,0 +1,105 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// loadRouterConfig loads the Models of the routes of a router. Calls to these models wait in the
+// queues of callerNamespace, as calls to the router do.
+func loadRouterConfig(ctx context.Context, k8sClient client.Client, config *arkv1alpha1.RouterModelConfig, namespace, callerNamespace string, model *Model, additionalHeaders map[string]string) error {
+	if config == nil {
+		return fmt.Errorf("router configuration is required for router model type")
+	}
+
+	router := &RouterProvider{
+		Name:              model.Name,
+		Namespace:         model.Namespace,
+		Strategy:          config.Strategy,
+		telemetryRecorder: model.telemetryRecorder,
+		random:            rand.IntN,
+		latencies:         modelLatencies,
+	}
+	if router.Strategy == "" {
+		router.Strategy = RouterStrategyWeighted
+	}
+
+	// A model used by several routes is loaded once, so it is called with the same settings
+	loaded := make(map[string]*Model)
+	load := func(name string) (*Model, error) {
+		if routeModel, ok := loaded[name]; ok {
+			return routeModel, nil
+		}
+		routeModel, err := loadRouteModel(ctx, k8sClient, name, namespace, callerNamespace, model, additionalHeaders)
+		if err != nil {
+			return nil, err
+		}
+		loaded[name] = routeModel
+		return routeModel, nil
+	}
+
+	for i, spec := range config.Routes {
+		route, err := newModelRoute(spec)
+		if err != nil {
+			return fmt.Errorf("invalid route %d of router %s/%s: %w", i, model.Namespace, model.Name, err)
+		}
+		if route.model, err = load(spec.Model); err != nil {
+			return err
+		}
+		for _, name := range spec.Fallbacks {
+			fallback, err := load(name)
+			if err != nil {
+				return err
+			}
+			route.fallbacks = append(route.fallbacks, fallback)
+		}
+		router.Routes = append(router.Routes, route)
+	}
+
+	model.Provider = router
+	return nil
+}
+
+// loadRouteModel loads a Model called by a router. Routers cannot call other routers, which also
+// rules out routing loops.
+func loadRouteModel(ctx context.Context, k8sClient client.Client, name, namespace, callerNamespace string, router *Model, additionalHeaders map[string]string) (*Model, error) {
+	modelCRD, err := loadModelCRD(ctx, k8sClient, name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load route model of router %s/%s: %w", namespace, router.Name, err)
+	}
+	if modelCRD.Spec.Type == ModelTypeRouter {
+		return nil, fmt.Errorf("model %s/%s of router %s is a router, routes must use other model types", namespace, name, router.Name)
+	}
+	if modelCRD.Spec.Task == ModelTaskEmbedding {
+		return nil, fmt.Errorf("model %s/%s of router %s is an embedding model, routes must use completion models", namespace, name, router.Name)
+	}
+
+	modelRef := &arkv1alpha1.AgentModelRef{Name: name, Namespace: namespace}
+	return LoadModel(ctx, k8sClient, modelRef, callerNamespace, additionalHeaders, router.telemetryRecorder, router.eventingRecorder)
+}
+
+func newModelRoute(spec arkv1alpha1.ModelRoute) (*modelRoute, error) {
+	route := &modelRoute{
+		weight:          1,
+		minPromptTokens: spec.MinPromptTokens,
+		maxPromptTokens: spec.MaxPromptTokens,
+		capabilities:    spec.Capabilities,
+	}
+	if spec.Weight != nil {
+		route.weight = int(*spec.Weight)
+	}
+	if spec.MaxCost != "" {
+		maxCost, err := strconv.ParseFloat(spec.MaxCost, 64)
+		if err != nil {
+			return nil, fmt.Errorf("maxCost must be a number: %q", spec.MaxCost)
+		}
+		route.maxCost = &maxCost
+	}
+	return route, nil
+}
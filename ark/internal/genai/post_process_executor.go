@@ -0,0 +1,294 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const defaultRedactReplacement = "[REDACTED]"
+
+// PostProcessToolExecutor applies the post-processing pipeline of a Tool to the output of its executor
+type PostProcessToolExecutor struct {
+	BaseExecutor ToolExecutor
+	Steps        []arkv1alpha1.ToolOutputStep
+}
+
+func (p *PostProcessToolExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	result, err := p.BaseExecutor.Execute(ctx, call)
+	if err != nil || result.Error != "" {
+		return result, err
+	}
+
+	content, err := ApplyPostProcessSteps(result.Content, p.Steps)
+	if err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: fmt.Sprintf("post-process error: %v", err),
+		}, fmt.Errorf("post-process error: %w", err)
+	}
+	result.Content = content
+
+	return result, nil
+}
+
+// ApplyPostProcessSteps runs each step in order, feeding the output of one step into the next
+func ApplyPostProcessSteps(content string, steps []arkv1alpha1.ToolOutputStep) (string, error) {
+	for i, step := range steps {
+		var err error
+		content, err = applyPostProcessStep(content, step)
+		if err != nil {
+			return "", fmt.Errorf("step %d (%s): %w", i, step.Type, err)
+		}
+	}
+	return content, nil
+}
+
+func applyPostProcessStep(content string, step arkv1alpha1.ToolOutputStep) (string, error) {
+	switch step.Type {
+	case PostProcessJQ:
+		return postProcessJQ(content, step.Expression)
+	case PostProcessTemplate:
+		return postProcessTemplate(content, step.Expression)
+	case PostProcessRegex:
+		return postProcessRegex(content, step.Expression, step.Group)
+	case PostProcessTable:
+		return postProcessTable(content, step.Format)
+	case PostProcessRedact:
+		return postProcessRedact(content, step)
+	default:
+		return "", fmt.Errorf("unsupported step type '%s'", step.Type)
+	}
+}
+
+// postProcessJQ evaluates a jq expression. A single string result is returned as plain text so it
+// can be consumed by later steps; other results are returned as JSON.
+func postProcessJQ(content, expression string) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return "", fmt.Errorf("output is not valid JSON: %w", err)
+	}
+
+	results, err := runJQ(expression, data)
+	if err != nil {
+		return "", err
+	}
+
+	var output interface{}
+	switch len(results) {
+	case 0:
+		return "", nil
+	case 1:
+		output = results[0]
+	default:
+		output = results
+	}
+
+	if text, ok := output.(string); ok {
+		return text, nil
+	}
+	outputBytes, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jq result: %w", err)
+	}
+	return string(outputBytes), nil
+}
+
+func postProcessTemplate(content, tmpl string) (string, error) {
+	templateData := map[string]any{
+		"raw":    content,
+		"output": content,
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err == nil {
+		templateData["output"] = data
+	}
+
+	resolved, err := common.ResolveTemplate(tmpl, templateData)
+	if err != nil {
+		return "", fmt.Errorf("template resolution failed: %w", err)
+	}
+	return resolved, nil
+}
+
+// postProcessRegex returns every match of the pattern, one per line
+func postProcessRegex(content, pattern string, group int) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regular expression '%s': %w", pattern, err)
+	}
+	if group > re.NumSubexp() {
+		return "", fmt.Errorf("regular expression '%s' has no capture group %d", pattern, group)
+	}
+
+	matches := re.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("regular expression '%s' did not match the output", pattern)
+	}
+
+	extracted := make([]string, 0, len(matches))
+	for _, match := range matches {
+		extracted = append(extracted, match[group])
+	}
+	return strings.Join(extracted, "\n"), nil
+}
+
+// postProcessTable renders a JSON object or array as a table. Objects become rows with one
+// column per key; scalar array items are placed in a single 'value' column.
+func postProcessTable(content, format string) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return "", fmt.Errorf("output is not valid JSON: %w", err)
+	}
+
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	var columns []string
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			row = map[string]interface{}{"value": item}
+		}
+		for key := range row {
+			if !slices.Contains(columns, key) {
+				columns = append(columns, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+	slices.Sort(columns)
+
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = formatTableCell(row[column])
+		}
+		cells = append(cells, line)
+	}
+
+	switch format {
+	case "", TableFormatMarkdown:
+		return renderMarkdownTable(columns, cells), nil
+	case TableFormatCSV:
+		return renderCSVTable(columns, cells)
+	default:
+		return "", fmt.Errorf("unsupported table format '%s'", format)
+	}
+}
+
+func formatTableCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(valueBytes)
+	}
+}
+
+func renderMarkdownTable(columns []string, rows [][]string) string {
+	escape := strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ")
+	writeRow := func(sb *strings.Builder, values []string) {
+		sb.WriteString("|")
+		for _, value := range values {
+			sb.WriteString(" ")
+			sb.WriteString(escape.Replace(value))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	var sb strings.Builder
+	writeRow(&sb, columns)
+	separators := make([]string, len(columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeRow(&sb, separators)
+	for _, row := range rows {
+		writeRow(&sb, row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func renderCSVTable(columns []string, rows [][]string) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(columns); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// postProcessRedact replaces the values of the configured JSON fields, then any text matching the expression
+func postProcessRedact(content string, step arkv1alpha1.ToolOutputStep) (string, error) {
+	replacement := step.Replacement
+	if replacement == "" {
+		replacement = defaultRedactReplacement
+	}
+
+	if len(step.Fields) > 0 {
+		var data interface{}
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return "", fmt.Errorf("redacting fields requires JSON output: %w", err)
+		}
+		redacted, err := json.Marshal(redactFields(data, step.Fields, replacement))
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal redacted output: %w", err)
+		}
+		content = string(redacted)
+	}
+
+	if step.Expression != "" {
+		re, err := regexp.Compile(step.Expression)
+		if err != nil {
+			return "", fmt.Errorf("invalid regular expression '%s': %w", step.Expression, err)
+		}
+		content = re.ReplaceAllLiteralString(content, replacement)
+	}
+
+	return content, nil
+}
+
+func redactFields(data interface{}, fields []string, replacement string) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if slices.Contains(fields, key) {
+				v[key] = replacement
+			} else {
+				v[key] = redactFields(value, fields, replacement)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactFields(value, fields, replacement)
+		}
+		return v
+	default:
+		return v
+	}
+}
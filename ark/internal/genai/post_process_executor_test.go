@@ -0,0 +1,128 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type staticToolExecutor struct {
+	content string
+	err     error
+}
+
+func (s *staticToolExecutor) Execute(_ context.Context, call ToolCall) (ToolResult, error) {
+	if s.err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: s.err.Error()}, s.err
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: s.content}, nil
+}
+
+func TestApplyPostProcessSteps(t *testing.T) {
+	users := `{"users":[{"name":"alice","email":"alice@example.com","age":30},{"name":"bob","email":"bob@example.com","age":25}]}`
+
+	tests := []struct {
+		name        string
+		content     string
+		steps       []arkv1alpha1.ToolOutputStep
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "jq then markdown table",
+			content:  users,
+			steps:    []arkv1alpha1.ToolOutputStep{{Type: "jq", Expression: "[.users[] | {name, age}]"}, {Type: "table"}},
+			expected: "| age | name |\n| --- | --- |\n| 30 | alice |\n| 25 | bob |",
+		},
+		{
+			name:     "csv table",
+			content:  `[{"name":"alice, jr"},{"name":"bob"}]`,
+			steps:    []arkv1alpha1.ToolOutputStep{{Type: "table", Format: "csv"}},
+			expected: "name\n\"alice, jr\"\nbob",
+		},
+		{
+			name:     "jq string result feeds regex",
+			content:  `{"log":"id=12 ok; id=34 ok"}`,
+			steps:    []arkv1alpha1.ToolOutputStep{{Type: "jq", Expression: ".log"}, {Type: "regex", Expression: `id=(\d+)`, Group: 1}},
+			expected: "12\n34",
+		},
+		{
+			name:     "template renders parsed output",
+			content:  users,
+			steps:    []arkv1alpha1.ToolOutputStep{{Type: "template", Expression: "{{range .output.users}}{{.name}};{{end}}"}},
+			expected: "alice;bob;",
+		},
+		{
+			name:     "redact fields and pattern",
+			content:  `{"token":"abc","user":{"email":"alice@example.com","password":"secret"}}`,
+			steps:    []arkv1alpha1.ToolOutputStep{{Type: "redact", Fields: []string{"password", "token"}, Expression: `[a-z]+@example\.com`, Replacement: "***"}},
+			expected: `{"token":"***","user":{"email":"***","password":"***"}}`,
+		},
+		{
+			name:        "jq on non-JSON output fails",
+			content:     "plain text",
+			steps:       []arkv1alpha1.ToolOutputStep{{Type: "jq", Expression: ".a"}},
+			expectedErr: "step 0 (jq): output is not valid JSON",
+		},
+		{
+			name:        "regex without match fails",
+			content:     "nothing here",
+			steps:       []arkv1alpha1.ToolOutputStep{{Type: "regex", Expression: `\d+`}},
+			expectedErr: "did not match",
+		},
+		{
+			name:        "template error is reported",
+			content:     users,
+			steps:       []arkv1alpha1.ToolOutputStep{{Type: "template", Expression: "{{.output.users.missing.field}}"}},
+			expectedErr: "template resolution failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyPostProcessSteps(tt.content, tt.steps)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPostProcessToolExecutor(t *testing.T) {
+	call := ToolCall{
+		ID:       "call-1",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "users"},
+		Type:     "function",
+	}
+	steps := []arkv1alpha1.ToolOutputStep{{Type: "jq", Expression: ".name"}}
+
+	t.Run("transforms successful output", func(t *testing.T) {
+		executor := &PostProcessToolExecutor{BaseExecutor: &staticToolExecutor{content: `{"name":"alice"}`}, Steps: steps}
+		result, err := executor.Execute(context.Background(), call)
+		require.NoError(t, err)
+		require.Equal(t, "alice", result.Content)
+	})
+
+	t.Run("reports step failure as tool error", func(t *testing.T) {
+		executor := &PostProcessToolExecutor{BaseExecutor: &staticToolExecutor{content: "not json"}, Steps: steps}
+		result, err := executor.Execute(context.Background(), call)
+		require.Error(t, err)
+		require.Equal(t, "call-1", result.ID)
+		require.Contains(t, result.Error, "post-process error")
+	})
+
+	t.Run("passes through executor errors", func(t *testing.T) {
+		executor := &PostProcessToolExecutor{BaseExecutor: &staticToolExecutor{err: errors.New("boom")}, Steps: steps}
+		result, err := executor.Execute(context.Background(), call)
+		require.EqualError(t, err, "boom")
+		require.Equal(t, "boom", result.Error)
+	})
+}
@@ -0,0 +1,125 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const defaultCurrency = "USD"
+
+// modelPricing is the pricing of a Model converted to prices per token
+type modelPricing struct {
+	input       float64
+	output      float64
+	cachedInput float64
+	currency    string
+}
+
+func parseModelPricing(spec *arkv1alpha1.ModelPricing) (*modelPricing, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	pricing := &modelPricing{currency: spec.Currency}
+	if pricing.currency == "" {
+		pricing.currency = defaultCurrency
+	}
+
+	cachedInput := spec.CachedInputPerMillion
+	if cachedInput == "" {
+		cachedInput = spec.InputPerMillion
+	}
+	prices := []struct {
+		name   string
+		value  string
+		target *float64
+	}{
+		{"inputPerMillion", spec.InputPerMillion, &pricing.input},
+		{"outputPerMillion", spec.OutputPerMillion, &pricing.output},
+		{"cachedInputPerMillion", cachedInput, &pricing.cachedInput},
+	}
+	for _, price := range prices {
+		perMillion, err := strconv.ParseFloat(price.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number: %q", price.name, price.value)
+		}
+		*price.target = perMillion / 1e6
+	}
+	return pricing, nil
+}
+
+// cost returns the cost of the usage, cached prompt tokens are priced separately from the other prompt tokens
+func (p *modelPricing) cost(usage arkv1alpha1.TokenUsage) float64 {
+	cached := min(usage.CachedPromptTokens, usage.PromptTokens)
+	return float64(usage.PromptTokens-cached)*p.input +
+		float64(cached)*p.cachedInput +
+		float64(usage.CompletionTokens)*p.output
+}
+
+// PriceModelUsage attributes usage reported without a model, such as the usage of an evaluator,
+// to the named Model and prices it. The usage is returned without a cost if the model cannot be loaded.
+func PriceModelUsage(ctx context.Context, k8sClient client.Client, name, namespace string, usage arkv1alpha1.TokenUsage) (arkv1alpha1.ModelUsage, error) {
+	modelUsage := arkv1alpha1.ModelUsage{Model: name, Namespace: namespace, TokenUsage: usage}
+
+	modelCRD, err := loadModelCRD(ctx, k8sClient, name, namespace)
+	if err != nil {
+		return modelUsage, err
+	}
+	pricing, err := parseModelPricing(modelCRD.Spec.Pricing)
+	if err != nil {
+		return modelUsage, fmt.Errorf("invalid pricing for model %s/%s: %w", namespace, name, err)
+	}
+	if pricing != nil {
+		modelUsage.Cost = arkv1alpha1.NewCost(pricing.cost(usage), pricing.currency)
+	}
+	return modelUsage, nil
+}
+
+// SumModelUsage adds up the usage of each model and the total cost. The total cost is nil when no
+// model has pricing or models are priced in different currencies.
+func SumModelUsage(usages ...[]arkv1alpha1.ModelUsage) ([]arkv1alpha1.ModelUsage, *arkv1alpha1.Cost) {
+	var result []arkv1alpha1.ModelUsage
+	for _, usage := range usages {
+		for _, model := range usage {
+			i := 0
+			for i < len(result) && (result[i].Model != model.Model || result[i].Namespace != model.Namespace) {
+				i++
+			}
+			if i == len(result) {
+				result = append(result, arkv1alpha1.ModelUsage{Model: model.Model, Namespace: model.Namespace})
+			}
+
+			sum := &result[i]
+			sum.PromptTokens += model.PromptTokens
+			sum.CompletionTokens += model.CompletionTokens
+			sum.TotalTokens += model.TotalTokens
+			sum.CachedPromptTokens += model.CachedPromptTokens
+			sum.ReasoningTokens += model.ReasoningTokens
+			if model.Cost != nil {
+				sum.Cost = arkv1alpha1.NewCost(sum.Cost.Value()+model.Cost.Value(), model.Cost.Currency)
+			}
+		}
+	}
+
+	var total float64
+	currency := ""
+	for _, model := range result {
+		if model.Cost == nil {
+			continue
+		}
+		if currency != "" && model.Cost.Currency != currency {
+			return result, nil
+		}
+		currency = model.Cost.Currency
+		total += model.Cost.Value()
+	}
+	if currency == "" {
+		return result, nil
+	}
+	return result, arkv1alpha1.NewCost(total, currency)
+}
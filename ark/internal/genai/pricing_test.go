@@ -0,0 +1,101 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestParseModelPricing(t *testing.T) {
+	pricing, err := parseModelPricing(nil)
+	require.NoError(t, err)
+	require.Nil(t, pricing)
+
+	pricing, err = parseModelPricing(&arkv1alpha1.ModelPricing{InputPerMillion: "2.5", OutputPerMillion: "10"})
+	require.NoError(t, err)
+	require.Equal(t, "USD", pricing.currency)
+	require.InDelta(t, 2.5e-6, pricing.input, 1e-12)
+	require.InDelta(t, 2.5e-6, pricing.cachedInput, 1e-12)
+	require.InDelta(t, 10e-6, pricing.output, 1e-12)
+
+	_, err = parseModelPricing(&arkv1alpha1.ModelPricing{InputPerMillion: "2.5", OutputPerMillion: "ten"})
+	require.ErrorContains(t, err, "outputPerMillion")
+}
+
+func TestModelPricingCost(t *testing.T) {
+	pricing, err := parseModelPricing(&arkv1alpha1.ModelPricing{
+		InputPerMillion:       "2",
+		OutputPerMillion:      "8",
+		CachedInputPerMillion: "0.5",
+		Currency:              "EUR",
+	})
+	require.NoError(t, err)
+
+	cost := pricing.cost(arkv1alpha1.TokenUsage{
+		PromptTokens:       1_000_000,
+		CachedPromptTokens: 400_000,
+		CompletionTokens:   500_000,
+		TotalTokens:        1_500_000,
+	})
+	// 600k uncached at 2, 400k cached at 0.5 and 500k completion at 8
+	require.InDelta(t, 1.2+0.2+4, cost, 1e-9)
+}
+
+func TestSumModelUsage(t *testing.T) {
+	gpt := arkv1alpha1.ModelUsage{
+		Model:      "gpt-4o",
+		Namespace:  "default",
+		TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150},
+		Cost:       arkv1alpha1.NewCost(0.1, "USD"),
+	}
+	claude := arkv1alpha1.ModelUsage{
+		Model:      "claude",
+		Namespace:  "default",
+		TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		Cost:       arkv1alpha1.NewCost(0.02, "USD"),
+	}
+
+	usage, total := SumModelUsage([]arkv1alpha1.ModelUsage{gpt, claude}, []arkv1alpha1.ModelUsage{gpt})
+	require.Len(t, usage, 2)
+	require.Equal(t, int64(300), usage[0].TotalTokens)
+	require.Equal(t, "0.2", usage[0].Cost.Amount)
+	require.Equal(t, &arkv1alpha1.Cost{Amount: "0.22", Currency: "USD"}, total)
+
+	unpriced := arkv1alpha1.ModelUsage{Model: "local", Namespace: "default", TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 10}}
+	_, total = SumModelUsage([]arkv1alpha1.ModelUsage{unpriced})
+	require.Nil(t, total)
+
+	claude.Cost = arkv1alpha1.NewCost(0.02, "EUR")
+	usage, total = SumModelUsage([]arkv1alpha1.ModelUsage{gpt, claude})
+	require.Len(t, usage, 2)
+	require.Nil(t, total, "costs in different currencies are not added up")
+}
+
+func TestPriceModelUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	model := &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "evaluator-model", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:    "openai",
+			Pricing: &arkv1alpha1.ModelPricing{InputPerMillion: "1", OutputPerMillion: "2"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model).Build()
+	usage := arkv1alpha1.TokenUsage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}
+
+	priced, err := PriceModelUsage(context.Background(), k8sClient, "evaluator-model", "default", usage)
+	require.NoError(t, err)
+	require.Equal(t, &arkv1alpha1.Cost{Amount: "0.002", Currency: "USD"}, priced.Cost)
+
+	missing, err := PriceModelUsage(context.Background(), k8sClient, "missing", "default", usage)
+	require.Error(t, err)
+	require.Equal(t, usage, missing.TokenUsage)
+	require.Nil(t, missing.Cost)
+}
@@ -0,0 +1,740 @@
+package genai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultAnthropicVersion   = "2023-06-01"
+	defaultAnthropicMaxTokens = 4096
+	// anthropicPromptCachingProperty adds cache breakpoints to the system prompt and the tools
+	// instead of being sent as a request parameter
+	anthropicPromptCachingProperty = "prompt_caching"
+	// defaultAnthropicSchemaTool names the tool structured output is requested with when the
+	// schema has no name
+	defaultAnthropicSchemaTool = "structured_output"
+)
+
+// AnthropicProvider calls the Anthropic Messages API and converts between its messages and the
+// OpenAI chat completion types used by the rest of the engine
+type AnthropicProvider struct {
+	Model        string
+	BaseURL      string
+	APIKey       string
+	Version      string
+	Headers      map[string]string
+	Properties   map[string]string
+	outputSchema *runtime.RawExtension
+	schemaName   string
+	generation   *GenerationParameters
+}
+
+// AnthropicError is an error response of the Anthropic API
+type AnthropicError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *AnthropicError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("anthropic %s: %s", e.Type, e.Message)
+	}
+	return fmt.Sprintf("anthropic %s (%d): %s", e.Type, e.StatusCode, e.Message)
+}
+
+type anthropicRequest struct {
+	Model      string                  `json:"model"`
+	MaxTokens  int                     `json:"max_tokens"`
+	System     []anthropicContentBlock `json:"system,omitempty"`
+	Messages   []anthropicMessage      `json:"messages"`
+	Tools      []anthropicTool         `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice    `json:"tool_choice,omitempty"`
+	Stream     bool                    `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	// Set on tool_use blocks
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+	// Set on tool_result blocks
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	// Set on image blocks
+	Source       *anthropicImageSource  `json:"source,omitempty"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type anthropicCacheControl struct {
+	Type string `json:"type"`
+}
+
+type anthropicTool struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]any         `json:"input_schema"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID         string                  `json:"id"`
+	Model      string                  `json:"model"`
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// anthropicStreamEvent is the data of a server-sent event of a streamed message
+type anthropicStreamEvent struct {
+	Type         string                `json:"type"`
+	Index        int                   `json:"index"`
+	Message      anthropicResponse     `json:"message"`
+	ContentBlock anthropicContentBlock `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (ap *AnthropicProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	ap.outputSchema = schema
+	ap.schemaName = schemaName
+}
+
+func (ap *AnthropicProvider) SetGenerationParameters(params *GenerationParameters) {
+	ap.generation = params
+}
+
+// ChatCompletion sends the messages to the Messages API. The API returns a single choice, so n is ignored.
+func (ap *AnthropicProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	body, err := ap.buildRequest(messages, false, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ap.send(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Anthropic response: %w", err)
+	}
+
+	return ap.convertResponse(response), nil
+}
+
+func (ap *AnthropicProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	body, err := ap.buildRequest(messages, true, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ap.send(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var fullResponse *openai.ChatCompletion
+	toolCallsMap := make(map[int64]*openai.ChatCompletionMessageToolCall)
+	stream := &anthropicStreamState{
+		schemaTool:  ap.schemaToolName(),
+		created:     time.Now().Unix(),
+		toolCalls:   make(map[int]int64),
+		schemaBlock: -1,
+	}
+
+	err = readServerSentEvents(resp.Body, func(data []byte) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to decode Anthropic stream event: %w", err)
+		}
+
+		chunk, err := stream.toChunk(event)
+		if err != nil || chunk == nil {
+			return err
+		}
+		if err := streamFunc(chunk); err != nil {
+			return err
+		}
+		accumulateStreamChunk(chunk, &fullResponse, toolCallsMap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fullResponse == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+
+	if len(toolCallsMap) > 0 && len(fullResponse.Choices) > 0 {
+		toolCalls := make([]openai.ChatCompletionMessageToolCall, 0, len(toolCallsMap))
+		for i := int64(0); i < int64(len(toolCallsMap)); i++ {
+			toolCalls = append(toolCalls, *toolCallsMap[i])
+		}
+		fullResponse.Choices[0].Message.ToolCalls = toolCalls
+	}
+	fullResponse.Usage = stream.usage.toCompletionUsage()
+
+	return fullResponse, nil
+}
+
+// anthropicStreamState maps the events of a streamed message to OpenAI chat completion chunks
+type anthropicStreamState struct {
+	schemaTool string
+	id         string
+	model      string
+	created    int64
+	usage      anthropicUsage
+	// toolCalls maps the index of a tool_use content block to the index of its tool call
+	toolCalls map[int]int64
+	// schemaBlock is the index of the content block carrying structured output, or -1
+	schemaBlock  int
+	schemaCalled bool
+	// heldText keeps text back while the structured output can still replace it
+	heldText string
+}
+
+// toChunk returns the chunk for an event, or nil for events that carry nothing to stream
+func (s *anthropicStreamState) toChunk(event anthropicStreamEvent) (*openai.ChatCompletionChunk, error) {
+	switch event.Type {
+	case "message_start":
+		s.id = event.Message.ID
+		s.model = event.Message.Model
+		s.usage = event.Message.Usage
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{Role: RoleAssistant}, ""), nil
+
+	case "content_block_start":
+		if event.ContentBlock.Type != "tool_use" {
+			return nil, nil
+		}
+		if s.schemaTool != "" && event.ContentBlock.Name == s.schemaTool {
+			// Structured output replaces the text, as in complete responses
+			s.schemaBlock = event.Index
+			s.schemaCalled = true
+			s.heldText = ""
+			return nil, nil
+		}
+		index := int64(len(s.toolCalls))
+		s.toolCalls[event.Index] = index
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    index,
+				ID:       event.ContentBlock.ID,
+				Type:     "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: event.ContentBlock.Name},
+			}},
+		}, ""), nil
+
+	case "content_block_delta":
+		switch event.Delta.Type {
+		case "text_delta":
+			if s.schemaTool != "" {
+				if !s.schemaCalled {
+					s.heldText += event.Delta.Text
+				}
+				return nil, nil
+			}
+			return s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta.Text}, ""), nil
+		case "input_json_delta":
+			if event.Index == s.schemaBlock {
+				return s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta.PartialJSON}, ""), nil
+			}
+			index, ok := s.toolCalls[event.Index]
+			if !ok || event.Delta.PartialJSON == "" {
+				return nil, nil
+			}
+			return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+				ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+					Index:    index,
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: event.Delta.PartialJSON},
+				}},
+			}, ""), nil
+		}
+		return nil, nil
+
+	case "message_delta":
+		// Usage in message_delta is cumulative, fields that are not reported again keep their value
+		if event.Usage.OutputTokens > 0 {
+			s.usage.OutputTokens = event.Usage.OutputTokens
+		}
+		if event.Usage.InputTokens > 0 {
+			s.usage.InputTokens = event.Usage.InputTokens
+		}
+		chunk := s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: s.heldText}, anthropicFinishReason(event.Delta.StopReason, s.schemaCalled && len(s.toolCalls) == 0))
+		s.heldText = ""
+		chunk.Usage = s.usage.toCompletionUsage()
+		return chunk, nil
+
+	case "error":
+		return nil, &AnthropicError{Type: event.Error.Type, Message: event.Error.Message}
+	}
+
+	// ping, content_block_stop and message_stop carry nothing to stream
+	return nil, nil
+}
+
+func (s *anthropicStreamState) chunk(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) *openai.ChatCompletionChunk {
+	return &openai.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+		Choices: []openai.ChatCompletionChunkChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
+
+// readServerSentEvents calls handle with the data of every event in a text/event-stream response
+func readServerSentEvents(body io.Reader, handle func(data []byte) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			if data.Len() > 0 {
+				if err := handle(data.Bytes()); err != nil {
+					return err
+				}
+				data.Reset()
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	if data.Len() > 0 {
+		return handle(data.Bytes())
+	}
+	return nil
+}
+
+func (ap *AnthropicProvider) buildRequest(messages []Message, stream bool, tools ...[]openai.ChatCompletionToolParam) ([]byte, error) {
+	system, anthropicMessages := convertAnthropicMessages(messages)
+
+	request := anthropicRequest{
+		Model:     ap.Model,
+		MaxTokens: defaultAnthropicMaxTokens,
+		System:    system,
+		Messages:  anthropicMessages,
+		Stream:    stream,
+	}
+	if len(tools) > 0 {
+		request.Tools = convertAnthropicTools(tools[0])
+	}
+
+	// The Messages API has no response format, structured output is requested as a tool call
+	if schemaTool := ap.schemaToolName(); schemaTool != "" {
+		var schema map[string]any
+		if err := json.Unmarshal(ap.outputSchema.Raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse output schema: %w", err)
+		}
+		if len(request.Tools) == 0 {
+			request.ToolChoice = &anthropicToolChoice{Type: "tool", Name: schemaTool}
+		}
+		request.Tools = append(request.Tools, anthropicTool{
+			Name:        schemaTool,
+			Description: "Respond with the final answer by calling this tool.",
+			InputSchema: schema,
+		})
+	}
+
+	if ap.Properties[anthropicPromptCachingProperty] == TrueString {
+		cache := &anthropicCacheControl{Type: "ephemeral"}
+		if len(request.System) > 0 {
+			request.System[len(request.System)-1].CacheControl = cache
+		}
+		if len(request.Tools) > 0 {
+			request.Tools[len(request.Tools)-1].CacheControl = cache
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Anthropic request: %w", err)
+	}
+	return applyAnthropicProperties(ap.Properties, ap.generation, body)
+}
+
+// anthropicGenerationFields are the generation parameters the Messages API supports
+var anthropicGenerationFields = generationFieldNames{
+	temperature: "temperature",
+	topP:        "top_p",
+	maxTokens:   "max_tokens",
+	stop:        "stop_sequences",
+}
+
+// applyAnthropicProperties sets the model properties as request parameters. Values that are valid
+// JSON, such as numbers or arrays for stop_sequences, are sent as JSON, other values as strings.
+// Generation parameters replace properties.
+func applyAnthropicProperties(properties map[string]string, generation *GenerationParameters, body []byte) ([]byte, error) {
+	if len(properties) == 0 && generation == nil {
+		return body, nil
+	}
+
+	var request map[string]any
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("failed to apply model properties: %w", err)
+	}
+	for key, value := range properties {
+		if value == "" || key == anthropicPromptCachingProperty {
+			continue
+		}
+		request[key] = parsePropertyValue(value)
+	}
+	for key, value := range generation.fields(anthropicGenerationFields) {
+		request[key] = value
+	}
+
+	return json.Marshal(request)
+}
+
+func (ap *AnthropicProvider) send(ctx context.Context, body []byte) (*http.Response, error) {
+	return ap.do(ctx, http.MethodPost, ap.apiURL("messages"), bytes.NewReader(body))
+}
+
+// ProbeModelList checks the model with the models endpoint, which resolves model aliases and
+// does not generate tokens
+func (ap *AnthropicProvider) ProbeModelList(ctx context.Context) error {
+	resp, err := ap.do(ctx, http.MethodGet, ap.apiURL("models/"+url.PathEscape(ap.Model)), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (ap *AnthropicProvider) do(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Anthropic request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("x-api-key", ap.APIKey)
+	req.Header.Set("anthropic-version", ap.Version)
+	for name, value := range ap.Headers {
+		req.Header.Set(name, value)
+	}
+
+	var httpClient *http.Client
+	if IsProbeContext(ctx) {
+		httpClient = common.NewHTTPClientWithoutTracing()
+	} else {
+		httpClient = common.NewHTTPClientWithLogging(ctx)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Anthropic API: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, parseAnthropicError(resp)
+	}
+	return resp, nil
+}
+
+// apiURL returns the URL of an API path, accepting base URLs with and without the /v1 path
+func (ap *AnthropicProvider) apiURL(path string) string {
+	baseURL := strings.TrimSuffix(ap.BaseURL, "/")
+	if strings.HasSuffix(baseURL, "/v1") {
+		return baseURL + "/" + path
+	}
+	return baseURL + "/v1/" + path
+}
+
+func parseAnthropicError(resp *http.Response) error {
+	apiErr := &AnthropicError{StatusCode: resp.StatusCode, Type: "api_error", Message: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, &body); err == nil && body.Error.Message != "" {
+		apiErr.Type = body.Error.Type
+		apiErr.Message = body.Error.Message
+	}
+	return apiErr
+}
+
+// schemaToolName returns the name of the structured output tool, or "" without an output schema
+func (ap *AnthropicProvider) schemaToolName() string {
+	if ap.outputSchema == nil || ap.outputSchema.Raw == nil {
+		return ""
+	}
+	if ap.schemaName == "" {
+		return defaultAnthropicSchemaTool
+	}
+	return ap.schemaName
+}
+
+// convertAnthropicMessages splits the system prompt from the conversation. Tool results become
+// tool_result blocks of user messages, and consecutive messages of the same role are merged as
+// the API requires all results of a turn in the message that follows the tool calls.
+func convertAnthropicMessages(messages []Message) ([]anthropicContentBlock, []anthropicMessage) {
+	var system []anthropicContentBlock
+	var result []anthropicMessage
+
+	appendBlocks := func(role string, blocks []anthropicContentBlock) {
+		if len(blocks) == 0 {
+			return
+		}
+		if last := len(result) - 1; last >= 0 && result[last].Role == role {
+			result[last].Content = append(result[last].Content, blocks...)
+			return
+		}
+		result = append(result, anthropicMessage{Role: role, Content: blocks})
+	}
+
+	for _, msg := range messages {
+		switch {
+		case msg.OfSystem != nil:
+			system = append(system, anthropicTextBlocks(msg.OfSystem.Content.OfString.Value, msg.OfSystem.Content.OfArrayOfContentParts)...)
+		case msg.OfDeveloper != nil:
+			system = append(system, anthropicTextBlocks(msg.OfDeveloper.Content.OfString.Value, msg.OfDeveloper.Content.OfArrayOfContentParts)...)
+		case msg.OfUser != nil:
+			appendBlocks(RoleUser, convertAnthropicUserContent(msg.OfUser.Content))
+		case msg.OfAssistant != nil:
+			appendBlocks(RoleAssistant, convertAnthropicAssistantContent(msg.OfAssistant))
+		case msg.OfTool != nil:
+			content := msg.OfTool.Content.OfString.Value
+			for _, part := range msg.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			appendBlocks(RoleUser, []anthropicContentBlock{{Type: "tool_result", ToolUseID: msg.OfTool.ToolCallID, Content: content}})
+		}
+	}
+
+	return system, result
+}
+
+func anthropicTextBlocks(text string, parts []openai.ChatCompletionContentPartTextParam) []anthropicContentBlock {
+	var blocks []anthropicContentBlock
+	if text != "" {
+		blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+	}
+	for _, part := range parts {
+		if part.Text != "" {
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: part.Text})
+		}
+	}
+	return blocks
+}
+
+func convertAnthropicUserContent(content openai.ChatCompletionUserMessageParamContentUnion) []anthropicContentBlock {
+	var blocks []anthropicContentBlock
+	if text := content.OfString.Value; text != "" {
+		blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+	}
+	for _, part := range content.OfArrayOfContentParts {
+		switch {
+		case part.OfText != nil && part.OfText.Text != "":
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: part.OfText.Text})
+		case part.OfImageURL != nil:
+			blocks = append(blocks, anthropicContentBlock{Type: "image", Source: anthropicImage(part.OfImageURL.ImageURL.URL)})
+		}
+	}
+	return blocks
+}
+
+// anthropicImage turns an image URL into an image source, inlining data URLs as base64
+func anthropicImage(url string) *anthropicImageSource {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if mediaType, data, ok := strings.Cut(rest, ";base64,"); ok {
+			return &anthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}
+		}
+	}
+	return &anthropicImageSource{Type: "url", URL: url}
+}
+
+func convertAnthropicAssistantContent(msg *openai.ChatCompletionAssistantMessageParam) []anthropicContentBlock {
+	var blocks []anthropicContentBlock
+	if text := msg.Content.OfString.Value; text != "" {
+		blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+	}
+	for _, part := range msg.Content.OfArrayOfContentParts {
+		if part.OfText != nil && part.OfText.Text != "" {
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: part.OfText.Text})
+		}
+	}
+	for _, toolCall := range msg.ToolCalls {
+		input := json.RawMessage(toolCall.Function.Arguments)
+		if !json.Valid(input) {
+			input = json.RawMessage("{}")
+		}
+		blocks = append(blocks, anthropicContentBlock{Type: "tool_use", ID: toolCall.ID, Name: toolCall.Function.Name, Input: input})
+	}
+	return blocks
+}
+
+func convertAnthropicTools(tools []openai.ChatCompletionToolParam) []anthropicTool {
+	anthropicTools := make([]anthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		inputSchema := map[string]any(tool.Function.Parameters)
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		anthropicTools = append(anthropicTools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description.Value,
+			InputSchema: inputSchema,
+		})
+	}
+	return anthropicTools
+}
+
+func (ap *AnthropicProvider) convertResponse(response anthropicResponse) *openai.ChatCompletion {
+	schemaTool := ap.schemaToolName()
+	message := openai.ChatCompletionMessage{Role: RoleAssistant}
+	schemaCalled := false
+
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			if !schemaCalled {
+				message.Content += block.Text
+			}
+		case "tool_use":
+			if schemaTool != "" && block.Name == schemaTool {
+				// Structured output replaces any text, so that the content stays valid JSON
+				message.Content = string(block.Input)
+				schemaCalled = true
+				continue
+			}
+			message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+				ID:   block.ID,
+				Type: "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      block.Name,
+					Arguments: string(block.Input),
+				},
+			})
+		}
+	}
+
+	return &openai.ChatCompletion{
+		ID:      response.ID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   response.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: anthropicFinishReason(response.StopReason, schemaCalled && len(message.ToolCalls) == 0),
+		}},
+		Usage: response.Usage.toCompletionUsage(),
+	}
+}
+
+// anthropicFinishReason maps a stop reason to an OpenAI finish reason. A turn that only returned
+// structured output is complete rather than waiting for tool results.
+func anthropicFinishReason(stopReason string, onlyStructuredOutput bool) string {
+	switch stopReason {
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		if onlyStructuredOutput {
+			return "stop"
+		}
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	case "":
+		return ""
+	default:
+		return "stop"
+	}
+}
+
+// toCompletionUsage counts cached input tokens as prompt tokens, as OpenAI does
+func (u anthropicUsage) toCompletionUsage() openai.CompletionUsage {
+	promptTokens := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return openai.CompletionUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      promptTokens + u.OutputTokens,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{
+			CachedTokens: u.CacheReadInputTokens,
+		},
+	}
+}
+
+func (ap *AnthropicProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl": ap.BaseURL,
+	}
+	if ap.APIKey != "" {
+		config["apiKey"] = ap.APIKey
+	}
+	if ap.Version != "" {
+		config["version"] = ap.Version
+	}
+	return config
+}
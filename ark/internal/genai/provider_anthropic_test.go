@@ -0,0 +1,290 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newAnthropicTestProvider(baseURL string, properties map[string]string) *AnthropicProvider {
+	return &AnthropicProvider{
+		Model:      "claude-sonnet-4-5",
+		BaseURL:    baseURL,
+		APIKey:     "sk-ant-test",
+		Version:    defaultAnthropicVersion,
+		Headers:    map[string]string{"X-Gateway": "ark"},
+		Properties: properties,
+	}
+}
+
+var weatherTool = openai.ChatCompletionToolParam{
+	Function: openai.FunctionDefinitionParam{
+		Name:        "get_weather",
+		Description: openai.String("Current weather of a city"),
+		Parameters: openai.FunctionParameters{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		},
+	},
+}
+
+func TestAnthropicChatCompletion(t *testing.T) {
+	stub := &providerStub{body: `{
+		"id": "msg_1", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+		"content": [
+			{"type": "text", "text": "Let me check."},
+			{"type": "tool_use", "id": "toolu_2", "name": "get_weather", "input": {"city": "Paris"}}
+		],
+		"usage": {"input_tokens": 20, "output_tokens": 15, "cache_creation_input_tokens": 5, "cache_read_input_tokens": 100}
+	}`}
+	server := stub.start(t)
+	provider := newAnthropicTestProvider(server.URL, map[string]string{"temperature": "0.2", "max_tokens": "1024", "prompt_caching": "true"})
+
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "toolu_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"Lyon"}`}},
+	}
+	messages := []Message{
+		NewSystemMessage("You are a weather assistant."),
+		NewUserMessage("Weather in Lyon and Paris?"),
+		Message(assistant),
+		Message(openai.ToolMessage("sunny", "toolu_1")),
+		NewUserMessage("And Paris?"),
+	}
+
+	completion, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	t.Run("sends the conversation in the Messages API format", func(t *testing.T) {
+		require.Len(t, stub.requests, 1)
+		request := stub.requests[0]
+		require.Equal(t, []string{"/v1/messages"}, stub.paths)
+		require.Equal(t, "sk-ant-test", stub.headers[0].Get("x-api-key"))
+		require.Equal(t, "2023-06-01", stub.headers[0].Get("anthropic-version"))
+		require.Equal(t, "ark", stub.headers[0].Get("X-Gateway"))
+
+		require.Equal(t, 0.2, request["temperature"])
+		require.Equal(t, float64(1024), request["max_tokens"])
+		require.NotContains(t, request, "prompt_caching")
+
+		require.Equal(t, []any{map[string]any{"type": "text", "text": "You are a weather assistant.", "cache_control": map[string]any{"type": "ephemeral"}}}, request["system"])
+		require.Equal(t, []any{map[string]any{
+			"name":          "get_weather",
+			"description":   "Current weather of a city",
+			"input_schema":  map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+			"cache_control": map[string]any{"type": "ephemeral"},
+		}}, request["tools"])
+
+		require.Equal(t, []any{
+			map[string]any{"role": "user", "content": []any{map[string]any{"type": "text", "text": "Weather in Lyon and Paris?"}}},
+			map[string]any{"role": "assistant", "content": []any{map[string]any{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": map[string]any{"city": "Lyon"}}}},
+			map[string]any{"role": "user", "content": []any{
+				map[string]any{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"},
+				map[string]any{"type": "text", "text": "And Paris?"},
+			}},
+		}, request["messages"])
+	})
+
+	t.Run("converts the response to a chat completion", func(t *testing.T) {
+		require.Equal(t, "msg_1", completion.ID)
+		message := completion.Choices[0].Message
+		require.Equal(t, "Let me check.", message.Content)
+		require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+		require.Len(t, message.ToolCalls, 1)
+		require.Equal(t, "toolu_2", message.ToolCalls[0].ID)
+		require.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+		require.JSONEq(t, `{"city":"Paris"}`, message.ToolCalls[0].Function.Arguments)
+
+		require.Equal(t, int64(125), completion.Usage.PromptTokens)
+		require.Equal(t, int64(15), completion.Usage.CompletionTokens)
+		require.Equal(t, int64(140), completion.Usage.TotalTokens)
+		require.Equal(t, int64(100), completion.Usage.PromptTokensDetails.CachedTokens)
+	})
+}
+
+func TestAnthropicChatCompletionStream(t *testing.T) {
+	stub := &providerStub{events: []string{
+		`{"type": "message_start", "message": {"id": "msg_2", "model": "claude-sonnet-4-5", "content": [], "usage": {"input_tokens": 30, "output_tokens": 1}}}`,
+		`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+		`{"type": "ping"}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Checking "}}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "now."}}`,
+		`{"type": "content_block_stop", "index": 0}`,
+		`{"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_3", "name": "get_weather", "input": {}}}`,
+		`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"city\": "}}`,
+		`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "\"Paris\"}"}}`,
+		`{"type": "content_block_stop", "index": 1}`,
+		`{"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 42}}`,
+		`{"type": "message_stop"}`,
+	}}
+	server := stub.start(t)
+	provider := newAnthropicTestProvider(server.URL+"/v1/", nil)
+
+	var chunks []*openai.ChatCompletionChunk
+	completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Weather in Paris?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, true, stub.requests[0]["stream"])
+	require.Equal(t, float64(defaultAnthropicMaxTokens), stub.requests[0]["max_tokens"])
+
+	require.Len(t, chunks, 7)
+	require.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	require.Equal(t, "Checking ", chunks[1].Choices[0].Delta.Content)
+	require.Equal(t, "toolu_3", chunks[3].Choices[0].Delta.ToolCalls[0].ID)
+	require.Equal(t, "get_weather", chunks[3].Choices[0].Delta.ToolCalls[0].Function.Name)
+	require.Equal(t, `"Paris"}`, chunks[5].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	require.Equal(t, "tool_calls", chunks[6].Choices[0].FinishReason)
+	require.Equal(t, int64(72), chunks[6].Usage.TotalTokens)
+
+	require.Equal(t, "msg_2", completion.ID)
+	require.Equal(t, "Checking now.", completion.Choices[0].Message.Content)
+	require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.JSONEq(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, int64(30), completion.Usage.PromptTokens)
+	require.Equal(t, int64(42), completion.Usage.CompletionTokens)
+}
+
+func TestAnthropicStructuredOutput(t *testing.T) {
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"answer": {"type": "string"}}}`)}
+
+	t.Run("forces the schema tool and returns its input as content", func(t *testing.T) {
+		stub := &providerStub{body: `{
+			"id": "msg_3", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+			"content": [{"type": "tool_use", "id": "toolu_4", "name": "answer-schema", "input": {"answer": "42"}}],
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`}
+		server := stub.start(t)
+		provider := newAnthropicTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer-schema")
+
+		completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("question")}, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": "tool", "name": "answer-schema"}, stub.requests[0]["tool_choice"])
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+		require.Empty(t, completion.Choices[0].Message.ToolCalls)
+		require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	})
+
+	t.Run("streams the schema tool input as content", func(t *testing.T) {
+		stub := &providerStub{events: []string{
+			`{"type": "message_start", "message": {"id": "msg_4", "model": "claude-sonnet-4-5", "usage": {"input_tokens": 10}}}`,
+			`{"type": "content_block_start", "index": 0, "content_block": {"type": "tool_use", "id": "toolu_5", "name": "answer-schema", "input": {}}}`,
+			`{"type": "content_block_delta", "index": 0, "delta": {"type": "input_json_delta", "partial_json": "{\"answer\": \"42\"}"}}`,
+			`{"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 5}}`,
+		}}
+		server := stub.start(t)
+		provider := newAnthropicTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer-schema")
+
+		completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("question")}, 1, func(*openai.ChatCompletionChunk) error { return nil }, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.NotContains(t, stub.requests[0], "tool_choice", "the model may still call other tools first")
+		require.Len(t, stub.requests[0]["tools"], 2)
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+		require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	})
+
+	t.Run("leaves out text before the structured output", func(t *testing.T) {
+		stub := &providerStub{body: `{
+			"id": "msg_6", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+			"content": [
+				{"type": "text", "text": "Here is the answer."},
+				{"type": "tool_use", "id": "toolu_6", "name": "answer-schema", "input": {"answer": "42"}}
+			],
+			"usage": {"input_tokens": 10, "output_tokens": 9}
+		}`}
+		server := stub.start(t)
+		provider := newAnthropicTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer-schema")
+
+		completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("question")}, 1, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+
+		stub = &providerStub{events: []string{
+			`{"type": "message_start", "message": {"id": "msg_7", "model": "claude-sonnet-4-5", "usage": {"input_tokens": 10}}}`,
+			`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+			`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Here is the answer."}}`,
+			`{"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_7", "name": "answer-schema", "input": {}}}`,
+			`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"answer\": \"42\"}"}}`,
+			`{"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 9}}`,
+		}}
+		server = stub.start(t)
+		provider = newAnthropicTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer-schema")
+
+		var streamed string
+		completion, err = provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("question")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+			streamed += chunk.Choices[0].Delta.Content
+			return nil
+		}, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"answer":"42"}`, streamed)
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+	})
+
+	t.Run("streams held back text when other tools are called", func(t *testing.T) {
+		stub := &providerStub{events: []string{
+			`{"type": "message_start", "message": {"id": "msg_8", "model": "claude-sonnet-4-5", "usage": {"input_tokens": 10}}}`,
+			`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Let me check."}}`,
+			`{"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_9", "name": "get_weather", "input": {}}}`,
+			`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"city\": \"Paris\"}"}}`,
+			`{"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 9}}`,
+		}}
+		server := stub.start(t)
+		provider := newAnthropicTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer-schema")
+
+		completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("question")}, 1, func(*openai.ChatCompletionChunk) error { return nil }, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.Equal(t, "Let me check.", completion.Choices[0].Message.Content)
+		require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+		require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	})
+}
+
+func TestAnthropicErrors(t *testing.T) {
+	t.Run("returns API errors with a stable probe message", func(t *testing.T) {
+		stub := &providerStub{status: http.StatusUnauthorized, body: `{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`}
+		server := stub.start(t)
+
+		_, err := newAnthropicTestProvider(server.URL, nil).ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+		var apiErr *AnthropicError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, "authentication_error", apiErr.Type)
+		require.Equal(t, "invalid x-api-key (401)", extractStableError(err, 0))
+	})
+
+	t.Run("returns errors sent in the stream", func(t *testing.T) {
+		stub := &providerStub{events: []string{
+			`{"type": "message_start", "message": {"id": "msg_5", "model": "claude-sonnet-4-5", "usage": {"input_tokens": 10}}}`,
+			`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`,
+		}}
+		server := stub.start(t)
+
+		_, err := newAnthropicTestProvider(server.URL, nil).ChatCompletionStream(context.Background(), []Message{NewUserMessage("hi")}, 1, func(*openai.ChatCompletionChunk) error { return nil })
+		require.ErrorContains(t, err, "anthropic overloaded_error: Overloaded")
+	})
+}
+
+func TestConvertAnthropicUserContent(t *testing.T) {
+	content := openai.ChatCompletionUserMessageParamContentUnion{OfArrayOfContentParts: []openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What is in these images?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,iVBORw0KGgo="}),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "https://example.com/cat.jpg"}),
+	}}
+
+	blocks := convertAnthropicUserContent(content)
+	require.Len(t, blocks, 3)
+	require.Equal(t, "What is in these images?", blocks[0].Text)
+	require.Equal(t, &anthropicImageSource{Type: "base64", MediaType: "image/png", Data: "iVBORw0KGgo="}, blocks[1].Source)
+	require.Equal(t, &anthropicImageSource{Type: "url", URL: "https://example.com/cat.jpg"}, blocks[2].Source)
+}
@@ -16,10 +16,14 @@ type AzureProvider struct {
 	BaseURL      string
 	APIVersion   string
 	APIKey       string
+	API          string
 	Headers      map[string]string
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	generation   *GenerationParameters
+	// reasoningItems are kept between the calls of the Responses API
+	reasoningItems responsesReasoningItems
 }
 
 func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -27,7 +31,15 @@ func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNam
 	ap.schemaName = schemaName
 }
 
+func (ap *AzureProvider) SetGenerationParameters(params *GenerationParameters) {
+	ap.generation = params
+}
+
 func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if ap.API == ModelAPIResponses {
+		return ap.responses(ctx).ChatCompletion(ctx, messages, tools...)
+	}
+
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
 		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
@@ -40,6 +52,7 @@ func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message,
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyGenerationToParams(ap.generation, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -66,6 +79,7 @@ func (ap *AzureProvider) prepareStreamParams(messages []Message, n int64, tools
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyGenerationToParams(ap.generation, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -78,6 +92,10 @@ func (ap *AzureProvider) prepareStreamParams(messages []Message, n int64, tools
 }
 
 func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if ap.API == ModelAPIResponses {
+		return ap.responses(ctx).ChatCompletionStream(ctx, messages, streamFunc, tools...)
+	}
+
 	params := ap.prepareStreamParams(messages, n, tools...)
 	client := ap.createClient(ctx)
 	stream := client.Chat.Completions.NewStreaming(ctx, params)
@@ -138,6 +156,37 @@ func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Me
 }
 
 func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
+	return ap.newClient(ctx, fmt.Sprintf("%s/openai/deployments/%s", ap.BaseURL, ap.Model))
+}
+
+// ProbeModelList lists the models of the Azure OpenAI resource. This checks the endpoint and the
+// key, the models endpoint does not list deployments.
+func (ap *AzureProvider) ProbeModelList(ctx context.Context) error {
+	client := ap.newClient(ctx, fmt.Sprintf("%s/openai", ap.BaseURL))
+	_, err := client.Models.List(ctx)
+	return err
+}
+
+// Embed calls the embeddings endpoint of the deployment
+func (ap *AzureProvider) Embed(ctx context.Context, inputs []string, dimensions *int64) (*EmbeddingResponse, error) {
+	return createOpenAIEmbeddings(ctx, ap.createClient(ctx), ap.Model, inputs, dimensions)
+}
+
+// responses calls the Responses API, which is not scoped to a deployment and takes the
+// deployment name as the model
+func (ap *AzureProvider) responses(ctx context.Context) responsesAPI {
+	return responsesAPI{
+		client:       ap.newClient(ctx, fmt.Sprintf("%s/openai", ap.BaseURL)),
+		model:        ap.Model,
+		properties:   ap.Properties,
+		outputSchema: ap.outputSchema,
+		schemaName:   ap.schemaName,
+		generation:   ap.generation,
+		reasoning:    &ap.reasoningItems,
+	}
+}
+
+func (ap *AzureProvider) newClient(ctx context.Context, baseURL string) openai.Client {
 	var httpClient *http.Client
 	if IsProbeContext(ctx) {
 		httpClient = common.NewHTTPClientWithoutTracing()
@@ -145,9 +194,8 @@ func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
 		httpClient = common.NewHTTPClientWithLogging(ctx)
 	}
 
-	deploymentURL := fmt.Sprintf("%s/openai/deployments/%s", ap.BaseURL, ap.Model)
 	options := []option.RequestOption{
-		option.WithBaseURL(deploymentURL),
+		option.WithBaseURL(baseURL),
 		option.WithHeader("api-key", ap.APIKey),
 		option.WithAPIKey(ap.APIKey),
 		option.WithHTTPClient(httpClient),
@@ -169,5 +217,8 @@ func (ap *AzureProvider) BuildConfig() map[string]any {
 	if ap.APIKey != "" {
 		config["apiKey"] = ap.APIKey
 	}
+	if ap.API != "" {
+		config["api"] = ap.API
+	}
 	return config
 }
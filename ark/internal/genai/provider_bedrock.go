@@ -2,18 +2,43 @@ package genai
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+const (
+	defaultBedrockMaxTokens  = 4096
+	defaultBedrockSchemaTool = "structured_output"
+)
+
+// bedrockInferenceProperties are the properties sent as the Converse inference configuration,
+// any other property is passed to the model as an additional request field
+var bedrockInferenceProperties = map[string]bool{
+	"max_tokens":     true,
+	"temperature":    true,
+	"top_p":          true,
+	"stop":           true,
+	"stop_sequences": true,
+}
+
+// BedrockModel calls models through the Bedrock Converse API, which provides one request format
+// for all models that support it
 type BedrockModel struct {
 	Model           string
 	Region          string
@@ -26,45 +51,7 @@ type BedrockModel struct {
 	client          *bedrockruntime.Client
 	outputSchema    *runtime.RawExtension
 	schemaName      string
-}
-
-type bedrockMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type bedrockRequest struct {
-	Messages         []bedrockMessage `json:"messages"`
-	MaxTokens        int              `json:"max_tokens"`
-	Temperature      float64          `json:"temperature"`
-	SystemPrompt     string           `json:"system,omitempty"`
-	AnthropicVersion string           `json:"anthropic_version,omitempty"`
-	Tools            []bedrockTool    `json:"tools,omitempty"`
-}
-
-type bedrockTool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
-}
-
-type bedrockResponse struct {
-	Content    []bedrockContent `json:"content"`
-	ID         string           `json:"id"`
-	Model      string           `json:"model"`
-	StopReason string           `json:"stop_reason"`
-	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
-}
-
-type bedrockContent struct {
-	Text  string                 `json:"text,omitempty"`
-	Type  string                 `json:"type"`
-	ID    string                 `json:"id,omitempty"`
-	Name  string                 `json:"name,omitempty"`
-	Input map[string]interface{} `json:"input,omitempty"`
+	generation      *GenerationParameters
 }
 
 func NewBedrockModel(model, region, baseURL, accessKeyID, secretAccessKey, sessionToken, modelArn string, properties map[string]string) *BedrockModel {
@@ -113,257 +100,662 @@ func (bm *BedrockModel) SetOutputSchema(schema *runtime.RawExtension, schemaName
 	bm.schemaName = schemaName
 }
 
+func (bm *BedrockModel) SetGenerationParameters(params *GenerationParameters) {
+	bm.generation = params
+}
+
+// ChatCompletion sends the messages to the Converse API. The API returns a single choice, so n is ignored.
 func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	var toolsParam []openai.ChatCompletionToolParam
-	if len(tools) > 0 {
-		toolsParam = tools[0]
-	}
 	if err := bm.initClient(ctx); err != nil {
 		return nil, err
 	}
 
-	bedrockMessages, systemPrompt := bm.convertMessages(messages)
-	bedrockTools := bm.convertTools(toolsParam)
+	input, err := bm.buildRequest(messages, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := bm.client.Converse(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+	}
 
-	request := bm.buildRequest(bedrockMessages, systemPrompt, bedrockTools)
+	return bm.convertResponse(output), nil
+}
 
-	if strings.Contains(strings.ToLower(bm.Model), "claude") {
-		request.AnthropicVersion = "bedrock-2023-05-31"
+func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if err := bm.initClient(ctx); err != nil {
+		return nil, err
 	}
 
-	requestBody, err := json.Marshal(request)
+	input, err := bm.buildRequest(messages, tools...)
 	if err != nil {
 		return nil, err
 	}
 
-	modelID := bm.Model
-	if bm.ModelArn != "" {
-		modelID = bm.ModelArn
+	output, err := bm.client.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+		ModelId:                      input.ModelId,
+		Messages:                     input.Messages,
+		System:                       input.System,
+		InferenceConfig:              input.InferenceConfig,
+		ToolConfig:                   input.ToolConfig,
+		AdditionalModelRequestFields: input.AdditionalModelRequestFields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
 	}
 
-	input := &bedrockruntime.InvokeModelInput{
-		ModelId:     aws.String(modelID),
-		Body:        requestBody,
-		ContentType: aws.String("application/json"),
-		Accept:      aws.String("application/json"),
+	events := output.GetStream()
+	defer func() { _ = events.Close() }()
+
+	var fullResponse *openai.ChatCompletion
+	toolCallsMap := make(map[int64]*openai.ChatCompletionMessageToolCall)
+	stream := &bedrockStreamState{
+		schemaTool:  bm.schemaToolName(),
+		id:          bedrockRequestID(output.ResultMetadata),
+		model:       bm.Model,
+		created:     time.Now().Unix(),
+		toolCalls:   make(map[int32]int64),
+		schemaBlock: -1,
 	}
 
-	result, err := bm.client.InvokeModel(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+	for event := range events.Events() {
+		chunk := stream.toChunk(event)
+		if chunk == nil {
+			continue
+		}
+		if err := streamFunc(chunk); err != nil {
+			return nil, err
+		}
+		accumulateStreamChunk(chunk, &fullResponse, toolCallsMap)
+	}
+	if err := events.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Bedrock stream: %w", err)
 	}
 
-	var response bedrockResponse
-	if err := json.Unmarshal(result.Body, &response); err != nil {
-		return nil, err
+	if fullResponse == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+
+	if len(toolCallsMap) > 0 && len(fullResponse.Choices) > 0 {
+		toolCalls := make([]openai.ChatCompletionMessageToolCall, 0, len(toolCallsMap))
+		for i := int64(0); i < int64(len(toolCallsMap)); i++ {
+			toolCalls = append(toolCalls, *toolCallsMap[i])
+		}
+		fullResponse.Choices[0].Message.ToolCalls = toolCalls
 	}
+	fullResponse.Usage = stream.usage
 
-	return bm.convertResponse(response), nil
+	return fullResponse, nil
 }
 
-func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	return bm.ChatCompletion(ctx, messages, 1, tools)
+// bedrockStreamState maps the events of a ConverseStream response to OpenAI chat completion chunks
+type bedrockStreamState struct {
+	schemaTool string
+	id         string
+	model      string
+	created    int64
+	usage      openai.CompletionUsage
+	// toolCalls maps the index of a tool use content block to the index of its tool call
+	toolCalls map[int32]int64
+	// schemaBlock is the index of the content block carrying structured output, or -1
+	schemaBlock  int32
+	schemaCalled bool
+	// heldText keeps text back while the structured output can still replace it
+	heldText string
 }
 
-func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	// Per OpenAI spec, when streaming is requested for a model that doesn't support it,
-	// return the complete response as a single chunk
-	completion, err := bm.ChatCompletion(ctx, messages, n, tools...)
-	if err != nil {
-		return nil, err
-	}
+// toChunk returns the chunk for an event, or nil for events that carry nothing to stream
+func (s *bedrockStreamState) toChunk(event types.ConverseStreamOutput) *openai.ChatCompletionChunk {
+	switch e := event.(type) {
+	case *types.ConverseStreamOutputMemberMessageStart:
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{Role: RoleAssistant}, "")
 
-	// Convert the completion to a single streaming chunk
-	// We send the full content in one chunk as per the OpenAI fallback spec
-	for _, choice := range completion.Choices {
-		chunk := &openai.ChatCompletionChunk{
-			ID:      completion.ID,
-			Object:  "chat.completion.chunk",
-			Created: completion.Created,
-			Model:   completion.Model,
-			Choices: []openai.ChatCompletionChunkChoice{
-				{
-					Index: choice.Index,
-					Delta: openai.ChatCompletionChunkChoiceDelta{
-						Content: choice.Message.Content,
-						Role:    "assistant",
-					},
-					FinishReason: choice.FinishReason,
-				},
-			},
+	case *types.ConverseStreamOutputMemberContentBlockStart:
+		start, ok := e.Value.Start.(*types.ContentBlockStartMemberToolUse)
+		if !ok {
+			return nil
 		}
+		block := aws.ToInt32(e.Value.ContentBlockIndex)
+		name := aws.ToString(start.Value.Name)
+		if s.schemaTool != "" && name == s.schemaTool {
+			// Structured output replaces the text, as in complete responses
+			s.schemaBlock = block
+			s.schemaCalled = true
+			s.heldText = ""
+			return nil
+		}
+		index := int64(len(s.toolCalls))
+		s.toolCalls[block] = index
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    index,
+				ID:       aws.ToString(start.Value.ToolUseId),
+				Type:     "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: name},
+			}},
+		}, "")
+
+	case *types.ConverseStreamOutputMemberContentBlockDelta:
+		block := aws.ToInt32(e.Value.ContentBlockIndex)
+		switch delta := e.Value.Delta.(type) {
+		case *types.ContentBlockDeltaMemberText:
+			if delta.Value == "" {
+				return nil
+			}
+			if s.schemaTool != "" {
+				if !s.schemaCalled {
+					s.heldText += delta.Value
+				}
+				return nil
+			}
+			return s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: delta.Value}, "")
+		case *types.ContentBlockDeltaMemberToolUse:
+			input := aws.ToString(delta.Value.Input)
+			if input == "" {
+				return nil
+			}
+			if block == s.schemaBlock {
+				return s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: input}, "")
+			}
+			index, ok := s.toolCalls[block]
+			if !ok {
+				return nil
+			}
+			return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+				ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+					Index:    index,
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: input},
+				}},
+			}, "")
+		}
+		// Reasoning and citation deltas have no equivalent in chat completion chunks
+		return nil
 
-		// Send the chunk via the stream callback
-		if err := streamFunc(chunk); err != nil {
-			return nil, err
+	case *types.ConverseStreamOutputMemberMessageStop:
+		chunk := s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: s.heldText}, bedrockFinishReason(e.Value.StopReason, s.schemaCalled && len(s.toolCalls) == 0))
+		s.heldText = ""
+		return chunk
+
+	case *types.ConverseStreamOutputMemberMetadata:
+		if e.Value.Usage == nil {
+			return nil
 		}
+		// Usage arrives after the message stopped, like the final usage chunk of OpenAI streams
+		s.usage = bedrockCompletionUsage(e.Value.Usage)
+		chunk := s.chunk(openai.ChatCompletionChunkChoiceDelta{}, "")
+		chunk.Choices = nil
+		chunk.Usage = s.usage
+		return chunk
 	}
 
-	// Return the original completion
-	return completion, nil
+	// Content block stops carry nothing to stream
+	return nil
 }
 
-func (bm *BedrockModel) buildRequest(messages []bedrockMessage, systemPrompt string, tools []bedrockTool) bedrockRequest {
-	temperature := getFloatProperty(bm.Properties, "temperature", 1.0)
-	maxTokens := getIntProperty(bm.Properties, "max_tokens", 4096)
-
-	return bedrockRequest{
-		Messages:     messages,
-		MaxTokens:    maxTokens,
-		Temperature:  temperature,
-		SystemPrompt: systemPrompt,
-		Tools:        tools,
+func (s *bedrockStreamState) chunk(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) *openai.ChatCompletionChunk {
+	return &openai.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+		Choices: []openai.ChatCompletionChunkChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
 	}
 }
 
-func (bm *BedrockModel) convertMessages(messages []Message) ([]bedrockMessage, string) {
-	var bedrockMessages []bedrockMessage
-	var systemPrompt string
+func (bm *BedrockModel) buildRequest(messages []Message, tools ...[]openai.ChatCompletionToolParam) (*bedrockruntime.ConverseInput, error) {
+	system, bedrockMessages, err := convertBedrockMessages(messages)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, msg := range messages {
-		content, role := extractMessageContent(msg)
-		if content == "" {
-			continue
+	modelID := bm.Model
+	if bm.ModelArn != "" {
+		modelID = bm.ModelArn
+	}
+
+	input := &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(modelID),
+		System:          system,
+		Messages:        bedrockMessages,
+		InferenceConfig: bm.inferenceConfig(),
+	}
+	if fields := bm.additionalFields(); fields != nil {
+		input.AdditionalModelRequestFields = document.NewLazyDocument(fields)
+	}
+
+	var bedrockTools []types.Tool
+	if len(tools) > 0 {
+		bedrockTools = convertBedrockTools(tools[0])
+	}
+
+	// The Converse API has no response format, structured output is requested as a tool call
+	var toolChoice types.ToolChoice
+	if schemaTool := bm.schemaToolName(); schemaTool != "" {
+		var schema map[string]any
+		if err := json.Unmarshal(bm.outputSchema.Raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse output schema: %w", err)
 		}
+		if len(bedrockTools) == 0 {
+			toolChoice = &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(schemaTool)}}
+		}
+		bedrockTools = append(bedrockTools, bedrockTool(schemaTool, "Respond with the final answer by calling this tool.", schema))
+	}
+
+	if len(bedrockTools) > 0 {
+		input.ToolConfig = &types.ToolConfiguration{Tools: bedrockTools, ToolChoice: toolChoice}
+	}
+
+	return input, nil
+}
 
-		switch role {
-		case RoleSystem:
-			systemPrompt = content
-		case RoleUser, RoleAssistant, RoleTool:
-			msgRole := role
-			if role == RoleTool {
-				msgRole = RoleUser
+func (bm *BedrockModel) inferenceConfig() *types.InferenceConfiguration {
+	inference := &types.InferenceConfiguration{
+		MaxTokens: aws.Int32(int32(getIntProperty(bm.Properties, "max_tokens", defaultBedrockMaxTokens))),
+	}
+	if _, ok := bm.Properties["temperature"]; ok {
+		inference.Temperature = aws.Float32(float32(getFloatProperty(bm.Properties, "temperature", 1.0)))
+	}
+	if _, ok := bm.Properties["top_p"]; ok {
+		inference.TopP = aws.Float32(float32(getFloatProperty(bm.Properties, "top_p", 1.0)))
+	}
+	for _, key := range []string{"stop", "stop_sequences"} {
+		value, ok := bm.Properties[key]
+		if !ok || value == "" {
+			continue
+		}
+		switch stop := parsePropertyValue(value).(type) {
+		case []any:
+			for _, sequence := range stop {
+				inference.StopSequences = append(inference.StopSequences, fmt.Sprint(sequence))
 			}
-			bedrockMessages = append(bedrockMessages, bedrockMessage{
-				Role:    msgRole,
-				Content: content,
-			})
+		default:
+			inference.StopSequences = append(inference.StopSequences, value)
 		}
 	}
 
-	return bedrockMessages, systemPrompt
+	// Generation parameters replace properties, the Converse API has no seed, penalties or reasoning effort
+	if g := bm.generation; g != nil {
+		if g.MaxTokens != nil {
+			inference.MaxTokens = aws.Int32(int32(*g.MaxTokens))
+		}
+		if g.Temperature != nil {
+			inference.Temperature = aws.Float32(float32(*g.Temperature))
+		}
+		if g.TopP != nil {
+			inference.TopP = aws.Float32(float32(*g.TopP))
+		}
+		if len(g.Stop) > 0 {
+			inference.StopSequences = g.Stop
+		}
+	}
+	return inference
 }
 
-func (bm *BedrockModel) convertResponse(response bedrockResponse) *openai.ChatCompletion {
-	var content string
-	var toolCalls []openai.ChatCompletionMessageToolCall
-
-	for _, c := range response.Content {
-		switch c.Type {
-		case "text":
-			content = c.Text
-		case "tool_use":
-			toolCall := openai.ChatCompletionMessageToolCall{
-				ID:   c.ID,
-				Type: "function",
-				Function: openai.ChatCompletionMessageToolCallFunction{
-					Name:      c.Name,
-					Arguments: mustMarshalJSON(c.Input),
-				},
-			}
-			toolCalls = append(toolCalls, toolCall)
+// additionalFields returns the model specific properties, such as top_k, that the Converse API
+// passes through to the model. Values that are valid JSON are sent as JSON, other values as strings.
+func (bm *BedrockModel) additionalFields() map[string]any {
+	var fields map[string]any
+	for key, value := range bm.Properties {
+		if value == "" || bedrockInferenceProperties[key] {
+			continue
 		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[key] = parsePropertyValue(value)
 	}
+	return fields
+}
 
-	finishReason := "stop"
-	switch response.StopReason {
-	case "max_tokens":
-		finishReason = "length"
-	case "tool_use":
-		finishReason = "tool_calls"
+// schemaToolName returns the name of the structured output tool, or "" without an output schema
+func (bm *BedrockModel) schemaToolName() string {
+	if bm.outputSchema == nil || bm.outputSchema.Raw == nil {
+		return ""
 	}
+	if bm.schemaName == "" {
+		return defaultBedrockSchemaTool
+	}
+	return bm.schemaName
+}
+
+// convertBedrockMessages splits the system prompt from the conversation. Tool results become
+// tool result blocks of user messages, and consecutive messages of the same role are merged as
+// the API requires roles to alternate.
+func convertBedrockMessages(messages []Message) ([]types.SystemContentBlock, []types.Message, error) {
+	var system []types.SystemContentBlock
+	var result []types.Message
 
-	message := openai.ChatCompletionMessage{
-		Role:    "assistant",
-		Content: content,
+	appendBlocks := func(role types.ConversationRole, blocks []types.ContentBlock) {
+		if len(blocks) == 0 {
+			return
+		}
+		if last := len(result) - 1; last >= 0 && result[last].Role == role {
+			result[last].Content = append(result[last].Content, blocks...)
+			return
+		}
+		result = append(result, types.Message{Role: role, Content: blocks})
 	}
 
-	if len(toolCalls) > 0 {
-		message.ToolCalls = toolCalls
+	for _, msg := range messages {
+		switch {
+		case msg.OfSystem != nil:
+			system = append(system, bedrockSystemBlocks(msg.OfSystem.Content.OfString.Value, msg.OfSystem.Content.OfArrayOfContentParts)...)
+		case msg.OfDeveloper != nil:
+			system = append(system, bedrockSystemBlocks(msg.OfDeveloper.Content.OfString.Value, msg.OfDeveloper.Content.OfArrayOfContentParts)...)
+		case msg.OfUser != nil:
+			blocks, err := convertBedrockUserContent(msg.OfUser.Content)
+			if err != nil {
+				return nil, nil, err
+			}
+			appendBlocks(types.ConversationRoleUser, blocks)
+		case msg.OfAssistant != nil:
+			appendBlocks(types.ConversationRoleAssistant, convertBedrockAssistantContent(msg.OfAssistant))
+		case msg.OfTool != nil:
+			content := msg.OfTool.Content.OfString.Value
+			for _, part := range msg.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			appendBlocks(types.ConversationRoleUser, []types.ContentBlock{&types.ContentBlockMemberToolResult{
+				Value: types.ToolResultBlock{
+					ToolUseId: aws.String(msg.OfTool.ToolCallID),
+					Content:   []types.ToolResultContentBlock{&types.ToolResultContentBlockMemberText{Value: content}},
+				},
+			}})
+		}
 	}
 
-	return &openai.ChatCompletion{
-		ID:     response.ID,
-		Object: "chat.completion",
-		Model:  response.Model,
-		Choices: []openai.ChatCompletionChoice{
-			{
-				Index:        0,
-				Message:      message,
-				FinishReason: finishReason,
-			},
-		},
-		Usage: openai.CompletionUsage{
-			PromptTokens:     int64(response.Usage.InputTokens),
-			CompletionTokens: int64(response.Usage.OutputTokens),
-			TotalTokens:      int64(response.Usage.InputTokens + response.Usage.OutputTokens),
-		},
+	return system, result, nil
+}
+
+func bedrockSystemBlocks(text string, parts []openai.ChatCompletionContentPartTextParam) []types.SystemContentBlock {
+	var blocks []types.SystemContentBlock
+	if text != "" {
+		blocks = append(blocks, &types.SystemContentBlockMemberText{Value: text})
 	}
+	for _, part := range parts {
+		if part.Text != "" {
+			blocks = append(blocks, &types.SystemContentBlockMemberText{Value: part.Text})
+		}
+	}
+	return blocks
 }
 
-func mustMarshalJSON(v interface{}) string {
-	if v == nil {
-		return "{}"
+func convertBedrockUserContent(content openai.ChatCompletionUserMessageParamContentUnion) ([]types.ContentBlock, error) {
+	var blocks []types.ContentBlock
+	if text := content.OfString.Value; text != "" {
+		blocks = append(blocks, &types.ContentBlockMemberText{Value: text})
+	}
+	for _, part := range content.OfArrayOfContentParts {
+		switch {
+		case part.OfText != nil && part.OfText.Text != "":
+			blocks = append(blocks, &types.ContentBlockMemberText{Value: part.OfText.Text})
+		case part.OfImageURL != nil:
+			image, err := bedrockImage(part.OfImageURL.ImageURL.URL)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, image)
+		}
 	}
-	data, err := json.Marshal(v)
+	return blocks, nil
+}
+
+// bedrockImage decodes a data URL into an image block. The Converse API only accepts inline
+// image bytes, so images referenced by URL cannot be sent.
+func bedrockImage(url string) (types.ContentBlock, error) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return nil, fmt.Errorf("bedrock only supports images as base64 data URLs")
+	}
+	mediaType, data, ok := strings.Cut(rest, ";base64,")
+	if !ok {
+		return nil, fmt.Errorf("bedrock only supports images as base64 data URLs")
+	}
+	imageBytes, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
-		return "{}"
+		return nil, fmt.Errorf("failed to decode image data: %w", err)
 	}
-	return string(data)
+	return &types.ContentBlockMemberImage{Value: types.ImageBlock{
+		Format: types.ImageFormat(strings.TrimPrefix(mediaType, "image/")),
+		Source: &types.ImageSourceMemberBytes{Value: imageBytes},
+	}}, nil
 }
 
-func (bm *BedrockModel) convertTools(tools []openai.ChatCompletionToolParam) []bedrockTool {
-	var bedrockTools []bedrockTool
+func convertBedrockAssistantContent(msg *openai.ChatCompletionAssistantMessageParam) []types.ContentBlock {
+	var blocks []types.ContentBlock
+	if text := msg.Content.OfString.Value; text != "" {
+		blocks = append(blocks, &types.ContentBlockMemberText{Value: text})
+	}
+	for _, part := range msg.Content.OfArrayOfContentParts {
+		if part.OfText != nil && part.OfText.Text != "" {
+			blocks = append(blocks, &types.ContentBlockMemberText{Value: part.OfText.Text})
+		}
+	}
+	for _, toolCall := range msg.ToolCalls {
+		input := map[string]any{}
+		_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &input)
+		blocks = append(blocks, &types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+			ToolUseId: aws.String(toolCall.ID),
+			Name:      aws.String(toolCall.Function.Name),
+			Input:     document.NewLazyDocument(input),
+		}})
+	}
+	return blocks
+}
 
+func convertBedrockTools(tools []openai.ChatCompletionToolParam) []types.Tool {
+	bedrockTools := make([]types.Tool, 0, len(tools))
 	for _, tool := range tools {
-		if tool.Type == "function" {
-			bedrockTool := bedrockTool{
-				Name: tool.Function.Name,
-			}
+		inputSchema := map[string]any(tool.Function.Parameters)
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		bedrockTools = append(bedrockTools, bedrockTool(tool.Function.Name, tool.Function.Description.Value, inputSchema))
+	}
+	return bedrockTools
+}
 
-			if tool.Function.Description.Value != "" {
-				bedrockTool.Description = tool.Function.Description.Value
-			}
+func bedrockTool(name, description string, inputSchema map[string]any) types.Tool {
+	spec := types.ToolSpecification{
+		Name:        aws.String(name),
+		InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(inputSchema)},
+	}
+	// The API rejects empty descriptions
+	if description != "" {
+		spec.Description = aws.String(description)
+	}
+	return &types.ToolMemberToolSpec{Value: spec}
+}
 
-			if tool.Function.Parameters != nil {
-				bedrockTool.InputSchema = map[string]interface{}(tool.Function.Parameters)
+func (bm *BedrockModel) convertResponse(output *bedrockruntime.ConverseOutput) *openai.ChatCompletion {
+	schemaTool := bm.schemaToolName()
+	message := openai.ChatCompletionMessage{Role: RoleAssistant}
+	schemaCalled := false
+
+	if result, ok := output.Output.(*types.ConverseOutputMemberMessage); ok {
+		for _, block := range result.Value.Content {
+			switch block := block.(type) {
+			case *types.ContentBlockMemberText:
+				if !schemaCalled {
+					message.Content += block.Value
+				}
+			case *types.ContentBlockMemberToolUse:
+				name := aws.ToString(block.Value.Name)
+				arguments := bedrockDocumentJSON(block.Value.Input)
+				if schemaTool != "" && name == schemaTool {
+					// Structured output replaces any text, so that the content stays valid JSON
+					message.Content = arguments
+					schemaCalled = true
+					continue
+				}
+				message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+					ID:   aws.ToString(block.Value.ToolUseId),
+					Type: "function",
+					Function: openai.ChatCompletionMessageToolCallFunction{
+						Name:      name,
+						Arguments: arguments,
+					},
+				})
 			}
-
-			bedrockTools = append(bedrockTools, bedrockTool)
 		}
 	}
 
-	return bedrockTools
+	return &openai.ChatCompletion{
+		ID:      bedrockRequestID(output.ResultMetadata),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   bm.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: bedrockFinishReason(output.StopReason, schemaCalled && len(message.ToolCalls) == 0),
+		}},
+		Usage: bedrockCompletionUsage(output.Usage),
+	}
 }
 
-func extractMessageContent(msg Message) (string, string) {
-	openaiMsg := openai.ChatCompletionMessageParamUnion(msg)
+// Embed calls the embedding model with InvokeModel, as the Converse API has no embeddings. Cohere
+// models embed a batch in one request, Titan models one text per request.
+func (bm *BedrockModel) Embed(ctx context.Context, inputs []string, dimensions *int64) (*EmbeddingResponse, error) {
+	if err := bm.initClient(ctx); err != nil {
+		return nil, err
+	}
 
-	if systemMsg := openaiMsg.OfSystem; systemMsg != nil {
-		if content := systemMsg.Content.OfString; content.Value != "" {
-			return content.Value, "system"
+	if strings.Contains(bm.Model, "cohere.embed") {
+		body := map[string]any{"texts": inputs, "input_type": "search_document"}
+		if dimensions != nil {
+			body["output_dimension"] = *dimensions
+		}
+		var response struct {
+			Embeddings [][]float64 `json:"embeddings"`
+		}
+		tokens, err := bm.invokeModel(ctx, body, &response)
+		if err != nil {
+			return nil, err
 		}
+		return &EmbeddingResponse{Embeddings: response.Embeddings, PromptTokens: tokens}, nil
 	}
 
-	if userMsg := openaiMsg.OfUser; userMsg != nil {
-		if content := userMsg.Content.OfString; content.Value != "" {
-			return content.Value, RoleUser
+	result := &EmbeddingResponse{}
+	for _, input := range inputs {
+		body := map[string]any{"inputText": input}
+		if dimensions != nil {
+			body["dimensions"] = *dimensions
 		}
+		var response struct {
+			Embedding           []float64 `json:"embedding"`
+			InputTextTokenCount int64     `json:"inputTextTokenCount"`
+		}
+		if _, err := bm.invokeModel(ctx, body, &response); err != nil {
+			return nil, err
+		}
+		result.Embeddings = append(result.Embeddings, response.Embedding)
+		result.PromptTokens += response.InputTextTokenCount
 	}
+	return result, nil
+}
 
-	if assistantMsg := openaiMsg.OfAssistant; assistantMsg != nil {
-		if content := assistantMsg.Content.OfString; content.Value != "" {
-			return content.Value, "assistant"
-		}
+// invokeModel sends the body to the model and decodes the response into result. It returns the
+// input tokens Bedrock reports in the response headers.
+func (bm *BedrockModel) invokeModel(ctx context.Context, body map[string]any, result any) (int64, error) {
+	modelID := bm.Model
+	if bm.ModelArn != "" {
+		modelID = bm.ModelArn
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal Bedrock request: %w", err)
 	}
 
-	if toolMsg := openaiMsg.OfTool; toolMsg != nil {
-		if content := toolMsg.Content.OfString; content.Value != "" {
-			return content.Value, "tool"
+	output, err := bm.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		Body:        data,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+	}
+	if err := json.Unmarshal(output.Body, result); err != nil {
+		return 0, fmt.Errorf("failed to decode Bedrock response: %w", err)
+	}
+
+	var tokens int64
+	if raw, ok := awsmiddleware.GetRawResponse(output.ResultMetadata).(*smithyhttp.Response); ok {
+		tokens, _ = strconv.ParseInt(raw.Header.Get("X-Amzn-Bedrock-Input-Token-Count"), 10, 64)
+	}
+	return tokens, nil
+}
+
+// bedrockRequestID returns the AWS request ID, the Converse API has no completion ID
+func bedrockRequestID(metadata middleware.Metadata) string {
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(metadata)
+	return requestID
+}
+
+func bedrockDocumentJSON(doc document.Interface) string {
+	if doc == nil {
+		return "{}"
+	}
+	data, err := doc.MarshalSmithyDocument()
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// bedrockFinishReason maps a stop reason to an OpenAI finish reason. A turn that only returned
+// structured output is complete rather than waiting for tool results.
+func bedrockFinishReason(stopReason types.StopReason, onlyStructuredOutput bool) string {
+	switch stopReason {
+	case types.StopReasonMaxTokens:
+		return "length"
+	case types.StopReasonToolUse:
+		if onlyStructuredOutput {
+			return "stop"
 		}
+		return "tool_calls"
+	case types.StopReasonGuardrailIntervened, types.StopReasonContentFiltered:
+		return "content_filter"
+	case "":
+		return ""
+	default:
+		return "stop"
 	}
+}
 
-	return "", ""
+// bedrockCompletionUsage counts cached input tokens as prompt tokens, as OpenAI does
+func bedrockCompletionUsage(usage *types.TokenUsage) openai.CompletionUsage {
+	if usage == nil {
+		return openai.CompletionUsage{}
+	}
+	cacheRead := int64(aws.ToInt32(usage.CacheReadInputTokens))
+	promptTokens := int64(aws.ToInt32(usage.InputTokens)) + cacheRead + int64(aws.ToInt32(usage.CacheWriteInputTokens))
+	completionTokens := int64(aws.ToInt32(usage.OutputTokens))
+	return openai.CompletionUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{
+			CachedTokens: cacheRead,
+		},
+	}
+}
+
+func mustMarshalJSON(v interface{}) string {
+	if v == nil {
+		return "{}"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
 }
 
 func (bm *BedrockModel) BuildConfig() map[string]any {
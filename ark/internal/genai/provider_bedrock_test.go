@@ -0,0 +1,245 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// bedrockResponseHeader carries the request ID Bedrock returns with every response
+var bedrockResponseHeader = http.Header{"X-Amzn-Requestid": {"req-1"}}
+
+func newBedrockTestModel(baseURL string, properties map[string]string) *BedrockModel {
+	return NewBedrockModel("anthropic.claude-sonnet-4-5", "us-east-1", baseURL, "AKIDTEST", "secret", "", "", properties)
+}
+
+func TestBedrockChatCompletion(t *testing.T) {
+	stub := &providerStub{header: bedrockResponseHeader, body: `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"text": "Let me check."},
+			{"toolUse": {"toolUseId": "tooluse_1", "name": "get_weather", "input": {"city": "Paris"}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 12, "outputTokens": 8, "totalTokens": 20, "cacheReadInputTokens": 4},
+		"metrics": {"latencyMs": 100}
+	}`}
+	server := stub.start(t)
+
+	model := newBedrockTestModel(server.URL, map[string]string{"max_tokens": "512", "temperature": "0.2", "stop": `["END"]`, "top_k": "40"})
+	messages := []Message{
+		NewSystemMessage("Be brief."),
+		NewUserMessage("Weather in Paris?"),
+	}
+
+	completion, err := model.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/model/anthropic.claude-sonnet-4-5/converse"}, stub.paths)
+	request := stub.requests[0]
+	require.Equal(t, []any{map[string]any{"text": "Be brief."}}, request["system"])
+	require.Equal(t, []any{map[string]any{"role": "user", "content": []any{map[string]any{"text": "Weather in Paris?"}}}}, request["messages"])
+	inference := request["inferenceConfig"].(map[string]any)
+	require.Equal(t, float64(512), inference["maxTokens"])
+	require.InDelta(t, 0.2, inference["temperature"], 0.0001)
+	require.Equal(t, []any{"END"}, inference["stopSequences"])
+	require.Equal(t, map[string]any{"top_k": float64(40)}, request["additionalModelRequestFields"])
+	toolSpec := request["toolConfig"].(map[string]any)["tools"].([]any)[0].(map[string]any)["toolSpec"].(map[string]any)
+	require.Equal(t, "get_weather", toolSpec["name"])
+	require.NotNil(t, toolSpec["inputSchema"].(map[string]any)["json"])
+
+	require.Equal(t, "req-1", completion.ID)
+	require.Equal(t, "Let me check.", completion.Choices[0].Message.Content)
+	require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, "tooluse_1", completion.Choices[0].Message.ToolCalls[0].ID)
+	require.JSONEq(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, int64(16), completion.Usage.PromptTokens)
+	require.Equal(t, int64(8), completion.Usage.CompletionTokens)
+	require.Equal(t, int64(24), completion.Usage.TotalTokens)
+	require.Equal(t, int64(4), completion.Usage.PromptTokensDetails.CachedTokens)
+}
+
+func TestBedrockChatCompletionStream(t *testing.T) {
+	stub := &providerStub{header: bedrockResponseHeader, encoding: awsEventStream, events: []string{
+		`{"messageStart": {"role": "assistant"}}`,
+		`{"contentBlockDelta": {"contentBlockIndex": 0, "delta": {"text": "Let me "}}}`,
+		`{"contentBlockDelta": {"contentBlockIndex": 0, "delta": {"text": "check."}}}`,
+		`{"contentBlockStop": {"contentBlockIndex": 0}}`,
+		`{"contentBlockStart": {"contentBlockIndex": 1, "start": {"toolUse": {"toolUseId": "tooluse_1", "name": "get_weather"}}}}`,
+		`{"contentBlockDelta": {"contentBlockIndex": 1, "delta": {"toolUse": {"input": "{\"city\":"}}}}`,
+		`{"contentBlockDelta": {"contentBlockIndex": 1, "delta": {"toolUse": {"input": "\"Paris\"}"}}}}`,
+		`{"contentBlockStop": {"contentBlockIndex": 1}}`,
+		`{"messageStop": {"stopReason": "tool_use"}}`,
+		`{"metadata": {"usage": {"inputTokens": 12, "outputTokens": 8, "totalTokens": 20}, "metrics": {"latencyMs": 100}}}`,
+	}}
+	server := stub.start(t)
+
+	model := newBedrockTestModel(server.URL, nil)
+	var chunks []*openai.ChatCompletionChunk
+	completion, err := model.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Weather in Paris?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/model/anthropic.claude-sonnet-4-5/converse-stream"}, stub.paths)
+	require.Len(t, chunks, 8)
+	require.Equal(t, "Let me ", chunks[1].Choices[0].Delta.Content)
+	require.Equal(t, "get_weather", chunks[3].Choices[0].Delta.ToolCalls[0].Function.Name)
+	require.Empty(t, chunks[7].Choices)
+	require.Equal(t, int64(20), chunks[7].Usage.TotalTokens)
+
+	require.Equal(t, "req-1", completion.ID)
+	require.Equal(t, "Let me check.", completion.Choices[0].Message.Content)
+	require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, "tooluse_1", completion.Choices[0].Message.ToolCalls[0].ID)
+	require.Equal(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, int64(20), completion.Usage.TotalTokens)
+}
+
+func TestBedrockStructuredOutput(t *testing.T) {
+	schema := &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"answer":{"type":"string"}}}`)}
+
+	t.Run("completion", func(t *testing.T) {
+		stub := &providerStub{header: bedrockResponseHeader, body: `{
+			"output": {"message": {"role": "assistant", "content": [
+				{"toolUse": {"toolUseId": "tooluse_1", "name": "answer", "input": {"answer": "42"}}}
+			]}},
+			"stopReason": "tool_use",
+			"usage": {"inputTokens": 5, "outputTokens": 3, "totalTokens": 8}
+		}`}
+		server := stub.start(t)
+
+		model := newBedrockTestModel(server.URL, nil)
+		model.SetOutputSchema(schema, "answer")
+		completion, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("Answer?")}, 1)
+		require.NoError(t, err)
+
+		toolConfig := stub.requests[0]["toolConfig"].(map[string]any)
+		require.Equal(t, map[string]any{"tool": map[string]any{"name": "answer"}}, toolConfig["toolChoice"])
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+		require.Empty(t, completion.Choices[0].Message.ToolCalls)
+		require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		state := &bedrockStreamState{schemaTool: "answer", toolCalls: make(map[int32]int64), schemaBlock: -1}
+		require.Nil(t, state.toChunk(&types.ConverseStreamOutputMemberContentBlockStart{Value: types.ContentBlockStartEvent{
+			ContentBlockIndex: aws.Int32(0),
+			Start:             &types.ContentBlockStartMemberToolUse{Value: types.ToolUseBlockStart{ToolUseId: aws.String("tooluse_1"), Name: aws.String("answer")}},
+		}}))
+		chunk := state.toChunk(&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+			ContentBlockIndex: aws.Int32(0),
+			Delta:             &types.ContentBlockDeltaMemberToolUse{Value: types.ToolUseBlockDelta{Input: aws.String(`{"answer":"42"}`)}},
+		}})
+		require.Equal(t, `{"answer":"42"}`, chunk.Choices[0].Delta.Content)
+		require.Empty(t, chunk.Choices[0].Delta.ToolCalls)
+		chunk = state.toChunk(&types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonToolUse}})
+		require.Equal(t, "stop", chunk.Choices[0].FinishReason)
+	})
+
+	t.Run("leaves out text before the structured output", func(t *testing.T) {
+		state := &bedrockStreamState{schemaTool: "answer", toolCalls: make(map[int32]int64), schemaBlock: -1}
+		require.Nil(t, state.toChunk(&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+			ContentBlockIndex: aws.Int32(0),
+			Delta:             &types.ContentBlockDeltaMemberText{Value: "Here is the answer."},
+		}}))
+		require.Nil(t, state.toChunk(&types.ConverseStreamOutputMemberContentBlockStart{Value: types.ContentBlockStartEvent{
+			ContentBlockIndex: aws.Int32(1),
+			Start:             &types.ContentBlockStartMemberToolUse{Value: types.ToolUseBlockStart{ToolUseId: aws.String("tooluse_1"), Name: aws.String("answer")}},
+		}}))
+		chunk := state.toChunk(&types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonToolUse}})
+		require.Empty(t, chunk.Choices[0].Delta.Content)
+
+		stub := &providerStub{header: bedrockResponseHeader, body: `{
+			"output": {"message": {"role": "assistant", "content": [
+				{"text": "Here is the answer."},
+				{"toolUse": {"toolUseId": "tooluse_1", "name": "answer", "input": {"answer": "42"}}}
+			]}},
+			"stopReason": "tool_use",
+			"usage": {"inputTokens": 5, "outputTokens": 9, "totalTokens": 14}
+		}`}
+		server := stub.start(t)
+		model := newBedrockTestModel(server.URL, nil)
+		model.SetOutputSchema(schema, "answer")
+		completion, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("Answer?")}, 1, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+	})
+
+	t.Run("streams held back text when no structured output follows", func(t *testing.T) {
+		state := &bedrockStreamState{schemaTool: "answer", toolCalls: make(map[int32]int64), schemaBlock: -1}
+		require.Nil(t, state.toChunk(&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+			ContentBlockIndex: aws.Int32(0),
+			Delta:             &types.ContentBlockDeltaMemberText{Value: "Let me check."},
+		}}))
+		chunk := state.toChunk(&types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonEndTurn}})
+		require.Equal(t, "Let me check.", chunk.Choices[0].Delta.Content)
+	})
+}
+
+func TestBedrockErrors(t *testing.T) {
+	stub := &providerStub{
+		header: http.Header{"X-Amzn-Requestid": {"req-1"}, "X-Amzn-Errortype": {"ValidationException"}},
+		status: http.StatusBadRequest,
+		body:   `{"message": "The provided model identifier is invalid."}`,
+	}
+	server := stub.start(t)
+
+	model := newBedrockTestModel(server.URL, nil)
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("Hi")}, 1)
+	require.Error(t, err)
+	require.Equal(t, "The provided model identifier is invalid. (400)", extractStableError(err, 0))
+}
+
+func TestConvertBedrockMessages(t *testing.T) {
+	assistant := openai.ChatCompletionAssistantMessageParam{
+		ToolCalls: []openai.ChatCompletionMessageToolCallParam{{
+			ID:       "tooluse_1",
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		}},
+	}
+	messages := []Message{
+		NewSystemMessage("Be brief."),
+		NewUserMessage("Weather in Paris and Rome?"),
+		Message(openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant}),
+		Message(openai.ToolMessage("Sunny", "tooluse_1")),
+		Message(openai.ToolMessage("Rainy", "tooluse_2")),
+		NewUserMessage("Thanks"),
+	}
+
+	system, converted, err := convertBedrockMessages(messages)
+	require.NoError(t, err)
+	require.Len(t, system, 1)
+	require.Len(t, converted, 3)
+	require.Equal(t, types.ConversationRoleAssistant, converted[1].Role)
+	toolUse := converted[1].Content[0].(*types.ContentBlockMemberToolUse)
+	require.Equal(t, "get_weather", aws.ToString(toolUse.Value.Name))
+	require.JSONEq(t, `{"city":"Paris"}`, bedrockDocumentJSON(toolUse.Value.Input))
+
+	// Tool results and the following user message form a single user turn
+	require.Equal(t, types.ConversationRoleUser, converted[2].Role)
+	require.Len(t, converted[2].Content, 3)
+	result := converted[2].Content[1].(*types.ContentBlockMemberToolResult)
+	require.Equal(t, "tooluse_2", aws.ToString(result.Value.ToolUseId))
+
+	_, _, err = convertBedrockMessages([]Message{Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "https://example.com/cat.png"}),
+	}))})
+	require.Error(t, err)
+
+	_, converted, err = convertBedrockMessages([]Message{Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,aGVsbG8="}),
+	}))})
+	require.NoError(t, err)
+	image := converted[0].Content[0].(*types.ContentBlockMemberImage)
+	require.Equal(t, types.ImageFormatPng, image.Value.Format)
+	require.Equal(t, []byte("hello"), image.Value.Source.(*types.ImageSourceMemberBytes).Value)
+}
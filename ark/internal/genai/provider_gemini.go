@@ -0,0 +1,1020 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+	"k8s.io/apimachinery/pkg/runtime"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultGeminiBaseURL   = "https://generativelanguage.googleapis.com"
+	defaultVertexLocation  = "global"
+	defaultGoogleTokenURL  = "https://oauth2.googleapis.com/token"
+	vertexCloudPlatformURL = "https://www.googleapis.com/auth/cloud-platform"
+	// defaultGeminiSchemaFunction names the function structured output is requested with when the
+	// output schema has no name
+	defaultGeminiSchemaFunction = "structured_output"
+)
+
+// geminiPropertyAliases maps the OpenAI parameter names used by other model types to their
+// generationConfig fields, so the same properties work across providers
+var geminiPropertyAliases = map[string]string{
+	"max_tokens":            "maxOutputTokens",
+	"max_completion_tokens": "maxOutputTokens",
+	"top_p":                 "topP",
+	"top_k":                 "topK",
+	"stop":                  "stopSequences",
+	"presence_penalty":      "presencePenalty",
+	"frequency_penalty":     "frequencyPenalty",
+	"n":                     "candidateCount",
+}
+
+// geminiGenerationFields are the generationConfig fields of the generation parameters
+var geminiGenerationFields = generationFieldNames{
+	temperature:      "temperature",
+	topP:             "topP",
+	maxTokens:        "maxOutputTokens",
+	stop:             "stopSequences",
+	seed:             "seed",
+	presencePenalty:  "presencePenalty",
+	frequencyPenalty: "frequencyPenalty",
+}
+
+// geminiThinkingBudgets maps reasoning efforts to thinking token budgets, following the mapping of
+// the Gemini OpenAI compatible API. Minimal uses the smallest budget all thinking models accept.
+var geminiThinkingBudgets = map[string]int{
+	"minimal": 128,
+	"low":     1024,
+	"medium":  8192,
+	"high":    24576,
+}
+
+// geminiSchemaFields are the JSON schema keywords the Gemini Schema object accepts, others such as
+// additionalProperties or $schema are rejected by the API
+var geminiSchemaFields = map[string]bool{
+	"type": true, "format": true, "title": true, "description": true, "nullable": true, "enum": true,
+	"items": true, "minItems": true, "maxItems": true, "properties": true, "required": true,
+	"minProperties": true, "maxProperties": true, "propertyOrdering": true, "minLength": true,
+	"maxLength": true, "pattern": true, "example": true, "anyOf": true, "default": true,
+	"minimum": true, "maximum": true,
+}
+
+// GeminiProvider calls Google Gemini through the Gemini API with an API key, or through Vertex AI
+// with a service account
+type GeminiProvider struct {
+	Model          string
+	BaseURL        string
+	APIKey         string
+	ServiceAccount string
+	Project        string
+	Location       string
+	Headers        map[string]string
+	Properties     map[string]string
+	outputSchema   *runtime.RawExtension
+	schemaName     string
+	mu             sync.Mutex
+	tokenSource    oauth2.TokenSource
+	generation     *GenerationParameters
+}
+
+// GeminiError is an error response of the Gemini or Vertex AI API
+type GeminiError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *GeminiError) Error() string {
+	return fmt.Sprintf("gemini %s (%d): %s", e.Status, e.StatusCode, e.Message)
+}
+
+type geminiRequest struct {
+	Contents          []geminiContent `json:"contents"`
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool    `json:"tools,omitempty"`
+	GenerationConfig  map[string]any  `json:"generationConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	InlineData       *geminiBlob             `json:"inlineData,omitempty"`
+	FileData         *geminiFileData         `json:"fileData,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiBlob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiFileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+type geminiFunctionCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates     []geminiCandidate `json:"candidates"`
+	UsageMetadata  geminiUsage       `json:"usageMetadata"`
+	ModelVersion   string            `json:"modelVersion"`
+	ResponseID     string            `json:"responseId"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	Error *geminiErrorBody `json:"error,omitempty"`
+}
+
+type geminiCandidate struct {
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+	Index        int64         `json:"index"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount        int64 `json:"promptTokenCount"`
+	CandidatesTokenCount    int64 `json:"candidatesTokenCount"`
+	ThoughtsTokenCount      int64 `json:"thoughtsTokenCount"`
+	CachedContentTokenCount int64 `json:"cachedContentTokenCount"`
+}
+
+type geminiErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+func (gp *GeminiProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	gp.outputSchema = schema
+	gp.schemaName = schemaName
+}
+
+func (gp *GeminiProvider) SetGenerationParameters(params *GenerationParameters) {
+	gp.generation = params
+}
+
+func (gp *GeminiProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	body, err := gp.buildRequest(messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := gp.send(ctx, body, false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Gemini response: %w", err)
+	}
+	if len(response.Candidates) == 0 && response.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("gemini blocked the prompt: %s", response.PromptFeedback.BlockReason)
+	}
+
+	return gp.convertResponse(response, gp.schemaFunctionName(tools...)), nil
+}
+
+func (gp *GeminiProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	body, err := gp.buildRequest(messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := gp.send(ctx, body, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var fullResponse *openai.ChatCompletion
+	toolCallsMap := make(map[int64]*openai.ChatCompletionMessageToolCall)
+	stream := &geminiStreamState{
+		schemaFunction: gp.schemaFunctionName(tools...),
+		created:        time.Now().Unix(),
+		idPrefix:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		heldText:       make(map[int64]string),
+		schemaCalled:   make(map[int64]bool),
+	}
+
+	err = readServerSentEvents(resp.Body, func(data []byte) error {
+		var event geminiResponse
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to decode Gemini stream event: %w", err)
+		}
+		if event.Error != nil {
+			return &GeminiError{StatusCode: event.Error.Code, Status: event.Error.Status, Message: event.Error.Message}
+		}
+		if len(event.Candidates) == 0 && event.PromptFeedback.BlockReason != "" {
+			return fmt.Errorf("gemini blocked the prompt: %s", event.PromptFeedback.BlockReason)
+		}
+
+		chunk := stream.toChunk(event)
+		if err := streamFunc(chunk); err != nil {
+			return err
+		}
+		accumulateStreamChunk(chunk, &fullResponse, toolCallsMap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fullResponse == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+
+	if len(toolCallsMap) > 0 && len(fullResponse.Choices) > 0 {
+		toolCalls := make([]openai.ChatCompletionMessageToolCall, 0, len(toolCallsMap))
+		for i := int64(0); i < int64(len(toolCallsMap)); i++ {
+			toolCalls = append(toolCalls, *toolCallsMap[i])
+		}
+		fullResponse.Choices[0].Message.ToolCalls = toolCalls
+	}
+	fullResponse.Usage = stream.usage.toCompletionUsage()
+
+	return fullResponse, nil
+}
+
+// geminiStreamState maps the partial responses of a stream to OpenAI chat completion chunks. Text
+// arrives in pieces while every function call arrives whole.
+type geminiStreamState struct {
+	schemaFunction string
+	id             string
+	idPrefix       string
+	model          string
+	created        int64
+	usage          geminiUsage
+	started        bool
+	toolCalls      int64
+	// heldText keeps the text of each candidate back while the schema function can still replace it
+	heldText     map[int64]string
+	schemaCalled map[int64]bool
+}
+
+func (s *geminiStreamState) toChunk(event geminiResponse) *openai.ChatCompletionChunk {
+	if s.id == "" {
+		s.id = event.ResponseID
+	}
+	if event.ModelVersion != "" {
+		s.model = event.ModelVersion
+	}
+	if event.UsageMetadata.PromptTokenCount > 0 || event.UsageMetadata.CandidatesTokenCount > 0 {
+		s.usage = event.UsageMetadata
+	}
+
+	chunk := &openai.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+	}
+
+	for _, candidate := range event.Candidates {
+		choice := openai.ChatCompletionChunkChoice{Index: candidate.Index}
+		if !s.started {
+			choice.Delta.Role = RoleAssistant
+		}
+		for _, part := range candidate.Content.Parts {
+			switch {
+			case part.Thought:
+				// Thought summaries are not part of the answer
+			case part.FunctionCall != nil && s.schemaFunction != "" && part.FunctionCall.Name == s.schemaFunction:
+				// Structured output replaces the text of the candidate, as in complete responses
+				s.schemaCalled[candidate.Index] = true
+				delete(s.heldText, candidate.Index)
+				choice.Delta.Content += mustMarshalJSON(part.FunctionCall.Args)
+			case part.FunctionCall != nil:
+				// Only the first candidate is accumulated into the response, so only its calls are numbered
+				if candidate.Index != 0 {
+					continue
+				}
+				choice.Delta.ToolCalls = append(choice.Delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+					Index: s.toolCalls,
+					ID:    geminiToolCallID(part.FunctionCall, s.idPrefix, s.toolCalls),
+					Type:  "function",
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{
+						Name:      part.FunctionCall.Name,
+						Arguments: mustMarshalJSON(part.FunctionCall.Args),
+					},
+				})
+				s.toolCalls++
+			case s.schemaFunction != "":
+				if !s.schemaCalled[candidate.Index] {
+					s.heldText[candidate.Index] += part.Text
+				}
+			default:
+				choice.Delta.Content += part.Text
+			}
+		}
+		if candidate.FinishReason != "" {
+			choice.Delta.Content += s.heldText[candidate.Index]
+			delete(s.heldText, candidate.Index)
+			choice.FinishReason = geminiFinishReason(candidate.FinishReason, candidate.Index == 0 && s.toolCalls > 0)
+			chunk.Usage = s.usage.toCompletionUsage()
+		}
+		chunk.Choices = append(chunk.Choices, choice)
+	}
+	s.started = true
+
+	return chunk
+}
+
+func (gp *GeminiProvider) buildRequest(messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) ([]byte, error) {
+	system, contents := convertGeminiMessages(messages)
+
+	request := geminiRequest{
+		Contents:          contents,
+		SystemInstruction: system,
+		GenerationConfig:  gp.generationConfig(n),
+	}
+	if len(tools) > 0 && len(tools[0]) > 0 {
+		request.Tools = []geminiTool{{FunctionDeclarations: convertGeminiTools(tools[0])}}
+	}
+
+	if gp.outputSchema != nil && gp.outputSchema.Raw != nil {
+		var schema any
+		if err := json.Unmarshal(gp.outputSchema.Raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse output schema: %w", err)
+		}
+		// Function calling does not work with a JSON response, with tools structured output is
+		// requested as a function call
+		if schemaFunction := gp.schemaFunctionName(tools...); schemaFunction != "" {
+			request.Tools[0].FunctionDeclarations = append(request.Tools[0].FunctionDeclarations, geminiFunctionDeclaration{
+				Name:        schemaFunction,
+				Description: "Respond with the final answer by calling this function.",
+				Parameters:  geminiSchema(schema),
+			})
+		} else {
+			request.GenerationConfig["responseMimeType"] = "application/json"
+			request.GenerationConfig["responseSchema"] = geminiSchema(schema)
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Gemini request: %w", err)
+	}
+	return body, nil
+}
+
+// schemaFunctionName returns the name of the structured output function, or "" without an output
+// schema or without tools, where the response schema is used instead
+func (gp *GeminiProvider) schemaFunctionName(tools ...[]openai.ChatCompletionToolParam) string {
+	if gp.outputSchema == nil || gp.outputSchema.Raw == nil || len(tools) == 0 || len(tools[0]) == 0 {
+		return ""
+	}
+	if gp.schemaName == "" {
+		return defaultGeminiSchemaFunction
+	}
+	return gp.schemaName
+}
+
+// generationConfig sets the model properties as generation parameters, translating OpenAI names.
+// Generation parameters replace properties.
+func (gp *GeminiProvider) generationConfig(n int64) map[string]any {
+	config := make(map[string]any)
+	for key, value := range gp.Properties {
+		if value == "" {
+			continue
+		}
+		if alias, ok := geminiPropertyAliases[key]; ok {
+			key = alias
+		}
+		config[key] = parsePropertyValue(value)
+	}
+	if stop, ok := config["stopSequences"].(string); ok {
+		config["stopSequences"] = []string{stop}
+	}
+	for key, value := range gp.generation.fields(geminiGenerationFields) {
+		config[key] = value
+	}
+	if gp.generation != nil && gp.generation.ReasoningEffort != "" {
+		config["thinkingConfig"] = map[string]any{"thinkingBudget": geminiThinkingBudgets[gp.generation.ReasoningEffort]}
+	}
+	if n > 1 {
+		config["candidateCount"] = n
+	}
+	return config
+}
+
+func (gp *GeminiProvider) send(ctx context.Context, body []byte, stream bool) (*http.Response, error) {
+	endpoint, err := gp.endpoint(stream)
+	if err != nil {
+		return nil, err
+	}
+	return gp.do(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+}
+
+type geminiEmbedRequest struct {
+	Model                string        `json:"model"`
+	Content              geminiContent `json:"content"`
+	OutputDimensionality *int64        `json:"outputDimensionality,omitempty"`
+}
+
+type geminiEmbedResponse struct {
+	Embeddings []struct {
+		Values []float64 `json:"values"`
+	} `json:"embeddings"`
+}
+
+type vertexEmbedResponse struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values     []float64 `json:"values"`
+			Statistics struct {
+				TokenCount float64 `json:"token_count"`
+			} `json:"statistics"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+}
+
+// Embed calls batchEmbedContents of the Gemini API, or predict of Vertex AI for service accounts.
+// Only Vertex AI reports the tokens of the inputs.
+func (gp *GeminiProvider) Embed(ctx context.Context, inputs []string, dimensions *int64) (*EmbeddingResponse, error) {
+	modelURL, err := gp.modelURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]any
+	if gp.ServiceAccount == "" {
+		requests := make([]geminiEmbedRequest, len(inputs))
+		for i, input := range inputs {
+			requests[i] = geminiEmbedRequest{
+				Model:                "models/" + strings.TrimPrefix(gp.Model, "models/"),
+				Content:              geminiContent{Parts: []geminiPart{{Text: input}}},
+				OutputDimensionality: dimensions,
+			}
+		}
+		body = map[string]any{"requests": requests}
+		modelURL += ":batchEmbedContents"
+	} else {
+		instances := make([]map[string]any, len(inputs))
+		for i, input := range inputs {
+			instances[i] = map[string]any{"content": input}
+		}
+		body = map[string]any{"instances": instances}
+		if dimensions != nil {
+			body["parameters"] = map[string]any{"outputDimensionality": *dimensions}
+		}
+		modelURL += ":predict"
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Gemini embedding request: %w", err)
+	}
+	resp, err := gp.do(ctx, http.MethodPost, modelURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	result := &EmbeddingResponse{}
+	if gp.ServiceAccount == "" {
+		var response geminiEmbedResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, fmt.Errorf("failed to decode Gemini embedding response: %w", err)
+		}
+		for _, embedding := range response.Embeddings {
+			result.Embeddings = append(result.Embeddings, embedding.Values)
+		}
+		return result, nil
+	}
+
+	var response vertexEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Vertex AI embedding response: %w", err)
+	}
+	for _, prediction := range response.Predictions {
+		result.Embeddings = append(result.Embeddings, prediction.Embeddings.Values)
+		result.PromptTokens += int64(prediction.Embeddings.Statistics.TokenCount)
+	}
+	return result, nil
+}
+
+// ProbeModelList gets the model from the models endpoint of the Gemini API, or the publisher
+// model from Vertex AI, which does not generate tokens
+func (gp *GeminiProvider) ProbeModelList(ctx context.Context) error {
+	var modelURL string
+	if gp.ServiceAccount == "" {
+		// Only Vertex AI URLs need a project, which can fail to resolve
+		modelURL, _ = gp.modelURL()
+	} else {
+		// Publisher models are read outside of a project
+		baseURL, _ := gp.vertexEndpoint()
+		modelURL = fmt.Sprintf("%s/v1beta1/publishers/google/models/%s", baseURL, strings.TrimPrefix(gp.Model, "models/"))
+	}
+	resp, err := gp.do(ctx, http.MethodGet, modelURL, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (gp *GeminiProvider) do(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := gp.authorize(req); err != nil {
+		return nil, err
+	}
+	for name, value := range gp.Headers {
+		req.Header.Set(name, value)
+	}
+
+	var httpClient *http.Client
+	if IsProbeContext(ctx) {
+		httpClient = common.NewHTTPClientWithoutTracing()
+	} else {
+		httpClient = common.NewHTTPClientWithLogging(ctx)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Gemini API: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, parseGeminiError(resp)
+	}
+	return resp, nil
+}
+
+// endpoint returns the generateContent URL of the Gemini API, or of Vertex AI for service accounts
+func (gp *GeminiProvider) endpoint(stream bool) (string, error) {
+	method := "generateContent"
+	if stream {
+		method = "streamGenerateContent?alt=sse"
+	}
+	modelURL, err := gp.modelURL()
+	if err != nil {
+		return "", err
+	}
+	return modelURL + ":" + method, nil
+}
+
+// modelURL returns the URL of the model in the Gemini API, or in Vertex AI for service accounts
+func (gp *GeminiProvider) modelURL() (string, error) {
+	model := strings.TrimPrefix(gp.Model, "models/")
+
+	if gp.ServiceAccount == "" {
+		baseURL := gp.BaseURL
+		if baseURL == "" {
+			baseURL = defaultGeminiBaseURL
+		}
+		return fmt.Sprintf("%s/v1beta/models/%s", strings.TrimSuffix(baseURL, "/"), model), nil
+	}
+
+	project, err := gp.vertexProject()
+	if err != nil {
+		return "", err
+	}
+	baseURL, location := gp.vertexEndpoint()
+	return fmt.Sprintf("%s/v1/projects/%s/locations/%s/publishers/google/models/%s", baseURL, project, location, model), nil
+}
+
+// vertexEndpoint returns the Vertex AI base URL and the location it serves
+func (gp *GeminiProvider) vertexEndpoint() (string, string) {
+	location := gp.Location
+	if location == "" {
+		location = defaultVertexLocation
+	}
+	baseURL := gp.BaseURL
+	switch {
+	case baseURL != "":
+	case location == defaultVertexLocation:
+		baseURL = "https://aiplatform.googleapis.com"
+	default:
+		baseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+	}
+	return strings.TrimSuffix(baseURL, "/"), location
+}
+
+type googleServiceAccountKey struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+func (gp *GeminiProvider) parseServiceAccount() (*googleServiceAccountKey, error) {
+	var key googleServiceAccountKey
+	if err := json.Unmarshal([]byte(gp.ServiceAccount), &key); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("gemini service account key has no client_email or private_key")
+	}
+	return &key, nil
+}
+
+func (gp *GeminiProvider) vertexProject() (string, error) {
+	if gp.Project != "" {
+		return gp.Project, nil
+	}
+	key, err := gp.parseServiceAccount()
+	if err != nil {
+		return "", err
+	}
+	if key.ProjectID == "" {
+		return "", fmt.Errorf("gemini configuration requires a project when the service account key has none")
+	}
+	return key.ProjectID, nil
+}
+
+// authorize adds the API key, or a service account access token that is fetched once and refreshed
+// when it expires
+func (gp *GeminiProvider) authorize(req *http.Request) error {
+	if gp.ServiceAccount == "" {
+		req.Header.Set("x-goog-api-key", gp.APIKey)
+		return nil
+	}
+
+	gp.mu.Lock()
+	if gp.tokenSource == nil {
+		key, err := gp.parseServiceAccount()
+		if err != nil {
+			gp.mu.Unlock()
+			return err
+		}
+		tokenURL := key.TokenURI
+		if tokenURL == "" {
+			tokenURL = defaultGoogleTokenURL
+		}
+		config := &jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			Scopes:       []string{vertexCloudPlatformURL},
+			TokenURL:     tokenURL,
+		}
+		// The token source outlives the request, so it must not use the request context
+		gp.tokenSource = config.TokenSource(context.Background())
+	}
+	tokenSource := gp.tokenSource
+	gp.mu.Unlock()
+
+	token, err := tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get Vertex AI access token: %w", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+func parseGeminiError(resp *http.Response) error {
+	apiErr := &GeminiError{StatusCode: resp.StatusCode, Status: http.StatusText(resp.StatusCode), Message: http.StatusText(resp.StatusCode)}
+
+	// Streaming errors are a one element array, other errors a single object
+	var body struct {
+		Error geminiErrorBody `json:"error"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		data = bytes.TrimSuffix(bytes.TrimPrefix(data, []byte("[")), []byte("]"))
+	}
+	if err := json.Unmarshal(data, &body); err == nil && body.Error.Message != "" {
+		apiErr.Status = body.Error.Status
+		apiErr.Message = body.Error.Message
+	}
+	return apiErr
+}
+
+// convertGeminiMessages splits the system instruction from the conversation. Assistant messages
+// become model turns and tool results become function responses, which Gemini matches to the
+// calls by name rather than by id.
+func convertGeminiMessages(messages []Message) (*geminiContent, []geminiContent) {
+	var system []geminiPart
+	var contents []geminiContent
+	toolNames := make(map[string]string)
+
+	appendParts := func(role string, parts []geminiPart) {
+		if len(parts) == 0 {
+			return
+		}
+		if last := len(contents) - 1; last >= 0 && contents[last].Role == role {
+			contents[last].Parts = append(contents[last].Parts, parts...)
+			return
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: parts})
+	}
+
+	for _, msg := range messages {
+		switch {
+		case msg.OfSystem != nil:
+			system = append(system, geminiTextParts(msg.OfSystem.Content.OfString.Value, msg.OfSystem.Content.OfArrayOfContentParts)...)
+		case msg.OfDeveloper != nil:
+			system = append(system, geminiTextParts(msg.OfDeveloper.Content.OfString.Value, msg.OfDeveloper.Content.OfArrayOfContentParts)...)
+		case msg.OfUser != nil:
+			appendParts(RoleUser, convertGeminiUserContent(msg.OfUser.Content))
+		case msg.OfAssistant != nil:
+			var parts []geminiPart
+			if text := msg.OfAssistant.Content.OfString.Value; text != "" {
+				parts = append(parts, geminiPart{Text: text})
+			}
+			for _, part := range msg.OfAssistant.Content.OfArrayOfContentParts {
+				if part.OfText != nil && part.OfText.Text != "" {
+					parts = append(parts, geminiPart{Text: part.OfText.Text})
+				}
+			}
+			for _, toolCall := range msg.OfAssistant.ToolCalls {
+				toolNames[toolCall.ID] = toolCall.Function.Name
+				var args map[string]any
+				_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: toolCall.Function.Name, Args: args}})
+			}
+			appendParts("model", parts)
+		case msg.OfTool != nil:
+			content := msg.OfTool.Content.OfString.Value
+			for _, part := range msg.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			// The response must be an object, results that are not one are wrapped
+			var response map[string]any
+			if err := json.Unmarshal([]byte(content), &response); err != nil || response == nil {
+				response = map[string]any{"result": content}
+			}
+			appendParts(RoleUser, []geminiPart{{FunctionResponse: &geminiFunctionResponse{Name: toolNames[msg.OfTool.ToolCallID], Response: response}}})
+		}
+	}
+
+	if len(system) == 0 {
+		return nil, contents
+	}
+	return &geminiContent{Parts: system}, contents
+}
+
+func geminiTextParts(text string, parts []openai.ChatCompletionContentPartTextParam) []geminiPart {
+	var result []geminiPart
+	if text != "" {
+		result = append(result, geminiPart{Text: text})
+	}
+	for _, part := range parts {
+		if part.Text != "" {
+			result = append(result, geminiPart{Text: part.Text})
+		}
+	}
+	return result
+}
+
+func convertGeminiUserContent(content openai.ChatCompletionUserMessageParamContentUnion) []geminiPart {
+	var parts []geminiPart
+	if text := content.OfString.Value; text != "" {
+		parts = append(parts, geminiPart{Text: text})
+	}
+	for _, part := range content.OfArrayOfContentParts {
+		switch {
+		case part.OfText != nil && part.OfText.Text != "":
+			parts = append(parts, geminiPart{Text: part.OfText.Text})
+		case part.OfImageURL != nil:
+			parts = append(parts, geminiImage(part.OfImageURL.ImageURL.URL))
+		}
+	}
+	return parts
+}
+
+// geminiImage inlines data URLs and references other URLs, such as gs:// URIs, as file data
+func geminiImage(url string) geminiPart {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if mimeType, data, ok := strings.Cut(rest, ";base64,"); ok {
+			return geminiPart{InlineData: &geminiBlob{MimeType: mimeType, Data: data}}
+		}
+	}
+	mimeType := mime.TypeByExtension(path.Ext(url))
+	if mimeType == "" {
+		mimeType = "image/jpeg"
+	}
+	return geminiPart{FileData: &geminiFileData{MimeType: mimeType, FileURI: url}}
+}
+
+func convertGeminiTools(tools []openai.ChatCompletionToolParam) []geminiFunctionDeclaration {
+	declarations := make([]geminiFunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declaration := geminiFunctionDeclaration{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description.Value,
+		}
+		// Tools without parameters must leave them out, an empty object schema is rejected
+		if properties, ok := tool.Function.Parameters["properties"].(map[string]any); ok && len(properties) > 0 {
+			declaration.Parameters = geminiSchema(map[string]any(tool.Function.Parameters))
+		}
+		declarations = append(declarations, declaration)
+	}
+	return declarations
+}
+
+// geminiSchema removes the JSON schema keywords the Gemini Schema object does not accept and turns
+// nullable type lists such as ["string", "null"] into a type and the nullable flag
+func geminiSchema(schema any) any {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	result := make(map[string]any, len(object))
+	for key, value := range object {
+		if !geminiSchemaFields[key] {
+			continue
+		}
+		switch key {
+		case "type":
+			types, ok := value.([]any)
+			if !ok {
+				result[key] = value
+				continue
+			}
+			for _, t := range types {
+				if t == "null" {
+					result["nullable"] = true
+				} else if _, set := result["type"]; !set {
+					result["type"] = t
+				}
+			}
+		case "properties":
+			properties, _ := value.(map[string]any)
+			converted := make(map[string]any, len(properties))
+			for name, property := range properties {
+				converted[name] = geminiSchema(property)
+			}
+			result[key] = converted
+		case "items":
+			result[key] = geminiSchema(value)
+		case "anyOf":
+			options, _ := value.([]any)
+			converted := make([]any, len(options))
+			for i, option := range options {
+				converted[i] = geminiSchema(option)
+			}
+			result[key] = converted
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (gp *GeminiProvider) convertResponse(response geminiResponse, schemaFunction string) *openai.ChatCompletion {
+	idPrefix := response.ResponseID
+	if idPrefix == "" {
+		idPrefix = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	completion := &openai.ChatCompletion{
+		ID:      response.ResponseID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   response.ModelVersion,
+		Usage:   response.UsageMetadata.toCompletionUsage(),
+	}
+
+	for _, candidate := range response.Candidates {
+		message := openai.ChatCompletionMessage{Role: RoleAssistant}
+		schemaCalled := false
+		for _, part := range candidate.Content.Parts {
+			switch {
+			case part.Thought:
+				// Thought summaries are not part of the answer
+			case part.FunctionCall != nil && schemaFunction != "" && part.FunctionCall.Name == schemaFunction:
+				// Structured output replaces any text, so that the content stays valid JSON
+				message.Content = mustMarshalJSON(part.FunctionCall.Args)
+				schemaCalled = true
+			case part.FunctionCall != nil:
+				index := int64(len(message.ToolCalls))
+				message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+					ID:   geminiToolCallID(part.FunctionCall, fmt.Sprintf("%s_%d", idPrefix, candidate.Index), index),
+					Type: "function",
+					Function: openai.ChatCompletionMessageToolCallFunction{
+						Name:      part.FunctionCall.Name,
+						Arguments: mustMarshalJSON(part.FunctionCall.Args),
+					},
+				})
+			case !schemaCalled:
+				message.Content += part.Text
+			}
+		}
+
+		finishReason := geminiFinishReason(candidate.FinishReason, len(message.ToolCalls) > 0)
+		if finishReason == "" {
+			finishReason = "stop"
+		}
+		completion.Choices = append(completion.Choices, openai.ChatCompletionChoice{
+			Index:        candidate.Index,
+			Message:      message,
+			FinishReason: finishReason,
+		})
+	}
+
+	return completion
+}
+
+// geminiToolCallID returns the id of a function call. Gemini only sometimes assigns ids, the
+// others are generated as the engine matches tool results to calls by id.
+func geminiToolCallID(call *geminiFunctionCall, prefix string, index int64) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return fmt.Sprintf("call_%s_%d", prefix, index)
+}
+
+// geminiFinishReason maps a Gemini finish reason to an OpenAI finish reason. Gemini finishes with
+// STOP after function calls too.
+func geminiFinishReason(finishReason string, hasToolCalls bool) string {
+	switch finishReason {
+	case "":
+		return ""
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "content_filter"
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+// toCompletionUsage counts thinking tokens as completion tokens, as they are billed as output
+func (u geminiUsage) toCompletionUsage() openai.CompletionUsage {
+	completionTokens := u.CandidatesTokenCount + u.ThoughtsTokenCount
+	return openai.CompletionUsage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: completionTokens,
+		TotalTokens:      u.PromptTokenCount + completionTokens,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{
+			CachedTokens: u.CachedContentTokenCount,
+		},
+		CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{
+			ReasoningTokens: u.ThoughtsTokenCount,
+		},
+	}
+}
+
+func (gp *GeminiProvider) BuildConfig() map[string]any {
+	config := map[string]any{}
+	if gp.BaseURL != "" {
+		config["baseUrl"] = gp.BaseURL
+	}
+	if gp.APIKey != "" {
+		config["apiKey"] = gp.APIKey
+	}
+	if gp.ServiceAccount != "" {
+		config["serviceAccount"] = gp.ServiceAccount
+	}
+	if gp.Project != "" {
+		config["project"] = gp.Project
+	}
+	if gp.Location != "" {
+		config["location"] = gp.Location
+	}
+	return config
+}
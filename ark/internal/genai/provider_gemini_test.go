@@ -0,0 +1,282 @@
+package genai
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newGeminiTestProvider(baseURL string, properties map[string]string) *GeminiProvider {
+	return &GeminiProvider{
+		Model:      "gemini-2.5-flash",
+		BaseURL:    baseURL,
+		APIKey:     "gemini-test-key",
+		Headers:    map[string]string{},
+		Properties: properties,
+	}
+}
+
+func TestGeminiChatCompletion(t *testing.T) {
+	stub := &providerStub{body: `{
+		"responseId": "resp-1", "modelVersion": "gemini-2.5-flash",
+		"candidates": [{"index": 0, "finishReason": "STOP", "content": {"role": "model", "parts": [
+			{"text": "planning the lookup", "thought": true},
+			{"text": "Let me check."},
+			{"functionCall": {"name": "get_weather", "args": {"city": "Paris"}}}
+		]}}],
+		"usageMetadata": {"promptTokenCount": 40, "candidatesTokenCount": 12, "thoughtsTokenCount": 8, "cachedContentTokenCount": 30}
+	}`}
+	server := stub.start(t)
+	provider := newGeminiTestProvider(server.URL, map[string]string{"temperature": "0.2", "max_tokens": "1024", "stop": "END"})
+
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"Lyon"}`}},
+	}
+	messages := []Message{
+		NewSystemMessage("You are a weather assistant."),
+		NewUserMessage("Weather in Lyon?"),
+		Message(assistant),
+		Message(openai.ToolMessage("sunny", "call_1")),
+		NewUserMessage("And Paris?"),
+	}
+	tool := openai.ChatCompletionToolParam{
+		Function: openai.FunctionDefinitionParam{
+			Name:        "get_weather",
+			Description: openai.String("Current weather of a city"),
+			Parameters: openai.FunctionParameters{
+				"$schema":              "http://json-schema.org/draft-07/schema#",
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties":           map[string]any{"city": map[string]any{"type": []any{"string", "null"}}},
+			},
+		},
+	}
+
+	completion, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{tool})
+	require.NoError(t, err)
+
+	t.Run("sends the conversation as Gemini contents", func(t *testing.T) {
+		require.Equal(t, "/v1beta/models/gemini-2.5-flash:generateContent", stub.paths[0])
+		require.Equal(t, "gemini-test-key", stub.headers[0].Get("x-goog-api-key"))
+
+		request := stub.requests[0]
+		require.Equal(t, map[string]any{"parts": []any{map[string]any{"text": "You are a weather assistant."}}}, request["systemInstruction"])
+		require.Equal(t, map[string]any{"temperature": 0.2, "maxOutputTokens": float64(1024), "stopSequences": []any{"END"}}, request["generationConfig"])
+		require.Equal(t, []any{map[string]any{"functionDeclarations": []any{map[string]any{
+			"name":        "get_weather",
+			"description": "Current weather of a city",
+			"parameters":  map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string", "nullable": true}}},
+		}}}}, request["tools"])
+
+		require.Equal(t, []any{
+			map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Weather in Lyon?"}}},
+			map[string]any{"role": "model", "parts": []any{map[string]any{"functionCall": map[string]any{"name": "get_weather", "args": map[string]any{"city": "Lyon"}}}}},
+			map[string]any{"role": "user", "parts": []any{
+				map[string]any{"functionResponse": map[string]any{"name": "get_weather", "response": map[string]any{"result": "sunny"}}},
+				map[string]any{"text": "And Paris?"},
+			}},
+		}, request["contents"])
+	})
+
+	t.Run("converts the response to a chat completion", func(t *testing.T) {
+		require.Equal(t, "resp-1", completion.ID)
+		message := completion.Choices[0].Message
+		require.Equal(t, "Let me check.", message.Content, "thoughts are left out")
+		require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+		require.Len(t, message.ToolCalls, 1)
+		require.Equal(t, "call_resp-1_0_0", message.ToolCalls[0].ID)
+		require.JSONEq(t, `{"city":"Paris"}`, message.ToolCalls[0].Function.Arguments)
+
+		require.Equal(t, int64(40), completion.Usage.PromptTokens)
+		require.Equal(t, int64(20), completion.Usage.CompletionTokens)
+		require.Equal(t, int64(60), completion.Usage.TotalTokens)
+		require.Equal(t, int64(30), completion.Usage.PromptTokensDetails.CachedTokens)
+		require.Equal(t, int64(8), completion.Usage.CompletionTokensDetails.ReasoningTokens)
+	})
+}
+
+func TestGeminiChatCompletionStream(t *testing.T) {
+	stub := &providerStub{encoding: crlfServerSentEvents, events: []string{
+		`{"responseId": "resp-2", "modelVersion": "gemini-2.5-flash", "candidates": [{"content": {"role": "model", "parts": [{"text": "Checking "}]}}]}`,
+		`{"responseId": "resp-2", "candidates": [{"content": {"role": "model", "parts": [{"text": "now."}]}}]}`,
+		`{"responseId": "resp-2", "candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"id": "fc-1", "name": "get_weather", "args": {"city": "Paris"}}}]}, "finishReason": "STOP"}], "usageMetadata": {"promptTokenCount": 25, "candidatesTokenCount": 9}}`,
+	}}
+	server := stub.start(t)
+	provider := newGeminiTestProvider(server.URL, nil)
+
+	var chunks []*openai.ChatCompletionChunk
+	completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Weather in Paris?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, "/v1beta/models/gemini-2.5-flash:streamGenerateContent?alt=sse", stub.paths[0])
+	require.Len(t, chunks, 3)
+	require.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	require.Equal(t, "Checking ", chunks[0].Choices[0].Delta.Content)
+	require.Equal(t, "fc-1", chunks[2].Choices[0].Delta.ToolCalls[0].ID)
+	require.Equal(t, "tool_calls", chunks[2].Choices[0].FinishReason)
+	require.Equal(t, int64(34), chunks[2].Usage.TotalTokens)
+
+	require.Equal(t, "resp-2", completion.ID)
+	require.Equal(t, "Checking now.", completion.Choices[0].Message.Content)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, "get_weather", completion.Choices[0].Message.ToolCalls[0].Function.Name)
+	require.JSONEq(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, int64(25), completion.Usage.PromptTokens)
+}
+
+func TestGeminiStructuredOutput(t *testing.T) {
+	stub := &providerStub{body: `{"candidates": [{"finishReason": "STOP", "content": {"role": "model", "parts": [{"text": "{\"answer\": \"42\"}"}]}}]}`}
+	server := stub.start(t)
+	provider := newGeminiTestProvider(server.URL, nil)
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(`{"type": "object", "additionalProperties": false, "properties": {"answer": {"type": "string"}}, "required": ["answer"]}`)}, "answer")
+
+	completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("question")}, 1)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"responseMimeType": "application/json",
+		"responseSchema":   map[string]any{"type": "object", "properties": map[string]any{"answer": map[string]any{"type": "string"}}, "required": []any{"answer"}},
+	}, stub.requests[0]["generationConfig"])
+	require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+	require.Equal(t, "stop", completion.Choices[0].FinishReason)
+}
+
+func TestGeminiStructuredOutputWithTools(t *testing.T) {
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"answer": {"type": "string"}}, "required": ["answer"]}`)}
+
+	t.Run("complete response", func(t *testing.T) {
+		stub := &providerStub{body: `{"candidates": [{"finishReason": "STOP", "content": {"role": "model", "parts": [
+			{"text": "Here is the answer."},
+			{"functionCall": {"name": "answer", "args": {"answer": "42"}}}
+		]}}]}`}
+		server := stub.start(t)
+		provider := newGeminiTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer")
+
+		completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("question")}, 1, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+
+		require.NotContains(t, stub.requests[0], "generationConfig", "no JSON response is requested with tools")
+		declarations := stub.requests[0]["tools"].([]any)[0].(map[string]any)["functionDeclarations"].([]any)
+		require.Len(t, declarations, 2)
+		require.Equal(t, "get_weather", declarations[0].(map[string]any)["name"])
+		require.Equal(t, "answer", declarations[1].(map[string]any)["name"])
+		require.Equal(t, map[string]any{"type": "object", "properties": map[string]any{"answer": map[string]any{"type": "string"}}, "required": []any{"answer"}}, declarations[1].(map[string]any)["parameters"])
+
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+		require.Empty(t, completion.Choices[0].Message.ToolCalls)
+		require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		stub := &providerStub{encoding: crlfServerSentEvents, events: []string{
+			`{"responseId": "resp-3", "candidates": [{"content": {"role": "model", "parts": [{"text": "Here is "}]}}]}`,
+			`{"responseId": "resp-3", "candidates": [{"content": {"role": "model", "parts": [{"text": "the answer."}]}}]}`,
+			`{"responseId": "resp-3", "candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"name": "answer", "args": {"answer": "42"}}}]}, "finishReason": "STOP"}]}`,
+		}}
+		server := stub.start(t)
+		provider := newGeminiTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer")
+
+		var streamed string
+		completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("question")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+			streamed += chunk.Choices[0].Delta.Content
+			return nil
+		}, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{"answer":"42"}`, streamed)
+		require.JSONEq(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+		require.Empty(t, completion.Choices[0].Message.ToolCalls)
+		require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	})
+
+	t.Run("stream without structured output", func(t *testing.T) {
+		stub := &providerStub{encoding: crlfServerSentEvents, events: []string{
+			`{"responseId": "resp-4", "candidates": [{"content": {"role": "model", "parts": [{"text": "Checking "}]}}]}`,
+			`{"responseId": "resp-4", "candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"name": "get_weather", "args": {"city": "Paris"}}}]}, "finishReason": "STOP"}]}`,
+		}}
+		server := stub.start(t)
+		provider := newGeminiTestProvider(server.URL, nil)
+		provider.SetOutputSchema(schema, "answer")
+
+		completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("question")}, 1, func(*openai.ChatCompletionChunk) error {
+			return nil
+		}, []openai.ChatCompletionToolParam{weatherTool})
+		require.NoError(t, err)
+		require.Equal(t, "Checking ", completion.Choices[0].Message.Content)
+		require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+		require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	})
+}
+
+func TestGeminiVertexAI(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	stub := &providerStub{
+		body:   `{"candidates": [{"finishReason": "STOP", "content": {"role": "model", "parts": [{"text": "Hello"}]}}]}`,
+		routes: map[string]string{"/token": `{"access_token": "vertex-token", "token_type": "Bearer", "expires_in": 3600}`},
+	}
+	server := stub.start(t)
+	serviceAccount, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "ark-project",
+		"private_key":  string(keyPEM),
+		"client_email": "ark@ark-project.iam.gserviceaccount.com",
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	provider := &GeminiProvider{
+		Model:          "gemini-2.5-pro",
+		BaseURL:        server.URL,
+		ServiceAccount: string(serviceAccount),
+		Location:       "europe-west4",
+	}
+
+	for range 2 {
+		_, err = provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, 1)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, "/v1/projects/ark-project/locations/europe-west4/publishers/google/models/gemini-2.5-pro:generateContent", stub.paths[0])
+	require.Equal(t, "Bearer vertex-token", stub.headers[1].Get("Authorization"))
+	require.Empty(t, stub.headers[0].Get("x-goog-api-key"))
+}
+
+func TestGeminiErrors(t *testing.T) {
+	stub := &providerStub{status: http.StatusTooManyRequests, body: `{"error": {"code": 429, "message": "Resource has been exhausted", "status": "RESOURCE_EXHAUSTED"}}`}
+	server := stub.start(t)
+
+	_, err := newGeminiTestProvider(server.URL, nil).ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+	var apiErr *GeminiError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "RESOURCE_EXHAUSTED", apiErr.Status)
+	require.Equal(t, "Resource has been exhausted (429)", extractStableError(err, 0))
+
+	provider := &GeminiProvider{Model: "gemini-2.5-pro", ServiceAccount: `{"client_email": "ark@example.com", "private_key": "key"}`}
+	_, err = provider.endpoint(false)
+	require.ErrorContains(t, err, "requires a project")
+}
+
+func TestGeminiImage(t *testing.T) {
+	require.Equal(t, geminiPart{InlineData: &geminiBlob{MimeType: "image/png", Data: "iVBORw0KGgo="}}, geminiImage("data:image/png;base64,iVBORw0KGgo="))
+	part := geminiImage("gs://bucket/cat.png")
+	require.Equal(t, "gs://bucket/cat.png", part.FileData.FileURI)
+	require.True(t, strings.HasPrefix(part.FileData.MimeType, "image/png"))
+}
@@ -17,10 +17,14 @@ type OpenAIProvider struct {
 	Model        string
 	BaseURL      string
 	APIKey       string
+	API          string
 	Headers      map[string]string
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	generation   *GenerationParameters
+	// reasoningItems are kept between the calls of the Responses API
+	reasoningItems responsesReasoningItems
 }
 
 func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -28,7 +32,15 @@ func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNa
 	op.schemaName = schemaName
 }
 
+func (op *OpenAIProvider) SetGenerationParameters(params *GenerationParameters) {
+	op.generation = params
+}
+
 func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if op.API == ModelAPIResponses {
+		return op.responses(ctx).ChatCompletion(ctx, messages, tools...)
+	}
+
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
 		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
@@ -41,6 +53,7 @@ func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyGenerationToParams(op.generation, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -93,6 +106,11 @@ func accumulateStreamChunk(chunk *openai.ChatCompletionChunk, fullResponse **ope
 		(*fullResponse).Choices[0].Message.Content += choice.Delta.Content
 	}
 
+	if reasoning := reasoningContent(choice.Delta.JSON.ExtraFields); reasoning != "" {
+		message := &(*fullResponse).Choices[0].Message
+		message.JSON.ExtraFields = reasoningContentFields(reasoningContent(message.JSON.ExtraFields) + reasoning)
+	}
+
 	// Accumulate tool calls per OpenAI streaming specification
 	for _, deltaToolCall := range choice.Delta.ToolCalls {
 		if existingCall, exists := toolCallsMap[deltaToolCall.Index]; exists {
@@ -203,6 +221,7 @@ func (op *OpenAIProvider) prepareStreamParams(messages []Message, n int64, tools
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyGenerationToParams(op.generation, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -217,6 +236,10 @@ func (op *OpenAIProvider) prepareStreamParams(messages []Message, n int64, tools
 func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	logf.Log.Info("OpenAIProvider.ChatCompletionStream called", "messageCount", len(messages), "toolCount", len(tools))
 
+	if op.API == ModelAPIResponses {
+		return op.responses(ctx).ChatCompletionStream(ctx, messages, streamFunc, tools...)
+	}
+
 	params := op.prepareStreamParams(messages, n, tools...)
 
 	client := op.createClient(ctx)
@@ -262,6 +285,52 @@ func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []M
 	return fullResponse, nil
 }
 
+// ProbeModelList checks that the models endpoint lists the model, which OpenAI compatible servers
+// also provide
+func (op *OpenAIProvider) ProbeModelList(ctx context.Context) error {
+	client := op.createClient(ctx)
+	models, err := client.Models.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, model := range models.Data {
+		if model.ID == op.Model {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errModelNotListed, op.Model)
+}
+
+func (op *OpenAIProvider) Embed(ctx context.Context, inputs []string, dimensions *int64) (*EmbeddingResponse, error) {
+	return createOpenAIEmbeddings(ctx, op.createClient(ctx), op.Model, inputs, dimensions)
+}
+
+// createOpenAIEmbeddings calls the embeddings endpoint, which Azure and OpenAI compatible servers share
+func createOpenAIEmbeddings(ctx context.Context, client openai.Client, model string, inputs []string, dimensions *int64) (*EmbeddingResponse, error) {
+	params := openai.EmbeddingNewParams{
+		Model:          model,
+		Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	}
+	if dimensions != nil {
+		params.Dimensions = openai.Int(*dimensions)
+	}
+
+	response, err := client.Embeddings.New(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float64, len(inputs))
+	for _, embedding := range response.Data {
+		if embedding.Index < 0 || embedding.Index >= int64(len(embeddings)) {
+			return nil, fmt.Errorf("embedding index %d out of range for %d inputs", embedding.Index, len(inputs))
+		}
+		embeddings[embedding.Index] = embedding.Embedding
+	}
+	return &EmbeddingResponse{Embeddings: embeddings, PromptTokens: response.Usage.PromptTokens}, nil
+}
+
 func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 	var httpClient *http.Client
 	if IsProbeContext(ctx) {
@@ -281,6 +350,18 @@ func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 	return openai.NewClient(options...)
 }
 
+func (op *OpenAIProvider) responses(ctx context.Context) responsesAPI {
+	return responsesAPI{
+		client:       op.createClient(ctx),
+		model:        op.Model,
+		properties:   op.Properties,
+		outputSchema: op.outputSchema,
+		schemaName:   op.schemaName,
+		generation:   op.generation,
+		reasoning:    &op.reasoningItems,
+	}
+}
+
 func (op *OpenAIProvider) BuildConfig() map[string]any {
 	config := map[string]any{
 		"baseUrl": op.BaseURL,
@@ -288,5 +369,8 @@ func (op *OpenAIProvider) BuildConfig() map[string]any {
 	if op.APIKey != "" {
 		config["apiKey"] = op.APIKey
 	}
+	if op.API != "" {
+		config["api"] = op.API
+	}
 	return config
 }
@@ -0,0 +1,580 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/responses"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// responsesPropertyAliases maps Chat Completions property names to their Responses API parameter
+var responsesPropertyAliases = map[string]string{
+	"max_tokens":            "max_output_tokens",
+	"max_completion_tokens": "max_output_tokens",
+}
+
+// responsesMinOutputTokens is the lowest max_output_tokens the Responses API accepts
+const responsesMinOutputTokens = 16
+
+// responsesGenerationFields are the generation parameters the Responses API supports
+var responsesGenerationFields = generationFieldNames{
+	temperature: "temperature",
+	topP:        "top_p",
+	maxTokens:   "max_output_tokens",
+}
+
+// responsesReasoningProperties maps properties to the fields of the reasoning parameter
+var responsesReasoningProperties = map[string]string{
+	"reasoning_effort":  "effort",
+	"reasoning_summary": "summary",
+}
+
+// responsesAPI translates the chat completions of the agent loop to and from the Responses API,
+// which OpenAI and Azure models use with api: responses. The full conversation is sent with every
+// request, so responses are not stored unless the store property is set, and previous_response_id
+// is not used: the conversation is kept in Ark memory, where any model can continue it.
+type responsesAPI struct {
+	client       openai.Client
+	model        string
+	properties   map[string]string
+	outputSchema *runtime.RawExtension
+	schemaName   string
+	generation   *GenerationParameters
+	reasoning    *responsesReasoningItems
+}
+
+// responsesReasoningItems keeps the reasoning items that preceded function calls, by the ID of the
+// first call. Reasoning models continue their reasoning after the tool results when the items are
+// sent back with the calls, which chat completion messages have no field for.
+type responsesReasoningItems struct {
+	mu     sync.Mutex
+	byCall map[string][]responses.ResponseReasoningItemParam
+}
+
+func (r *responsesReasoningItems) add(callID string, items []responses.ResponseReasoningItemParam) {
+	if r == nil || len(items) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byCall == nil {
+		r.byCall = make(map[string][]responses.ResponseReasoningItemParam)
+	}
+	r.byCall[callID] = items
+}
+
+func (r *responsesReasoningItems) get(callID string) []responses.ResponseReasoningItemParam {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.byCall[callID]
+}
+
+// ChatCompletion creates a response. The API returns a single output, so n is ignored.
+func (r responsesAPI) ChatCompletion(ctx context.Context, messages []Message, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	params, err := r.buildParams(messages, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.Responses.New(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status == responses.ResponseStatusFailed {
+		return nil, fmt.Errorf("response failed: %s", response.Error.Message)
+	}
+
+	return convertResponsesOutput(response, r.reasoning), nil
+}
+
+func (r responsesAPI) ChatCompletionStream(ctx context.Context, messages []Message, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	params, err := r.buildParams(messages, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := r.client.Responses.NewStreaming(ctx, params)
+	defer func() { _ = stream.Close() }()
+
+	var fullResponse *openai.ChatCompletion
+	toolCallsMap := make(map[int64]*openai.ChatCompletionMessageToolCall)
+	state := &responsesStreamState{
+		model:     r.model,
+		created:   time.Now().Unix(),
+		toolCalls: make(map[int64]int64),
+		reasoning: r.reasoning,
+	}
+
+	for stream.Next() {
+		chunk, err := state.toChunk(stream.Current())
+		if err != nil {
+			return nil, err
+		}
+		if chunk == nil {
+			continue
+		}
+		if err := streamFunc(chunk); err != nil {
+			return nil, err
+		}
+		accumulateStreamChunk(chunk, &fullResponse, toolCallsMap)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	if fullResponse == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+
+	if len(toolCallsMap) > 0 && len(fullResponse.Choices) > 0 {
+		toolCalls := make([]openai.ChatCompletionMessageToolCall, 0, len(toolCallsMap))
+		for i := int64(0); i < int64(len(toolCallsMap)); i++ {
+			toolCalls = append(toolCalls, *toolCallsMap[i])
+		}
+		fullResponse.Choices[0].Message.ToolCalls = toolCalls
+	}
+	fullResponse.Usage = state.usage
+
+	return fullResponse, nil
+}
+
+func (r responsesAPI) buildParams(messages []Message, tools ...[]openai.ChatCompletionToolParam) (responses.ResponseNewParams, error) {
+	params := responses.ResponseNewParams{
+		Model: r.model,
+		Input: responses.ResponseNewParamsInputUnion{OfInputItemList: convertResponsesInput(messages, r.reasoning)},
+		Store: openai.Bool(false),
+	}
+
+	if len(tools) > 0 {
+		params.Tools = convertResponsesTools(tools[0])
+	}
+
+	if r.outputSchema != nil && r.outputSchema.Raw != nil {
+		var schema map[string]any
+		if err := json.Unmarshal(r.outputSchema.Raw, &schema); err != nil {
+			return params, fmt.Errorf("failed to parse output schema: %w", err)
+		}
+		params.Text = responses.ResponseTextConfigParam{
+			Format: responses.ResponseFormatTextConfigUnionParam{
+				OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
+					Name:   r.schemaName,
+					Schema: schema,
+					Strict: openai.Bool(true),
+				},
+			},
+		}
+	}
+
+	extra := responsesExtraFields(r.properties, r.generation)
+	// Responses that are not stored return their reasoning encrypted, to be sent back with the
+	// function calls. Only reasoning models accept this, so it is requested with reasoning settings.
+	if _, reasoning := extra["reasoning"]; reasoning && r.properties["store"] != TrueString {
+		params.Include = []responses.ResponseIncludable{responses.ResponseIncludableReasoningEncryptedContent}
+	}
+	if len(extra) > 0 {
+		params.SetExtraFields(extra)
+	}
+
+	return params, nil
+}
+
+// responsesExtraFields turns the model properties into request parameters, renaming Chat
+// Completions parameters that the Responses API names differently. Values that are valid JSON
+// are sent as JSON, other values as strings. Generation parameters replace properties.
+func responsesExtraFields(properties map[string]string, generation *GenerationParameters) map[string]any {
+	extra := make(map[string]any)
+	reasoning := make(map[string]any)
+	for key, value := range properties {
+		// The Responses API returns a single output
+		if value == "" || key == "n" {
+			continue
+		}
+		if field, ok := responsesReasoningProperties[key]; ok {
+			reasoning[field] = value
+			continue
+		}
+		if alias, ok := responsesPropertyAliases[key]; ok {
+			key = alias
+		}
+		extra[key] = parsePropertyValue(value)
+	}
+	for key, value := range generation.fields(responsesGenerationFields) {
+		extra[key] = value
+	}
+	if maxTokens, ok := extra["max_output_tokens"].(int64); ok && maxTokens < responsesMinOutputTokens {
+		extra["max_output_tokens"] = int64(responsesMinOutputTokens)
+	}
+	if generation != nil && generation.ReasoningEffort != "" {
+		reasoning["effort"] = generation.ReasoningEffort
+	}
+	if len(reasoning) > 0 {
+		extra["reasoning"] = reasoning
+	}
+	return extra
+}
+
+// convertResponsesInput turns the conversation into input items. Assistant tool calls become
+// function_call items, preceded by the reasoning items kept for them, and tool messages become
+// function_call_output items.
+func convertResponsesInput(messages []Message, reasoning *responsesReasoningItems) responses.ResponseInputParam {
+	var items responses.ResponseInputParam
+
+	appendMessage := func(role responses.EasyInputMessageRole, content responses.EasyInputMessageContentUnionParam) {
+		items = append(items, responses.ResponseInputItemUnionParam{
+			OfMessage: &responses.EasyInputMessageParam{Role: role, Content: content},
+		})
+	}
+	textContent := func(text string, parts []openai.ChatCompletionContentPartTextParam) responses.EasyInputMessageContentUnionParam {
+		for _, part := range parts {
+			text += part.Text
+		}
+		return responses.EasyInputMessageContentUnionParam{OfString: openai.String(text)}
+	}
+
+	for _, msg := range messages {
+		switch {
+		case msg.OfSystem != nil:
+			appendMessage(responses.EasyInputMessageRoleSystem, textContent(msg.OfSystem.Content.OfString.Value, msg.OfSystem.Content.OfArrayOfContentParts))
+		case msg.OfDeveloper != nil:
+			appendMessage(responses.EasyInputMessageRoleDeveloper, textContent(msg.OfDeveloper.Content.OfString.Value, msg.OfDeveloper.Content.OfArrayOfContentParts))
+		case msg.OfUser != nil:
+			appendMessage(responses.EasyInputMessageRoleUser, convertResponsesUserContent(msg.OfUser.Content))
+		case msg.OfAssistant != nil:
+			text := msg.OfAssistant.Content.OfString.Value
+			for _, part := range msg.OfAssistant.Content.OfArrayOfContentParts {
+				if part.OfText != nil {
+					text += part.OfText.Text
+				}
+			}
+			// Reasoning comes first in the output, so the items of the first call precede the text
+			appendReasoning := func(callID string) {
+				for _, item := range reasoning.get(callID) {
+					items = append(items, responses.ResponseInputItemUnionParam{OfReasoning: &item})
+				}
+			}
+			if len(msg.OfAssistant.ToolCalls) > 0 {
+				appendReasoning(msg.OfAssistant.ToolCalls[0].ID)
+			}
+			if text != "" {
+				appendMessage(responses.EasyInputMessageRoleAssistant, responses.EasyInputMessageContentUnionParam{OfString: openai.String(text)})
+			}
+			for i, toolCall := range msg.OfAssistant.ToolCalls {
+				if i > 0 {
+					appendReasoning(toolCall.ID)
+				}
+				items = append(items, responses.ResponseInputItemUnionParam{
+					OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+						CallID:    toolCall.ID,
+						Name:      toolCall.Function.Name,
+						Arguments: toolCall.Function.Arguments,
+					},
+				})
+			}
+		case msg.OfTool != nil:
+			output := msg.OfTool.Content.OfString.Value
+			for _, part := range msg.OfTool.Content.OfArrayOfContentParts {
+				output += part.Text
+			}
+			items = append(items, responses.ResponseInputItemUnionParam{
+				OfFunctionCallOutput: &responses.ResponseInputItemFunctionCallOutputParam{
+					CallID: msg.OfTool.ToolCallID,
+					Output: output,
+				},
+			})
+		}
+	}
+
+	return items
+}
+
+func convertResponsesUserContent(content openai.ChatCompletionUserMessageParamContentUnion) responses.EasyInputMessageContentUnionParam {
+	if len(content.OfArrayOfContentParts) == 0 {
+		return responses.EasyInputMessageContentUnionParam{OfString: openai.String(content.OfString.Value)}
+	}
+
+	var parts responses.ResponseInputMessageContentListParam
+	for _, part := range content.OfArrayOfContentParts {
+		switch {
+		case part.OfText != nil:
+			parts = append(parts, responses.ResponseInputContentUnionParam{
+				OfInputText: &responses.ResponseInputTextParam{Text: part.OfText.Text},
+			})
+		case part.OfImageURL != nil:
+			detail := responses.ResponseInputImageDetail(part.OfImageURL.ImageURL.Detail)
+			if detail == "" {
+				detail = responses.ResponseInputImageDetailAuto
+			}
+			parts = append(parts, responses.ResponseInputContentUnionParam{
+				OfInputImage: &responses.ResponseInputImageParam{
+					ImageURL: openai.String(part.OfImageURL.ImageURL.URL),
+					Detail:   detail,
+				},
+			})
+		}
+	}
+	return responses.EasyInputMessageContentUnionParam{OfInputItemContentList: parts}
+}
+
+// convertResponsesTools converts function tools. Function tools of the Responses API are strict
+// unless told otherwise, so tools that are not marked strict are sent as non-strict.
+func convertResponsesTools(tools []openai.ChatCompletionToolParam) []responses.ToolUnionParam {
+	result := make([]responses.ToolUnionParam, 0, len(tools))
+	for _, tool := range tools {
+		parameters := map[string]any(tool.Function.Parameters)
+		if parameters == nil {
+			parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		function := &responses.FunctionToolParam{
+			Name:       tool.Function.Name,
+			Parameters: parameters,
+			Strict:     openai.Bool(tool.Function.Strict.Value),
+		}
+		if description := tool.Function.Description.Value; description != "" {
+			function.Description = openai.String(description)
+		}
+		result = append(result, responses.ToolUnionParam{OfFunction: function})
+	}
+	return result
+}
+
+// convertResponsesOutput turns the output items into a message. Reasoning summaries become its
+// reasoning content, and reasoning items are kept for the function call that follows them.
+func convertResponsesOutput(response *responses.Response, reasoning *responsesReasoningItems) *openai.ChatCompletion {
+	message := openai.ChatCompletionMessage{Role: RoleAssistant}
+	var summaries []string
+	var reasoningItems []responses.ResponseReasoningItemParam
+
+	for _, item := range response.Output {
+		switch item.Type {
+		case "reasoning":
+			summaries = append(summaries, responsesReasoningSummary(item)...)
+			reasoningItems = append(reasoningItems, responsesReasoningParam(item))
+		case "message":
+			for _, content := range item.Content {
+				switch content.Type {
+				case "output_text":
+					message.Content += content.Text
+				case "refusal":
+					message.Refusal += content.Refusal
+				}
+			}
+		case "function_call":
+			reasoning.add(item.CallID, reasoningItems)
+			reasoningItems = nil
+			message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+				ID:   item.CallID,
+				Type: "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      item.Name,
+					Arguments: item.Arguments,
+				},
+			})
+		}
+	}
+
+	if len(summaries) > 0 {
+		message.JSON.ExtraFields = reasoningContentFields(strings.Join(summaries, responsesSummarySeparator))
+	}
+
+	return &openai.ChatCompletion{
+		ID:      response.ID,
+		Object:  "chat.completion",
+		Created: int64(response.CreatedAt),
+		Model:   response.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: responsesFinishReason(response, len(message.ToolCalls) > 0),
+		}},
+		Usage: responsesCompletionUsage(response.Usage),
+	}
+}
+
+// responsesSummarySeparator separates the parts of reasoning summaries
+const responsesSummarySeparator = "\n\n"
+
+// responsesReasoningSummary returns the summary parts of a reasoning item
+func responsesReasoningSummary(item responses.ResponseOutputItemUnion) []string {
+	summaries := make([]string, 0, len(item.Summary))
+	for _, summary := range item.Summary {
+		summaries = append(summaries, summary.Text)
+	}
+	return summaries
+}
+
+// responsesReasoningParam turns a reasoning output item into an input item
+func responsesReasoningParam(item responses.ResponseOutputItemUnion) responses.ResponseReasoningItemParam {
+	input := responses.ResponseReasoningItemParam{
+		ID:      item.ID,
+		Summary: make([]responses.ResponseReasoningItemSummaryParam, 0, len(item.Summary)),
+	}
+	for _, summary := range item.Summary {
+		input.Summary = append(input.Summary, responses.ResponseReasoningItemSummaryParam{Text: summary.Text})
+	}
+	if item.EncryptedContent != "" {
+		input.EncryptedContent = openai.String(item.EncryptedContent)
+	}
+	return input
+}
+
+// responsesStreamState maps the events of a streamed response to OpenAI chat completion chunks
+type responsesStreamState struct {
+	id      string
+	model   string
+	created int64
+	usage   openai.CompletionUsage
+	// toolCalls maps the output index of a function call to the index of its tool call
+	toolCalls map[int64]int64
+	reasoning *responsesReasoningItems
+	// reasoningItems are the reasoning items since the last function call
+	reasoningItems []responses.ResponseReasoningItemParam
+}
+
+// toChunk returns the chunk for an event, or nil for events that carry nothing to stream
+func (s *responsesStreamState) toChunk(event responses.ResponseStreamEventUnion) (*openai.ChatCompletionChunk, error) {
+	switch event.Type {
+	case "response.created":
+		s.id = event.Response.ID
+		if event.Response.Model != "" {
+			s.model = event.Response.Model
+		}
+		if event.Response.CreatedAt > 0 {
+			s.created = int64(event.Response.CreatedAt)
+		}
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{Role: RoleAssistant}, ""), nil
+
+	case "response.output_item.added":
+		if event.Item.Type != "function_call" {
+			return nil, nil
+		}
+		s.reasoning.add(event.Item.CallID, s.reasoningItems)
+		s.reasoningItems = nil
+		index := int64(len(s.toolCalls))
+		s.toolCalls[event.OutputIndex] = index
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index: index,
+				ID:    event.Item.CallID,
+				Type:  "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{
+					Name:      event.Item.Name,
+					Arguments: event.Item.Arguments,
+				},
+			}},
+		}, ""), nil
+
+	case "response.function_call_arguments.delta":
+		index, ok := s.toolCalls[event.OutputIndex]
+		if !ok || event.Delta.OfString == "" {
+			return nil, nil
+		}
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    index,
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: event.Delta.OfString},
+			}},
+		}, ""), nil
+
+	case "response.output_item.done":
+		if event.Item.Type == "reasoning" {
+			s.reasoningItems = append(s.reasoningItems, responsesReasoningParam(event.Item))
+		}
+		return nil, nil
+
+	case "response.reasoning_summary_part.added":
+		if event.SummaryIndex == 0 {
+			return nil, nil
+		}
+		return s.reasoningChunk(responsesSummarySeparator), nil
+
+	case "response.reasoning_summary_text.delta":
+		if event.Delta.OfString == "" {
+			return nil, nil
+		}
+		return s.reasoningChunk(event.Delta.OfString), nil
+
+	case "response.output_text.delta":
+		if event.Delta.OfString == "" {
+			return nil, nil
+		}
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta.OfString}, ""), nil
+
+	case "response.refusal.delta":
+		return s.chunk(openai.ChatCompletionChunkChoiceDelta{Refusal: event.Delta.OfString}, ""), nil
+
+	case "response.completed", "response.incomplete":
+		s.usage = responsesCompletionUsage(event.Response.Usage)
+		chunk := s.chunk(openai.ChatCompletionChunkChoiceDelta{}, responsesFinishReason(&event.Response, len(s.toolCalls) > 0))
+		chunk.Usage = s.usage
+		return chunk, nil
+
+	case "response.failed":
+		return nil, fmt.Errorf("response failed: %s", event.Response.Error.Message)
+
+	case "error":
+		return nil, fmt.Errorf("response stream error: %s", event.Message)
+	}
+
+	// Content part and other done events carry nothing to stream
+	return nil, nil
+}
+
+// reasoningChunk streams a piece of a reasoning summary as reasoning content
+func (s *responsesStreamState) reasoningChunk(reasoning string) *openai.ChatCompletionChunk {
+	delta := openai.ChatCompletionChunkChoiceDelta{}
+	delta.JSON.ExtraFields = reasoningContentFields(reasoning)
+	return s.chunk(delta, "")
+}
+
+func (s *responsesStreamState) chunk(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) *openai.ChatCompletionChunk {
+	return &openai.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+		Choices: []openai.ChatCompletionChunkChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
+
+// responsesFinishReason derives a finish reason, as responses report a status instead
+func responsesFinishReason(response *responses.Response, hasToolCalls bool) string {
+	if response.Status == responses.ResponseStatusIncomplete {
+		switch response.IncompleteDetails.Reason {
+		case "max_output_tokens":
+			return "length"
+		case "content_filter":
+			return "content_filter"
+		}
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+func responsesCompletionUsage(usage responses.ResponseUsage) openai.CompletionUsage {
+	return openai.CompletionUsage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{
+			CachedTokens: usage.InputTokensDetails.CachedTokens,
+		},
+		CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{
+			ReasoningTokens: usage.OutputTokensDetails.ReasoningTokens,
+		},
+	}
+}
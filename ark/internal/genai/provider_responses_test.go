@@ -0,0 +1,279 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+const responsesToolCallBody = `{
+	"id": "resp_1", "object": "response", "created_at": 1700000000, "model": "o4-mini", "status": "completed",
+	"output": [
+		{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "Need the weather."}, {"type": "summary_text", "text": "Paris first."}], "encrypted_content": "gAAA1"},
+		{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed",
+		 "content": [{"type": "output_text", "text": "Let me check.", "annotations": []}]},
+		{"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_weather", "arguments": "{\"city\":\"Paris\"}", "status": "completed"}
+	],
+	"usage": {
+		"input_tokens": 20, "input_tokens_details": {"cached_tokens": 5},
+		"output_tokens": 30, "output_tokens_details": {"reasoning_tokens": 18},
+		"total_tokens": 50
+	}
+}`
+
+func TestOpenAIResponsesChatCompletion(t *testing.T) {
+	stub := &providerStub{body: responsesToolCallBody}
+	server := stub.start(t)
+
+	provider := &OpenAIProvider{
+		Model:      "o4-mini",
+		BaseURL:    server.URL,
+		APIKey:     "sk-test",
+		API:        ModelAPIResponses,
+		Properties: map[string]string{"max_tokens": "1024", "reasoning_effort": "low", "n": "1"},
+	}
+	assistant := openai.ChatCompletionAssistantMessageParam{
+		ToolCalls: []openai.ChatCompletionMessageToolCallParam{{
+			ID:       "call_0",
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"Rome"}`},
+		}},
+	}
+	messages := []Message{
+		NewSystemMessage("Be brief."),
+		NewUserMessage("Weather in Rome and Paris?"),
+		Message(openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant}),
+		Message(openai.ToolMessage("Sunny", "call_0")),
+	}
+
+	completion, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/responses"}, stub.paths)
+	request := stub.requests[0]
+	require.Equal(t, "o4-mini", request["model"])
+	require.Equal(t, false, request["store"])
+	require.Equal(t, float64(1024), request["max_output_tokens"])
+	require.Equal(t, map[string]any{"effort": "low"}, request["reasoning"])
+	require.Equal(t, []any{"reasoning.encrypted_content"}, request["include"])
+	require.NotContains(t, request, "n")
+	require.NotContains(t, request, "max_tokens")
+
+	input := request["input"].([]any)
+	require.Len(t, input, 4)
+	require.Equal(t, map[string]any{"role": "system", "content": "Be brief."}, input[0])
+	require.Equal(t, map[string]any{"role": "user", "content": "Weather in Rome and Paris?"}, input[1])
+	require.Equal(t, map[string]any{"type": "function_call", "call_id": "call_0", "name": "get_weather", "arguments": `{"city":"Rome"}`}, input[2])
+	require.Equal(t, map[string]any{"type": "function_call_output", "call_id": "call_0", "output": "Sunny"}, input[3])
+
+	tool := request["tools"].([]any)[0].(map[string]any)
+	require.Equal(t, "function", tool["type"])
+	require.Equal(t, "get_weather", tool["name"])
+	require.Equal(t, false, tool["strict"])
+
+	require.Equal(t, "resp_1", completion.ID)
+	require.Equal(t, "o4-mini", completion.Model)
+	require.Equal(t, "Let me check.", completion.Choices[0].Message.Content)
+	require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, "call_1", completion.Choices[0].Message.ToolCalls[0].ID)
+	require.Equal(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, int64(50), completion.Usage.TotalTokens)
+	require.Equal(t, int64(5), completion.Usage.PromptTokensDetails.CachedTokens)
+	require.Equal(t, int64(18), completion.Usage.CompletionTokensDetails.ReasoningTokens)
+	require.Equal(t, "Need the weather.\n\nParis first.", reasoningContent(completion.Choices[0].Message.JSON.ExtraFields))
+
+	// The reasoning items are sent back in their place before the function call
+	messages = append(messages, Message(completion.Choices[0].Message.ToParam()), Message(openai.ToolMessage("Rainy", "call_1")))
+	_, err = provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	input = stub.requests[1]["input"].([]any)
+	require.Len(t, input, 8)
+	require.Equal(t, map[string]any{
+		"type": "reasoning", "id": "rs_1", "encrypted_content": "gAAA1",
+		"summary": []any{
+			map[string]any{"type": "summary_text", "text": "Need the weather."},
+			map[string]any{"type": "summary_text", "text": "Paris first."},
+		},
+	}, input[4])
+	require.Equal(t, map[string]any{"role": "assistant", "content": "Let me check."}, input[5])
+	require.Equal(t, "call_1", input[6].(map[string]any)["call_id"])
+	require.Equal(t, "function_call_output", input[7].(map[string]any)["type"])
+}
+
+func TestResponsesEncryptedReasoning(t *testing.T) {
+	stub := &providerStub{body: responsesToolCallBody}
+	server := stub.start(t)
+
+	// Reasoning is not requested encrypted without reasoning settings, or when responses are stored
+	for _, properties := range []map[string]string{nil, {"reasoning_effort": "low", "store": "true"}} {
+		provider := &OpenAIProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "sk-test", API: ModelAPIResponses, Properties: properties}
+		_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Hi")}, 1)
+		require.NoError(t, err)
+	}
+	require.NotContains(t, stub.requests[0], "include")
+	require.NotContains(t, stub.requests[1], "include")
+	require.Equal(t, true, stub.requests[1]["store"])
+}
+
+func TestOpenAIResponsesStream(t *testing.T) {
+	stub := &providerStub{encoding: typedServerSentEvents, events: []string{
+		`{"type": "response.created", "sequence_number": 0, "response": {"id": "resp_1", "object": "response", "created_at": 1700000000, "model": "o4-mini", "status": "in_progress", "output": []}}`,
+		`{"type": "response.reasoning_summary_part.added", "sequence_number": 1, "item_id": "rs_1", "output_index": 0, "summary_index": 0, "part": {"type": "summary_text", "text": ""}}`,
+		`{"type": "response.reasoning_summary_text.delta", "sequence_number": 1, "item_id": "rs_1", "output_index": 0, "summary_index": 0, "delta": "Need the weather."}`,
+		`{"type": "response.reasoning_summary_part.added", "sequence_number": 1, "item_id": "rs_1", "output_index": 0, "summary_index": 1, "part": {"type": "summary_text", "text": ""}}`,
+		`{"type": "response.reasoning_summary_text.delta", "sequence_number": 1, "item_id": "rs_1", "output_index": 0, "summary_index": 1, "delta": "Paris first."}`,
+		`{"type": "response.output_item.done", "sequence_number": 1, "output_index": 0, "item": {"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "Need the weather."}, {"type": "summary_text", "text": "Paris first."}], "encrypted_content": "gAAA1"}}`,
+		`{"type": "response.output_text.delta", "sequence_number": 2, "item_id": "msg_1", "output_index": 1, "content_index": 0, "delta": "Let me "}`,
+		`{"type": "response.output_text.delta", "sequence_number": 3, "item_id": "msg_1", "output_index": 1, "content_index": 0, "delta": "check."}`,
+		`{"type": "response.output_item.added", "sequence_number": 4, "output_index": 2, "item": {"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_weather", "arguments": ""}}`,
+		`{"type": "response.function_call_arguments.delta", "sequence_number": 5, "item_id": "fc_1", "output_index": 2, "delta": "{\"city\":"}`,
+		`{"type": "response.function_call_arguments.delta", "sequence_number": 6, "item_id": "fc_1", "output_index": 2, "delta": "\"Paris\"}"}`,
+		`{"type": "response.completed", "sequence_number": 7, "response": {"id": "resp_1", "object": "response", "created_at": 1700000000, "model": "o4-mini", "status": "completed", "output": [],
+		  "usage": {"input_tokens": 20, "input_tokens_details": {"cached_tokens": 0}, "output_tokens": 30, "output_tokens_details": {"reasoning_tokens": 18}, "total_tokens": 50}}}`,
+	}}
+	server := stub.start(t)
+
+	provider := &OpenAIProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "sk-test", API: ModelAPIResponses}
+	var chunks []*openai.ChatCompletionChunk
+	completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Weather in Paris?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	require.Equal(t, true, stub.requests[0]["stream"])
+	require.Len(t, chunks, 10)
+	require.Equal(t, "resp_1", chunks[0].ID)
+	require.Equal(t, "Need the weather.", reasoningContent(chunks[1].Choices[0].Delta.JSON.ExtraFields))
+	require.Equal(t, "\n\n", reasoningContent(chunks[2].Choices[0].Delta.JSON.ExtraFields))
+	require.Equal(t, "Paris first.", reasoningContent(chunks[3].Choices[0].Delta.JSON.ExtraFields))
+	require.Equal(t, "Let me ", chunks[4].Choices[0].Delta.Content)
+	require.Equal(t, "call_1", chunks[6].Choices[0].Delta.ToolCalls[0].ID)
+	require.Equal(t, "tool_calls", chunks[9].Choices[0].FinishReason)
+	require.Equal(t, int64(18), chunks[9].Usage.CompletionTokensDetails.ReasoningTokens)
+
+	// Reasoning content is streamed in the delta, where OpenAI compatible clients expect it
+	data, err := json.Marshal(ChunkWithMetadata{ChatCompletionChunk: chunks[1], Ark: &StreamMetadata{Agent: "weather"}})
+	require.NoError(t, err)
+	var streamed struct {
+		Choices []struct {
+			Delta map[string]any `json:"delta"`
+		} `json:"choices"`
+		Ark StreamMetadata `json:"ark"`
+	}
+	require.NoError(t, json.Unmarshal(data, &streamed))
+	require.Equal(t, "Need the weather.", streamed.Choices[0].Delta["reasoning_content"])
+	require.Equal(t, "weather", streamed.Ark.Agent)
+
+	require.Equal(t, "Let me check.", completion.Choices[0].Message.Content)
+	require.Equal(t, "Need the weather.\n\nParis first.", reasoningContent(completion.Choices[0].Message.JSON.ExtraFields))
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Len(t, provider.reasoningItems.get("call_1"), 1)
+	require.Equal(t, "gAAA1", provider.reasoningItems.get("call_1")[0].EncryptedContent.Value)
+	require.Equal(t, int64(50), completion.Usage.TotalTokens)
+	require.Equal(t, int64(18), completion.Usage.CompletionTokensDetails.ReasoningTokens)
+}
+
+func TestOpenAIResponsesIncomplete(t *testing.T) {
+	stub := &providerStub{encoding: typedServerSentEvents, events: []string{
+		`{"type": "response.created", "sequence_number": 0, "response": {"id": "resp_1", "object": "response", "model": "o4-mini", "status": "in_progress", "output": []}}`,
+		`{"type": "response.output_text.delta", "sequence_number": 1, "item_id": "msg_1", "output_index": 0, "content_index": 0, "delta": "Once upon"}`,
+		`{"type": "response.incomplete", "sequence_number": 2, "response": {"id": "resp_1", "object": "response", "model": "o4-mini", "status": "incomplete",
+		  "incomplete_details": {"reason": "max_output_tokens"}, "output": [], "usage": {"input_tokens": 5, "output_tokens": 2, "total_tokens": 7}}}`,
+	}}
+	server := stub.start(t)
+
+	provider := &OpenAIProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "sk-test", API: ModelAPIResponses}
+	completion, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Tell a story")}, 1, func(*openai.ChatCompletionChunk) error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, "length", completion.Choices[0].FinishReason)
+
+	stub = &providerStub{encoding: typedServerSentEvents, events: []string{
+		`{"type": "response.failed", "sequence_number": 0, "response": {"id": "resp_1", "object": "response", "model": "o4-mini", "status": "failed", "output": [],
+		  "error": {"code": "server_error", "message": "The model failed"}}}`,
+	}}
+	server = stub.start(t)
+	provider.BaseURL = server.URL
+	_, err = provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("Tell a story")}, 1, func(*openai.ChatCompletionChunk) error { return nil })
+	require.Error(t, err)
+}
+
+func TestAzureResponses(t *testing.T) {
+	stub := &providerStub{body: `{
+		"id": "resp_1", "object": "response", "created_at": 1700000000, "model": "gpt-4.1", "status": "completed",
+		"output": [{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed",
+		            "content": [{"type": "output_text", "text": "{\"answer\":\"42\"}", "annotations": []}]}],
+		"usage": {"input_tokens": 5, "output_tokens": 3, "total_tokens": 8}
+	}`}
+	server := stub.start(t)
+
+	provider := &AzureProvider{
+		Model:      "my-deployment",
+		BaseURL:    server.URL,
+		APIKey:     "azure-key",
+		APIVersion: "2025-04-01-preview",
+		API:        ModelAPIResponses,
+	}
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"answer":{"type":"string"}}}`)}, "answer")
+
+	completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Answer?")}, 1)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/openai/responses?api-version=2025-04-01-preview"}, stub.paths)
+	require.Equal(t, "my-deployment", stub.requests[0]["model"])
+	format := stub.requests[0]["text"].(map[string]any)["format"].(map[string]any)
+	require.Equal(t, "json_schema", format["type"])
+	require.Equal(t, "answer", format["name"])
+	require.Equal(t, true, format["strict"])
+	require.Equal(t, `{"answer":"42"}`, completion.Choices[0].Message.Content)
+	require.Equal(t, "stop", completion.Choices[0].FinishReason)
+}
+
+func TestResponsesMinimumOutputTokens(t *testing.T) {
+	maxTokens := int64(1)
+	extra := responsesExtraFields(nil, &GenerationParameters{MaxTokens: &maxTokens})
+	require.Equal(t, int64(responsesMinOutputTokens), extra["max_output_tokens"])
+}
+
+// reasoningRecorder records the reasoning tokens set on model spans
+type reasoningRecorder struct {
+	telemetry.ModelRecorder
+	reasoningTokens int64
+}
+
+func (r *reasoningRecorder) RecordReasoningTokens(span telemetry.Span, reasoningTokens int64) {
+	r.reasoningTokens = reasoningTokens
+}
+
+func TestModelRecordsReasoningTokens(t *testing.T) {
+	stub := &providerStub{body: responsesToolCallBody}
+	server := stub.start(t)
+
+	spans := &reasoningRecorder{ModelRecorder: noop.NewModelRecorder()}
+	usage := &usageRecorder{ModelRecorder: eventnoop.NewModelRecorder()}
+	model := &Model{
+		Name:              "reasoner",
+		Namespace:         "default",
+		Model:             "o4-mini",
+		Type:              ModelTypeOpenAI,
+		Provider:          &OpenAIProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "sk-test", API: ModelAPIResponses},
+		telemetryRecorder: spans,
+		eventingRecorder:  usage,
+	}
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("Weather in Paris?")}, nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(18), spans.reasoningTokens)
+	require.Len(t, usage.usage, 1)
+	require.Equal(t, int64(18), usage.usage[0].Usage.ReasoningTokens)
+	require.Equal(t, int64(30), usage.usage[0].Usage.CompletionTokens)
+}
@@ -0,0 +1,355 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	// latencySmoothing is the weight of the latest call in the recent latency of a model
+	latencySmoothing = 0.3
+	// failedRouteLatency is the latency counted for a failed call, so the latency strategy moves
+	// away from a failing model
+	failedRouteLatency = time.Minute
+)
+
+// modelLatencies holds the recent latency of the models called by routers. It is shared by all
+// routers, so every call to a model informs the choices of the others.
+var modelLatencies = &latencyTracker{latencies: make(map[string]time.Duration)}
+
+type latencyTracker struct {
+	mu        sync.Mutex
+	latencies map[string]time.Duration
+}
+
+// observe adds the latency of a call to the moving average of the model
+func (t *latencyTracker) observe(key string, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.latencies[key]
+	if !ok {
+		t.latencies[key] = latency
+		return
+	}
+	t.latencies[key] = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(previous))
+}
+
+func (t *latencyTracker) get(key string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latency, ok := t.latencies[key]
+	return latency, ok
+}
+
+// RouterProvider sends each call of a router Model to the Model of one of its routes. The routes
+// that match the call are the candidates, and the strategy picks one of them.
+type RouterProvider struct {
+	Name      string
+	Namespace string
+	Strategy  string
+	Routes    []*modelRoute
+
+	generation        *GenerationParameters
+	outputSchema      *runtime.RawExtension
+	telemetryRecorder telemetry.ModelRecorder
+	// random returns a number in [0, n) for the weighted strategy
+	random    func(n int) int
+	latencies *latencyTracker
+}
+
+type modelRoute struct {
+	model           *Model
+	weight          int
+	minPromptTokens *int64
+	maxPromptTokens *int64
+	// capabilities replace the capabilities detected for the model when set
+	capabilities []string
+	maxCost      *float64
+	// fallbacks are called in order when the call to the model fails
+	fallbacks []*Model
+}
+
+// routeRequest describes a call to a router for matching it with the routes
+type routeRequest struct {
+	promptTokens int64
+	capabilities []string
+}
+
+func (r *RouterProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	r.outputSchema = schema
+	for _, model := range r.models() {
+		model.OutputSchema = schema
+		model.SchemaName = schemaName
+		model.Provider.SetOutputSchema(schema, schemaName)
+	}
+}
+
+// SetGenerationParameters passes the parameters of the router and the calling agent on to the
+// route models, where they take precedence over the parameters of each model
+func (r *RouterProvider) SetGenerationParameters(params *GenerationParameters) {
+	r.generation = params
+	for _, model := range r.models() {
+		model.GenerationOverrides = params
+	}
+}
+
+func (r *RouterProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return r.route(ctx, messages, n, nil, tools)
+}
+
+func (r *RouterProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return r.route(ctx, messages, n, streamFunc, tools)
+}
+
+func (r *RouterProvider) route(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools [][]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := routeRequest{
+		promptTokens: estimateTokens(messages, tools, nil),
+		capabilities: r.requiredCapabilities(messages, tools, streamFunc != nil),
+	}
+
+	var candidates []*modelRoute
+	for _, route := range r.Routes {
+		if r.matches(route, request) {
+			candidates = append(candidates, route)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no route of router %s/%s matches the call with %d estimated prompt tokens requiring %v",
+			r.Namespace, r.Name, request.promptTokens, request.capabilities)
+	}
+	candidateNames := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		candidateNames[i] = candidate.model.Name
+	}
+
+	route := r.choose(candidates)
+	span := modelSpanFromContext(ctx)
+	r.recordRoute(span, route.model.Name, r.Strategy, candidateNames)
+
+	response, streamed, err := r.call(ctx, route.model, messages, n, streamFunc, tools)
+	previous := route.model
+	for _, fallback := range route.fallbacks {
+		// Chunks that were streamed cannot be taken back, so a stream that started is not retried
+		if err == nil || streamed || ctx.Err() != nil {
+			break
+		}
+		r.recordRoute(span, fallback.Name, fmt.Sprintf("fallback after %s failed", previous.Name), candidateNames)
+		response, streamed, err = r.call(ctx, fallback, messages, n, streamFunc, tools)
+		previous = fallback
+	}
+	return response, err
+}
+
+// call calls a route model and records its latency. It also reports whether chunks were streamed.
+func (r *RouterProvider) call(ctx context.Context, model *Model, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools [][]openai.ChatCompletionToolParam) (*openai.ChatCompletion, bool, error) {
+	var eventStream *routedEventStream
+	if streamFunc != nil {
+		eventStream = &routedEventStream{streamFunc: streamFunc}
+	}
+
+	start := time.Now()
+	var response *openai.ChatCompletion
+	var err error
+	if eventStream != nil {
+		response, err = model.ChatCompletion(ctx, messages, eventStream, n, tools...)
+	} else {
+		response, err = model.ChatCompletion(ctx, messages, nil, n, tools...)
+	}
+
+	latency := time.Since(start)
+	if err != nil {
+		latency = max(latency, failedRouteLatency)
+	}
+	r.latencies.observe(model.Namespace+"/"+model.Name, latency)
+
+	return response, eventStream != nil && eventStream.streamed, err
+}
+
+// choose picks a route from the candidates with the strategy of the router
+func (r *RouterProvider) choose(candidates []*modelRoute) *modelRoute {
+	if r.Strategy == RouterStrategyLatency {
+		// Models without calls yet are tried first, so every model gets a latency
+		var fastest *modelRoute
+		var fastestLatency time.Duration
+		for _, candidate := range candidates {
+			latency, ok := r.latencies.get(candidate.model.Namespace + "/" + candidate.model.Name)
+			if !ok {
+				return candidate
+			}
+			if fastest == nil || latency < fastestLatency {
+				fastest, fastestLatency = candidate, latency
+			}
+		}
+		return fastest
+	}
+
+	total := 0
+	for _, candidate := range candidates {
+		total += candidate.weight
+	}
+	// Routes with weight 0 only take calls no other route matches
+	if total == 0 {
+		return candidates[0]
+	}
+	pick := r.random(total)
+	for _, candidate := range candidates {
+		if pick < candidate.weight {
+			return candidate
+		}
+		pick -= candidate.weight
+	}
+	return candidates[len(candidates)-1]
+}
+
+// matches reports whether the call is within the prompt size, capabilities and cost of the route
+func (r *RouterProvider) matches(route *modelRoute, request routeRequest) bool {
+	if route.minPromptTokens != nil && request.promptTokens < *route.minPromptTokens {
+		return false
+	}
+	if route.maxPromptTokens != nil && request.promptTokens > *route.maxPromptTokens {
+		return false
+	}
+	for _, capability := range request.capabilities {
+		if !route.supports(capability) {
+			return false
+		}
+	}
+	if route.maxCost != nil && route.model.pricing != nil {
+		if r.estimatedCost(route.model, request.promptTokens) > *route.maxCost {
+			return false
+		}
+	}
+	return true
+}
+
+// estimatedCost prices the estimated prompt and the maximum number of output tokens of the call.
+// Without a maximum only the prompt is priced.
+func (r *RouterProvider) estimatedCost(model *Model, promptTokens int64) float64 {
+	cost := float64(promptTokens) * model.pricing.input
+	if generation := model.Generation.withOverrides(r.generation); generation != nil && generation.MaxTokens != nil {
+		cost += float64(*generation.MaxTokens) * model.pricing.output
+	}
+	return cost
+}
+
+// supports reports whether the model of the route has the capability. Capabilities that were not
+// detected are assumed to be supported.
+func (route *modelRoute) supports(capability string) bool {
+	if route.capabilities != nil {
+		return slices.Contains(route.capabilities, capability)
+	}
+
+	detected := route.model.capabilities
+	if detected == nil {
+		return true
+	}
+	var supported *bool
+	switch capability {
+	case CapabilityToolCalling:
+		supported = detected.ToolCalling
+	case CapabilityStructuredOutput:
+		supported = detected.StructuredOutput
+	case CapabilityStreaming:
+		supported = detected.Streaming
+	case CapabilityVision:
+		supported = detected.Vision
+	}
+	return supported == nil || *supported
+}
+
+// requiredCapabilities returns the capabilities a model needs for the call
+func (r *RouterProvider) requiredCapabilities(messages []Message, tools [][]openai.ChatCompletionToolParam, streaming bool) []string {
+	var capabilities []string
+	for _, toolSet := range tools {
+		if len(toolSet) > 0 {
+			capabilities = append(capabilities, CapabilityToolCalling)
+			break
+		}
+	}
+	if r.outputSchema != nil {
+		capabilities = append(capabilities, CapabilityStructuredOutput)
+	}
+	if streaming {
+		capabilities = append(capabilities, CapabilityStreaming)
+	}
+	if hasImages(messages) {
+		capabilities = append(capabilities, CapabilityVision)
+	}
+	return capabilities
+}
+
+func hasImages(messages []Message) bool {
+	for _, message := range messages {
+		if message.OfUser == nil {
+			continue
+		}
+		for _, part := range message.OfUser.Content.OfArrayOfContentParts {
+			if part.OfImageURL != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// models returns the models of the routes and their fallbacks, each once
+func (r *RouterProvider) models() []*Model {
+	var models []*Model
+	for _, route := range r.Routes {
+		for _, model := range append([]*Model{route.model}, route.fallbacks...) {
+			if !slices.Contains(models, model) {
+				models = append(models, model)
+			}
+		}
+	}
+	return models
+}
+
+func (r *RouterProvider) recordRoute(span telemetry.Span, model, reason string, candidates []string) {
+	if span == nil {
+		return
+	}
+	r.telemetryRecorder.RecordRoute(span, model, reason, candidates)
+}
+
+// routedEventStream passes the chunks streamed by a route model on to the stream of the router
+type routedEventStream struct {
+	streamFunc func(*openai.ChatCompletionChunk) error
+	streamed   bool
+}
+
+func (s *routedEventStream) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.streamed = true
+	switch chunk := chunk.(type) {
+	case ChunkWithMetadata:
+		return s.streamFunc(chunk.ChatCompletionChunk)
+	case *openai.ChatCompletionChunk:
+		return s.streamFunc(chunk)
+	}
+	return nil
+}
+
+func (s *routedEventStream) NotifyCompletion(ctx context.Context) error { return nil }
+func (s *routedEventStream) Close() error                               { return nil }
+
+type modelSpanKey struct{}
+
+// contextWithModelSpan makes the span of a model call available to its provider
+func contextWithModelSpan(ctx context.Context, span telemetry.Span) context.Context {
+	return context.WithValue(ctx, modelSpanKey{}, span)
+}
+
+func modelSpanFromContext(ctx context.Context) telemetry.Span {
+	span, _ := ctx.Value(modelSpanKey{}).(telemetry.Span)
+	return span
+}
@@ -0,0 +1,292 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// routeRecorder records the routing decisions made on model spans
+type routeRecorder struct {
+	telemetry.ModelRecorder
+	routes  []string
+	reasons []string
+}
+
+func (r *routeRecorder) RecordRoute(span telemetry.Span, model, reason string, candidates []string) {
+	r.routes = append(r.routes, model)
+	r.reasons = append(r.reasons, reason)
+}
+
+type routeTestModel struct {
+	*Model
+	stub *providerStub
+}
+
+func newRouteTestModel(t *testing.T, name string) routeTestModel {
+	stub := newChatStub()
+	server := stub.start(t)
+	model := newProbeTestModel(server.URL, "gpt-4o")
+	model.Name = name
+	return routeTestModel{Model: model, stub: stub}
+}
+
+func newTestRouter(strategy string, routes ...*modelRoute) (*Model, *routeRecorder) {
+	recorder := &routeRecorder{ModelRecorder: noop.NewModelRecorder()}
+	router := &Model{
+		Name:      "test-router",
+		Namespace: "default",
+		Model:     "test-router",
+		Type:      ModelTypeRouter,
+		Provider: &RouterProvider{
+			Name:              "test-router",
+			Namespace:         "default",
+			Strategy:          strategy,
+			Routes:            routes,
+			telemetryRecorder: recorder,
+			random:            func(n int) int { return 0 },
+			latencies:         &latencyTracker{latencies: make(map[string]time.Duration)},
+		},
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+	return router, recorder
+}
+
+func hello() []Message {
+	return []Message{NewUserMessage("Say hello.")}
+}
+
+func TestRouterWeighted(t *testing.T) {
+	a := newRouteTestModel(t, "model-a")
+	b := newRouteTestModel(t, "model-b")
+	router, recorder := newTestRouter(RouterStrategyWeighted,
+		&modelRoute{model: a.Model, weight: 3},
+		&modelRoute{model: b.Model, weight: 1},
+	)
+	provider := router.Provider.(*RouterProvider)
+
+	for _, pick := range []int{0, 2, 3} {
+		provider.random = func(n int) int {
+			require.Equal(t, 4, n)
+			return pick
+		}
+		_, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, a.stub.requests, 2)
+	require.Len(t, b.stub.requests, 1)
+	require.Equal(t, []string{"model-a", "model-a", "model-b"}, recorder.routes)
+	require.Equal(t, RouterStrategyWeighted, recorder.reasons[0])
+}
+
+func TestRouterMatching(t *testing.T) {
+	image := Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What is in this image?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: capabilityTestImage}),
+	}))
+
+	t.Run("prompt size", func(t *testing.T) {
+		small := newRouteTestModel(t, "small")
+		large := newRouteTestModel(t, "large")
+		router, recorder := newTestRouter(RouterStrategyWeighted,
+			&modelRoute{model: small.Model, weight: 1, maxPromptTokens: ptr.To(int64(100))},
+			&modelRoute{model: large.Model, weight: 1, minPromptTokens: ptr.To(int64(101))},
+		)
+
+		_, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+		require.NoError(t, err)
+		_, err = router.ChatCompletion(context.Background(), []Message{NewUserMessage(string(make([]byte, 2000)))}, nil, 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"small", "large"}, recorder.routes)
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		text := newRouteTestModel(t, "text")
+		text.capabilities = &arkv1alpha1.ModelCapabilities{Vision: ptr.To(false)}
+		vision := newRouteTestModel(t, "vision")
+		router, recorder := newTestRouter(RouterStrategyWeighted,
+			&modelRoute{model: text.Model, weight: 1},
+			&modelRoute{model: vision.Model, weight: 1, capabilities: []string{CapabilityVision}},
+		)
+
+		_, err := router.ChatCompletion(context.Background(), []Message{image}, nil, 1)
+		require.Error(t, err, "the vision stub rejects images")
+		require.Equal(t, []string{"vision"}, recorder.routes)
+
+		// Routes that list their capabilities are only used for those
+		tool := openai.ChatCompletionToolParam{Function: openai.FunctionDefinitionParam{Name: "get_time"}}
+		_, err = router.ChatCompletion(context.Background(), hello(), nil, 1, []openai.ChatCompletionToolParam{tool})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vision", "text"}, recorder.routes)
+	})
+
+	t.Run("cost ceiling", func(t *testing.T) {
+		expensive := newRouteTestModel(t, "expensive")
+		expensive.pricing = &modelPricing{input: 10.0 / 1e6, output: 30.0 / 1e6, currency: defaultCurrency}
+		cheap := newRouteTestModel(t, "cheap")
+		router, recorder := newTestRouter(RouterStrategyWeighted,
+			&modelRoute{model: expensive.Model, weight: 1, maxCost: ptr.To(0.01)},
+			&modelRoute{model: cheap.Model, weight: 1},
+		)
+
+		// 500 output tokens cost 0.015 on the expensive model
+		router.GenerationOverrides = &GenerationParameters{MaxTokens: ptr.To(int64(500))}
+		_, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+		require.NoError(t, err)
+
+		router.GenerationOverrides = &GenerationParameters{MaxTokens: ptr.To(int64(100))}
+		_, err = router.ChatCompletion(context.Background(), hello(), nil, 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"cheap", "expensive"}, recorder.routes)
+		require.Equal(t, float64(100), expensive.stub.requests[0]["max_completion_tokens"])
+	})
+
+	t.Run("no matching route", func(t *testing.T) {
+		small := newRouteTestModel(t, "small")
+		router, _ := newTestRouter(RouterStrategyWeighted,
+			&modelRoute{model: small.Model, weight: 1, maxPromptTokens: ptr.To(int64(1))},
+		)
+
+		_, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+		require.ErrorContains(t, err, "no route of router default/test-router matches the call")
+		require.Empty(t, small.stub.requests)
+	})
+}
+
+func TestRouterFallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"message": "context length exceeded", "type": "invalid_request_error"}}`))
+	}))
+	t.Cleanup(failing.Close)
+	primary := newProbeTestModel(failing.URL, "gpt-4o")
+	primary.Name = "primary"
+	fallback := newRouteTestModel(t, "fallback")
+
+	router, recorder := newTestRouter(RouterStrategyWeighted,
+		&modelRoute{model: primary, weight: 1, fallbacks: []*Model{fallback.Model}},
+	)
+
+	response, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Hi", response.Choices[0].Message.Content)
+	require.Equal(t, []string{"primary", "fallback"}, recorder.routes)
+	require.Equal(t, "fallback after primary failed", recorder.reasons[1])
+}
+
+func TestRouterLatency(t *testing.T) {
+	slow := newRouteTestModel(t, "slow")
+	fast := newRouteTestModel(t, "fast")
+	router, recorder := newTestRouter(RouterStrategyLatency,
+		&modelRoute{model: slow.Model, weight: 1},
+		&modelRoute{model: fast.Model, weight: 1},
+	)
+	latencies := router.Provider.(*RouterProvider).latencies
+
+	// Models without a latency are tried first
+	latencies.observe("default/slow", 2*time.Second)
+	_, err := router.ChatCompletion(context.Background(), hello(), nil, 1)
+	require.NoError(t, err)
+
+	latencies.observe("default/fast", 100*time.Millisecond)
+	_, err = router.ChatCompletion(context.Background(), hello(), nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fast", "fast"}, recorder.routes)
+
+	// A failed call counts as slow
+	latencies.observe("default/fast", failedRouteLatency)
+	latency, _ := latencies.get("default/fast")
+	require.Greater(t, latency, 2*time.Second)
+}
+
+type collectingEventStream struct {
+	discardEventStream
+	chunks []interface{}
+}
+
+func (s *collectingEventStream) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func TestRouterStreaming(t *testing.T) {
+	backing := newRouteTestModel(t, "backing")
+	router, _ := newTestRouter(RouterStrategyWeighted, &modelRoute{model: backing.Model, weight: 1})
+
+	stream := &collectingEventStream{}
+	_, err := router.ChatCompletion(context.Background(), hello(), stream, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, stream.chunks)
+
+	chunk, ok := stream.chunks[0].(ChunkWithMetadata)
+	require.True(t, ok)
+	require.Equal(t, "Hi", chunk.Choices[0].Delta.Content)
+	require.Equal(t, true, backing.stub.requests[0]["stream"])
+}
+
+func TestLoadRouter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+
+	openaiModel := func(name string) *arkv1alpha1.Model {
+		return &arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Type:  ModelTypeOpenAI,
+				Model: arkv1alpha1.ValueSource{Value: "gpt-4o"},
+				Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+					BaseURL: arkv1alpha1.ValueSource{Value: "https://api.openai.com/v1"},
+					APIKey:  arkv1alpha1.ValueSource{Value: "sk-test"},
+				}},
+			},
+		}
+	}
+	router := func(name string, routes ...arkv1alpha1.ModelRoute) *arkv1alpha1.Model {
+		return &arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Type:   ModelTypeRouter,
+				Config: arkv1alpha1.ModelConfig{Router: &arkv1alpha1.RouterModelConfig{Routes: routes}},
+			},
+		}
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		openaiModel("model-a"),
+		openaiModel("model-b"),
+		router("router", arkv1alpha1.ModelRoute{Model: "model-a", Weight: ptr.To(int32(9)), MaxCost: "0.5", Fallbacks: []string{"model-b"}},
+			arkv1alpha1.ModelRoute{Model: "model-b"}),
+		router("nested", arkv1alpha1.ModelRoute{Model: "router"}),
+	).Build()
+
+	model, err := LoadModel(context.Background(), k8sClient, "router", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	require.Equal(t, "router", model.Model)
+
+	provider := model.Provider.(*RouterProvider)
+	require.Equal(t, RouterStrategyWeighted, provider.Strategy)
+	require.Len(t, provider.Routes, 2)
+	require.Equal(t, 9, provider.Routes[0].weight)
+	require.Equal(t, 0.5, *provider.Routes[0].maxCost)
+	require.Same(t, provider.Routes[1].model, provider.Routes[0].fallbacks[0], "a model is loaded once")
+	require.Equal(t, 1, provider.Routes[1].weight)
+
+	_, err = LoadModel(context.Background(), k8sClient, "nested", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.ErrorContains(t, err, "model default/router of router nested is a router")
+}
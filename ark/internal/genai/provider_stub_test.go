@@ -0,0 +1,175 @@
+package genai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+)
+
+// providerStub is a fake model provider API. It records the requests it receives and answers them
+// with a canned body, or streams its events when they are set. The handler does not run on the test
+// goroutine, so its failures are answered with an error and reported when the test ends.
+type providerStub struct {
+	// path is the path requests must be sent to, any path is accepted when empty
+	path   string
+	status int
+	header http.Header
+	body   string
+	events []string
+	// encoding writes the events, as server-sent events with only data when nil
+	encoding *eventEncoding
+	// respond answers requests instead of the canned body and events when set
+	respond func(w http.ResponseWriter, request map[string]any) error
+	// routes answers requests for other paths, such as token endpoints, with a JSON body. These
+	// requests are not recorded.
+	routes map[string]string
+
+	mu       sync.Mutex
+	paths    []string
+	requests []map[string]any
+	headers  []http.Header
+	failures []error
+}
+
+// eventEncoding writes the events of a streamed response
+type eventEncoding struct {
+	contentType string
+	write       func(w io.Writer, event string) error
+}
+
+var serverSentEvents = &eventEncoding{
+	contentType: "text/event-stream",
+	write: func(w io.Writer, event string) error {
+		_, err := fmt.Fprintf(w, "data: %s\n\n", event)
+		return err
+	},
+}
+
+// typedServerSentEvents names every event with its type field, as the Responses API does
+var typedServerSentEvents = &eventEncoding{
+	contentType: "text/event-stream",
+	write: func(w io.Writer, event string) error {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(event), &typed); err != nil {
+			return fmt.Errorf("invalid event %s: %w", event, err)
+		}
+		var data bytes.Buffer
+		if err := json.Compact(&data, []byte(event)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, data.String())
+		return err
+	},
+}
+
+// crlfServerSentEvents ends lines with CRLF, as the Gemini API does
+var crlfServerSentEvents = &eventEncoding{
+	contentType: "text/event-stream",
+	write: func(w io.Writer, event string) error {
+		_, err := fmt.Fprintf(w, "data: %s\r\n\r\n", event)
+		return err
+	},
+}
+
+// awsEventStream encodes events such as {"messageStart": {...}} as AWS event stream messages, with
+// the key as event type and the value as payload
+var awsEventStream = &eventEncoding{
+	contentType: "application/vnd.amazon.eventstream",
+	write: func(w io.Writer, event string) error {
+		var typed map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(event), &typed); err != nil || len(typed) != 1 {
+			return fmt.Errorf("invalid event %s: want an object with a single event type", event)
+		}
+		for eventType, payload := range typed {
+			var headers eventstream.Headers
+			headers.Set(":message-type", eventstream.StringValue("event"))
+			headers.Set(":event-type", eventstream.StringValue(eventType))
+			headers.Set(":content-type", eventstream.StringValue("application/json"))
+			if err := eventstream.NewEncoder().Encode(w, eventstream.Message{Headers: headers, Payload: payload}); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func (s *providerStub) start(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(func() {
+		server.Close()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		require.Empty(t, s.failures, "the provider stub failed to handle requests")
+	})
+	return server
+}
+
+func (s *providerStub) handle(w http.ResponseWriter, r *http.Request) {
+	if body, ok := s.routes[r.URL.Path]; ok {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+		return
+	}
+
+	if err := s.serve(w, r); err != nil {
+		s.mu.Lock()
+		s.failures = append(s.failures, err)
+		s.mu.Unlock()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *providerStub) serve(w http.ResponseWriter, r *http.Request) error {
+	if s.path != "" && r.URL.Path != s.path {
+		return fmt.Errorf("unexpected request to %s", r.URL.Path)
+	}
+
+	var request map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return fmt.Errorf("failed to decode request to %s: %w", r.URL.Path, err)
+	}
+	s.mu.Lock()
+	s.paths = append(s.paths, r.URL.RequestURI())
+	s.requests = append(s.requests, request)
+	s.headers = append(s.headers, r.Header.Clone())
+	s.mu.Unlock()
+
+	for key, values := range s.header {
+		w.Header()[key] = values
+	}
+
+	if s.respond != nil {
+		return s.respond(w, request)
+	}
+
+	if s.events != nil {
+		encoding := s.encoding
+		if encoding == nil {
+			encoding = serverSentEvents
+		}
+		w.Header().Set("Content-Type", encoding.contentType)
+		for _, event := range s.events {
+			if err := encoding.write(w, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+	_, _ = w.Write([]byte(s.body))
+	return nil
+}
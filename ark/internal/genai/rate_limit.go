@@ -0,0 +1,324 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// charactersPerToken is the rough ratio used to estimate prompt tokens before a call
+const charactersPerToken = 4
+
+// modelLimiters holds the limiter of every Model with limits. It is shared by all queries of the
+// controller, so the limits hold however many queries call the model at the same time.
+var modelLimiters = &limiterRegistry{limiters: make(map[string]*modelLimiter)}
+
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*modelLimiter
+}
+
+// get returns the limiter of the model with the given key, created on first use and updated when
+// the limits of the model change
+func (r *limiterRegistry) get(key string, limits *arkv1alpha1.ModelLimits) *modelLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = newModelLimiter(time.Now)
+		r.limiters[key] = limiter
+	}
+	limiter.configure(limits)
+	return limiter
+}
+
+// remove drops the limiter of the model with the given key. Calls that hold a reservation of the
+// limiter still release it, and the next call with limits starts a new limiter.
+func (r *limiterRegistry) remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, key)
+}
+
+// modelLimiterKey identifies the limiter of a Model resource
+func modelLimiterKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// RemoveModelLimiter drops the limiter of a deleted Model, so the registry only holds the limiters
+// of existing models
+func RemoveModelLimiter(namespace, name string) {
+	modelLimiters.remove(modelLimiterKey(namespace, name))
+}
+
+// modelLimiter enforces the limits of one model. Calls that cannot start wait in a queue per
+// namespace and the queues are served in turn, so one namespace with many calls cannot starve
+// the others.
+type modelLimiter struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	requests    rateBucket
+	tokens      rateBucket
+	maxInFlight int
+	inFlight    int
+	queues      map[string][]*limiterWaiter
+	// namespaces lists the namespaces with waiting calls in the order they are served
+	namespaces []string
+	timer      *time.Timer
+}
+
+type limiterWaiter struct {
+	tokens  float64
+	ready   chan struct{}
+	granted bool
+}
+
+// rateLimitReservation is the capacity held by a call that passed the limiter
+type rateLimitReservation struct {
+	limiter *modelLimiter
+	tokens  float64
+	waited  time.Duration
+}
+
+// rateBucket is a token bucket that refills its capacity once per minute. A bucket with no
+// capacity is unlimited.
+type rateBucket struct {
+	perMinute float64
+	available float64
+	updated   time.Time
+}
+
+func (b *rateBucket) refill(now time.Time) {
+	if b.perMinute == 0 {
+		return
+	}
+	b.available = math.Min(b.perMinute, b.available+now.Sub(b.updated).Minutes()*b.perMinute)
+	b.updated = now
+}
+
+// wait returns how long it takes until amount is available
+func (b *rateBucket) wait(amount float64) time.Duration {
+	if b.perMinute == 0 || b.available >= amount {
+		return 0
+	}
+	return time.Duration((amount - b.available) / b.perMinute * float64(time.Minute))
+}
+
+func (b *rateBucket) take(amount float64) {
+	if b.perMinute > 0 {
+		b.available -= amount
+	}
+}
+
+// setRate changes the capacity, a new bucket starts full
+func (b *rateBucket) setRate(perMinute float64, now time.Time) {
+	if b.perMinute == 0 {
+		b.available = perMinute
+	}
+	b.perMinute = perMinute
+	b.available = math.Min(b.available, perMinute)
+	b.updated = now
+}
+
+func newModelLimiter(now func() time.Time) *modelLimiter {
+	return &modelLimiter{
+		now:    now,
+		queues: make(map[string][]*limiterWaiter),
+	}
+}
+
+func (l *modelLimiter) configure(limits *arkv1alpha1.ModelLimits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.requests.refill(now)
+	l.tokens.refill(now)
+
+	var requests, tokens float64
+	l.maxInFlight = 0
+	if limits != nil {
+		if limits.RequestsPerMinute != nil {
+			requests = float64(*limits.RequestsPerMinute)
+		}
+		if limits.TokensPerMinute != nil {
+			tokens = float64(*limits.TokensPerMinute)
+		}
+		if limits.MaxConcurrentRequests != nil {
+			l.maxInFlight = int(*limits.MaxConcurrentRequests)
+		}
+	}
+	l.requests.setRate(requests, now)
+	l.tokens.setRate(tokens, now)
+	l.dispatch()
+}
+
+// acquire waits until a call of the namespace that is estimated to use tokens can start. The
+// estimate is capped at the tokens per minute, so a large prompt waits for a full bucket rather
+// than forever.
+func (l *modelLimiter) acquire(ctx context.Context, namespace string, tokens int64) (*rateLimitReservation, error) {
+	start := l.now()
+
+	l.mu.Lock()
+	waiter := &limiterWaiter{tokens: float64(tokens), ready: make(chan struct{})}
+	if l.tokens.perMinute > 0 {
+		waiter.tokens = math.Min(waiter.tokens, l.tokens.perMinute)
+	}
+	if len(l.queues[namespace]) == 0 {
+		l.namespaces = append(l.namespaces, namespace)
+	}
+	l.queues[namespace] = append(l.queues[namespace], waiter)
+	l.dispatch()
+	l.mu.Unlock()
+
+	reservation := &rateLimitReservation{limiter: l, tokens: waiter.tokens}
+	select {
+	case <-waiter.ready:
+		reservation.waited = l.now().Sub(start)
+		return reservation, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		granted := waiter.granted
+		if !granted {
+			l.remove(namespace, waiter)
+			l.dispatch()
+		}
+		l.mu.Unlock()
+		if granted {
+			reservation.release(nil)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch starts waiting calls while the limits allow it, and otherwise schedules itself for
+// when the next call can start. Must be called with the mutex held.
+func (l *modelLimiter) dispatch() {
+	for len(l.namespaces) > 0 {
+		if l.maxInFlight > 0 && l.inFlight >= l.maxInFlight {
+			// release dispatches again
+			return
+		}
+
+		now := l.now()
+		l.requests.refill(now)
+		l.tokens.refill(now)
+
+		namespace := l.namespaces[0]
+		waiter := l.queues[namespace][0]
+		if wait := max(l.requests.wait(1), l.tokens.wait(waiter.tokens)); wait > 0 {
+			l.schedule(wait)
+			return
+		}
+
+		l.requests.take(1)
+		l.tokens.take(waiter.tokens)
+		l.inFlight++
+		waiter.granted = true
+		close(waiter.ready)
+
+		// Move the namespace to the back so the next call comes from another namespace
+		l.queues[namespace] = l.queues[namespace][1:]
+		l.namespaces = l.namespaces[1:]
+		if len(l.queues[namespace]) > 0 {
+			l.namespaces = append(l.namespaces, namespace)
+		} else {
+			delete(l.queues, namespace)
+		}
+	}
+}
+
+func (l *modelLimiter) schedule(wait time.Duration) {
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	l.timer = time.AfterFunc(wait, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.dispatch()
+	})
+}
+
+func (l *modelLimiter) remove(namespace string, waiter *limiterWaiter) {
+	queue := l.queues[namespace]
+	for i, queued := range queue {
+		if queued == waiter {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) > 0 {
+		l.queues[namespace] = queue
+		return
+	}
+
+	delete(l.queues, namespace)
+	for i, queued := range l.namespaces {
+		if queued == namespace {
+			l.namespaces = append(l.namespaces[:i], l.namespaces[i+1:]...)
+			break
+		}
+	}
+}
+
+// release ends the call and corrects the reserved tokens with the usage of the response. Calls
+// without a response, or a response without usage, give back or keep the reservation.
+func (r *rateLimitReservation) release(response *openai.ChatCompletion) {
+	if response == nil {
+		r.releaseUsage(nil)
+		return
+	}
+	r.releaseUsage(&response.Usage)
+}
+
+// releaseUsage ends a call that is not a chat completion, such as an embedding call. Calls that
+// failed have no usage.
+func (r *rateLimitReservation) releaseUsage(usage *openai.CompletionUsage) {
+	if r == nil {
+		return
+	}
+
+	l := r.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if l.tokens.perMinute > 0 {
+		used := r.tokens
+		switch {
+		case usage == nil:
+			used = 0
+		case usage.TotalTokens > 0:
+			used = float64(usage.TotalTokens)
+		}
+		// Usage above the estimate can leave the bucket negative, which delays the following calls
+		l.tokens.refill(l.now())
+		l.tokens.available = math.Min(l.tokens.perMinute, l.tokens.available+r.tokens-used)
+	}
+	l.dispatch()
+}
+
+// estimateTokens estimates the tokens of a call from the size of its request and the maximum
+// completion tokens, if set
+func estimateTokens(messages []Message, tools [][]openai.ChatCompletionToolParam, generation *GenerationParameters) int64 {
+	size := 0
+	if body, err := json.Marshal(messages); err == nil {
+		size += len(body)
+	}
+	if body, err := json.Marshal(tools); err == nil && len(tools) > 0 {
+		size += len(body)
+	}
+
+	tokens := int64(size/charactersPerToken) + 1
+	if generation != nil && generation.MaxTokens != nil {
+		tokens += *generation.MaxTokens
+	}
+	return tokens
+}
@@ -0,0 +1,213 @@
+package genai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// testClock is a clock for limiters that only moves when advanced
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(l *modelLimiter, d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dispatch()
+}
+
+func newTestLimiter(limits arkv1alpha1.ModelLimits) (*modelLimiter, *testClock) {
+	clock := &testClock{now: time.Unix(0, 0)}
+	limiter := newModelLimiter(clock.Now)
+	limiter.configure(&limits)
+	return limiter, clock
+}
+
+// acquireInBackground queues a call and waits until it is queued, so calls queue in a known order
+func acquireInBackground(t *testing.T, l *modelLimiter, namespace string, granted chan<- string) {
+	l.mu.Lock()
+	queued := len(l.queues[namespace])
+	l.mu.Unlock()
+
+	go func() {
+		if _, err := l.acquire(context.Background(), namespace, 1); err == nil {
+			granted <- namespace
+		}
+	}()
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.queues[namespace]) > queued
+	}, time.Second, time.Millisecond)
+}
+
+func TestLimiterConcurrency(t *testing.T) {
+	limiter, _ := newTestLimiter(arkv1alpha1.ModelLimits{MaxConcurrentRequests: ptr.To[int32](1)})
+
+	first, err := limiter.acquire(context.Background(), "default", 10)
+	require.NoError(t, err)
+
+	granted := make(chan string, 1)
+	acquireInBackground(t, limiter, "default", granted)
+	require.Empty(t, granted)
+
+	first.release(&openai.ChatCompletion{})
+	require.Equal(t, "default", <-granted)
+}
+
+func TestLimiterRequestsPerMinute(t *testing.T) {
+	limiter, clock := newTestLimiter(arkv1alpha1.ModelLimits{RequestsPerMinute: ptr.To[int32](2)})
+
+	for range 2 {
+		reservation, err := limiter.acquire(context.Background(), "default", 10)
+		require.NoError(t, err)
+		require.Zero(t, reservation.waited)
+		reservation.release(nil)
+	}
+
+	granted := make(chan string, 1)
+	acquireInBackground(t, limiter, "default", granted)
+	clock.advance(limiter, 20*time.Second)
+	require.Empty(t, granted)
+	clock.advance(limiter, 10*time.Second)
+	require.Equal(t, "default", <-granted)
+}
+
+func TestLimiterFairQueuing(t *testing.T) {
+	limiter, _ := newTestLimiter(arkv1alpha1.ModelLimits{MaxConcurrentRequests: ptr.To[int32](1)})
+
+	running, err := limiter.acquire(context.Background(), "batch", 1)
+	require.NoError(t, err)
+
+	granted := make(chan string, 4)
+	acquireInBackground(t, limiter, "batch", granted)
+	acquireInBackground(t, limiter, "batch", granted)
+	acquireInBackground(t, limiter, "batch", granted)
+	acquireInBackground(t, limiter, "interactive", granted)
+
+	var order []string
+	for range 4 {
+		running.release(nil)
+		order = append(order, <-granted)
+		running = &rateLimitReservation{limiter: limiter}
+	}
+	require.Equal(t, []string{"batch", "interactive", "batch", "batch"}, order)
+}
+
+func TestLimiterTokenReconciliation(t *testing.T) {
+	limiter, clock := newTestLimiter(arkv1alpha1.ModelLimits{TokensPerMinute: ptr.To[int64](1000)})
+
+	reservation, err := limiter.acquire(context.Background(), "default", 600)
+	require.NoError(t, err)
+	require.Equal(t, 400.0, limiter.tokens.available)
+
+	reservation.release(&openai.ChatCompletion{Usage: openai.CompletionUsage{TotalTokens: 900}})
+	require.Equal(t, 100.0, limiter.tokens.available)
+
+	// Estimates above the limit are capped so the call can start once the bucket is full
+	reservations := make(chan *rateLimitReservation, 1)
+	go func() {
+		if reservation, err := limiter.acquire(context.Background(), "default", 5000); err == nil {
+			reservations <- reservation
+		}
+	}()
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.namespaces) == 1
+	}, time.Second, time.Millisecond)
+	clock.advance(limiter, time.Minute)
+	require.Equal(t, 1000.0, (<-reservations).tokens)
+}
+
+func TestLimiterCanceledWait(t *testing.T) {
+	limiter, _ := newTestLimiter(arkv1alpha1.ModelLimits{MaxConcurrentRequests: ptr.To[int32](1)})
+
+	running, err := limiter.acquire(context.Background(), "default", 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "default", 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Empty(t, limiter.namespaces)
+
+	running.release(nil)
+	require.Zero(t, limiter.inFlight)
+}
+
+func TestLimiterRegistry(t *testing.T) {
+	registry := &limiterRegistry{limiters: make(map[string]*modelLimiter)}
+
+	limiter := registry.get("default/gpt-4o", &arkv1alpha1.ModelLimits{RequestsPerMinute: ptr.To[int32](60)})
+	require.Same(t, limiter, registry.get("default/gpt-4o", &arkv1alpha1.ModelLimits{RequestsPerMinute: ptr.To[int32](30)}))
+	require.Equal(t, 30.0, limiter.requests.perMinute)
+	require.NotSame(t, limiter, registry.get("other/gpt-4o", &arkv1alpha1.ModelLimits{}))
+
+	registry.remove("default/gpt-4o")
+	require.NotContains(t, registry.limiters, "default/gpt-4o")
+	require.NotSame(t, limiter, registry.get("default/gpt-4o", &arkv1alpha1.ModelLimits{}), "a removed limiter is not reused")
+}
+
+func TestLoadModelLimiter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	model := &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "limited", Namespace: "limits"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:   ModelTypeOpenAI,
+			Model:  arkv1alpha1.ValueSource{Value: "gpt-4o"},
+			Limits: &arkv1alpha1.ModelLimits{RequestsPerMinute: ptr.To[int32](60)},
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: "https://api.openai.com/v1"},
+				APIKey:  arkv1alpha1.ValueSource{Value: "sk-test"},
+			}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model).Build()
+	t.Cleanup(func() { RemoveModelLimiter("limits", "limited") })
+
+	loaded, err := LoadModel(context.Background(), k8sClient, "limited", "limits", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	require.Same(t, loaded.limiter, modelLimiters.limiters["limits/limited"])
+
+	model.Spec.Limits = nil
+	require.NoError(t, k8sClient.Update(context.Background(), model))
+	loaded, err = LoadModel(context.Background(), k8sClient, "limited", "limits", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	require.Nil(t, loaded.limiter)
+	require.NotContains(t, modelLimiters.limiters, "limits/limited", "the limiter is removed with the limits")
+}
+
+func TestEstimateTokens(t *testing.T) {
+	messages := []Message{NewUserMessage("What is the weather in Paris today?")}
+	estimate := estimateTokens(messages, nil, nil)
+	require.Greater(t, estimate, int64(10))
+
+	maxTokens := int64(500)
+	require.Equal(t, estimate+500, estimateTokens(messages, nil, &GenerationParameters{MaxTokens: &maxTokens}))
+}
@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/respjson"
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 )
@@ -41,6 +42,70 @@ type ChunkWithMetadata struct {
 	Ark *StreamMetadata `json:"ark,omitempty"`
 }
 
+// reasoningContentField is the field of chunk deltas and messages that carries reasoning summaries,
+// named as by OpenAI compatible servers such as vLLM. The OpenAI types have no field for it, so it
+// is kept in their extra fields.
+const reasoningContentField = "reasoning_content"
+
+func reasoningContentFields(reasoning string) map[string]respjson.Field {
+	raw, _ := json.Marshal(reasoning)
+	return map[string]respjson.Field{reasoningContentField: respjson.NewField(string(raw))}
+}
+
+// reasoningContent returns the reasoning summary kept in extra fields, or ""
+func reasoningContent(fields map[string]respjson.Field) string {
+	field, ok := fields[reasoningContentField]
+	if !ok {
+		return ""
+	}
+	var reasoning string
+	_ = json.Unmarshal([]byte(field.Raw()), &reasoning)
+	return reasoning
+}
+
+// MarshalJSON adds the reasoning content of deltas, which the encoding of the chunk leaves out
+func (c ChunkWithMetadata) MarshalJSON() ([]byte, error) {
+	type plain ChunkWithMetadata
+	data, err := json.Marshal(plain(c))
+	if err != nil || c.ChatCompletionChunk == nil {
+		return data, err
+	}
+
+	var chunk map[string]json.RawMessage
+	var choices []map[string]json.RawMessage
+	patched := false
+	for i, choice := range c.Choices {
+		reasoning := reasoningContent(choice.Delta.JSON.ExtraFields)
+		if reasoning == "" {
+			continue
+		}
+		if !patched {
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(chunk["choices"], &choices); err != nil {
+				return nil, err
+			}
+			patched = true
+		}
+		var delta map[string]json.RawMessage
+		if err := json.Unmarshal(choices[i]["delta"], &delta); err != nil {
+			return nil, err
+		}
+		delta[reasoningContentField], _ = json.Marshal(reasoning)
+		if choices[i]["delta"], err = json.Marshal(delta); err != nil {
+			return nil, err
+		}
+	}
+	if !patched {
+		return data, nil
+	}
+	if chunk["choices"], err = json.Marshal(choices); err != nil {
+		return nil, err
+	}
+	return json.Marshal(chunk)
+}
+
 func NewContentChunk(id, model, content string) *openai.ChatCompletionChunk {
 	return &openai.ChatCompletionChunk{
 		ID:      id,
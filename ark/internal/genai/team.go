@@ -3,7 +3,10 @@ package genai
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"slices"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,14 +24,37 @@ type Team struct {
 	MaxTurns          *int
 	Selector          *arkv1alpha1.TeamSelectorSpec
 	Graph             *arkv1alpha1.TeamGraphSpec
+	Parallel          *arkv1alpha1.TeamParallelSpec
+	Plan              *arkv1alpha1.TeamPlanSpec
+	Vote              *arkv1alpha1.TeamVoteSpec
+	Context           *arkv1alpha1.TeamContextSpec
+	MemberContexts    map[string]*arkv1alpha1.TeamContextSpec
+	Termination       *arkv1alpha1.TeamTerminationSpec
+	terminationRegex  *regexp.Regexp
 	telemetryRecorder telemetry.TeamRecorder
 	eventingRecorder  eventing.TeamRecorder
 	telemetry         telemetry.Provider
 	eventing          eventing.Provider
 	Client            client.Client
 	Namespace         string
+}
+
+// teamRun is one execution of a team. Strategies keep the progress of the execution here rather
+// than on the Team, so that a team can run again while it is running, nested or concurrently.
+type teamRun struct {
+	*Team
 	memory            MemoryInterface
 	eventStream       EventStreamInterface
+	startedAt         time.Time
+	handoffChain      []string
+	terminationReason string
+	lastResponses     map[string]string
+	planSteps         []*planStepState
+	planRevisions     int
+	voteResult        *arkv1alpha1.VoteResult
+	resumedTurn       int
+	// mu guards the state written by members running in parallel
+	mu sync.Mutex
 }
 
 // FullName returns the namespace/name format for the team
@@ -41,33 +67,53 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 		return nil, fmt.Errorf("team %s has no members configured", t.FullName())
 	}
 
-	// Store memory and streaming parameters for member execution
-	t.memory = memory
-	t.eventStream = eventStream
+	ctx, err := enterCall(ctx, t.Client, CallFrame{Kind: MemberTypeTeam, Namespace: t.Namespace, Name: t.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	run := &teamRun{Team: t, memory: memory, eventStream: eventStream}
 
 	var execFunc func(context.Context, Message, []Message) ([]Message, error)
 	switch t.Strategy {
 	case "sequential":
-		execFunc = t.executeSequential
+		execFunc = run.executeSequential
 	case "round-robin":
-		execFunc = t.executeRoundRobin
+		execFunc = run.executeRoundRobin
 	case "selector":
-		execFunc = t.executeSelector
+		execFunc = run.executeSelector
 	case "graph":
-		execFunc = t.executeGraph
+		execFunc = run.executeGraph
+	case "parallel":
+		execFunc = run.executeParallel
+	case "handoff":
+		execFunc = run.executeHandoff
+	case "plan":
+		execFunc = run.executePlan
+	case "vote":
+		execFunc = run.executeVote
 	default:
 		return nil, fmt.Errorf("unsupported strategy %s for team %s", t.Strategy, t.FullName())
 	}
 
-	messages, err := t.executeWithTracking(execFunc, ctx, userInput, history)
-	return &ExecutionResult{Messages: messages}, err
+	messages, err := run.executeWithTracking(execFunc, ctx, userInput, history)
+	return &ExecutionResult{Messages: messages, TerminationReason: run.terminationReason, Vote: run.voteResult}, err
 }
 
-func (t *Team) executeSequential(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (t *teamRun) executeSequential(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	messages := slices.Clone(history)
 	var newMessages []Message
 
-	for i, member := range t.Members {
+	start := 0
+	if checkpoint := t.resumeCheckpoint(ctx); checkpoint != nil {
+		messages = append(messages, checkpoint.Messages...)
+		newMessages = slices.Clone(checkpoint.Messages)
+		start = checkpoint.Turn
+	}
+
+	for i := start; i < len(t.Members); i++ {
+		member := t.Members[i]
+
 		// Check if context was cancelled
 		if ctx.Err() != nil {
 			return newMessages, ctx.Err()
@@ -103,18 +149,26 @@ func (t *Team) executeSequential(ctx context.Context, userInput Message, history
 		t.telemetryRecorder.RecordSuccess(turnSpan)
 		turnSpan.End()
 		t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", i), operationData)
+		t.saveCheckpoint(ctx, TeamCheckpoint{Turn: i + 1}, newMessages)
 	}
 
 	return newMessages, nil
 }
 
-func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (t *teamRun) executeRoundRobin(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	messages := slices.Clone(history)
 	var newMessages []Message
 
 	messageCount := 0 // Count individual agent messages
 	memberIndex := 0  // Track which agent should speak next
 
+	if checkpoint := t.resumeCheckpoint(ctx); checkpoint != nil {
+		messages = append(messages, checkpoint.Messages...)
+		newMessages = slices.Clone(checkpoint.Messages)
+		messageCount = checkpoint.Turn
+		memberIndex = checkpoint.Turn % len(t.Members)
+	}
+
 	for {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
@@ -164,6 +218,7 @@ func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history
 
 		messageCount++                                   // Increment message count
 		memberIndex = (memberIndex + 1) % len(t.Members) // Move to next agent in round-robin
+		t.saveCheckpoint(ctx, TeamCheckpoint{Turn: messageCount}, newMessages)
 	}
 }
 
@@ -180,11 +235,22 @@ func (t *Team) GetDescription() string {
 }
 
 func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (*Team, error) {
+	// Nested teams are loaded recursively, so a team containing itself must be caught here
+	ctx, err := enterTeamLoad(ctx, CallFrame{Kind: MemberTypeTeam, Namespace: crd.Namespace, Name: crd.Name})
+	if err != nil {
+		return nil, err
+	}
+
 	members, err := loadTeamMembers(ctx, k8sClient, crd, telemetryProvider, eventingProvider)
 	if err != nil {
 		return nil, err
 	}
 
+	terminationRegex, err := compileTerminationRegex(crd.Spec.Termination)
+	if err != nil {
+		return nil, fmt.Errorf("team %s/%s termination: %w", crd.Namespace, crd.Name, err)
+	}
+
 	return &Team{
 		Name:              crd.Name,
 		Members:           members,
@@ -193,6 +259,13 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		MaxTurns:          crd.Spec.MaxTurns,
 		Selector:          crd.Spec.Selector,
 		Graph:             crd.Spec.Graph,
+		Parallel:          crd.Spec.Parallel,
+		Plan:              crd.Spec.Plan,
+		Vote:              crd.Spec.Vote,
+		Context:           crd.Spec.Context,
+		MemberContexts:    memberContexts(crd.Spec.Members),
+		Termination:       crd.Spec.Termination,
+		terminationRegex:  terminationRegex,
 		telemetryRecorder: telemetryProvider.TeamRecorder(),
 		eventingRecorder:  eventingProvider.TeamRecorder(),
 		telemetry:         telemetryProvider,
@@ -202,6 +275,16 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 	}, nil
 }
 
+func memberContexts(members []arkv1alpha1.TeamMember) map[string]*arkv1alpha1.TeamContextSpec {
+	contexts := make(map[string]*arkv1alpha1.TeamContextSpec)
+	for _, member := range members {
+		if member.Context != nil {
+			contexts[member.Name] = member.Context
+		}
+	}
+	return contexts
+}
+
 func loadTeamMembers(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) ([]TeamMember, error) {
 	members := make([]TeamMember, 0, len(crd.Spec.Members))
 
@@ -216,7 +299,7 @@ func loadTeamMembers(ctx context.Context, k8sClient client.Client, crd *arkv1alp
 	return members, nil
 }
 
-func (t *Team) executeWithTracking(execFunc func(context.Context, Message, []Message) ([]Message, error), ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (t *teamRun) executeWithTracking(execFunc func(context.Context, Message, []Message) ([]Message, error), ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	maxTurns := 0
 	if t.MaxTurns != nil {
 		maxTurns = *t.MaxTurns
@@ -233,7 +316,32 @@ func (t *Team) executeWithTracking(execFunc func(context.Context, Message, []Mes
 	}
 	teamctx = t.eventingRecorder.Start(teamctx, "TeamExecution", fmt.Sprintf("Executing team %s", t.FullName()), operationData)
 
+	t.startedAt = time.Now()
 	result, err := execFunc(teamctx, userInput, history)
+	if len(t.handoffChain) > 0 {
+		t.telemetryRecorder.RecordHandoffChain(span, t.handoffChain)
+	}
+	if plan := t.planJSON(); plan != "" {
+		t.telemetryRecorder.RecordPlan(span, plan, t.planRevisions)
+		operationData["plan"] = plan
+	}
+	if candidates := t.voteJSON(); candidates != "" {
+		t.telemetryRecorder.RecordVote(span, t.voteResult.Winner, candidates)
+		operationData["voteWinner"] = t.voteResult.Winner
+		operationData["voteCandidates"] = candidates
+	}
+	if t.resumedTurn > 0 {
+		t.telemetryRecorder.RecordResume(span, t.resumedTurn)
+		operationData["resumedFromTurn"] = fmt.Sprintf("%d", t.resumedTurn)
+	}
+	// An interrupted team keeps its checkpoint, so that it continues when the query is resumed
+	if teamctx.Err() == nil {
+		t.clearCheckpoint(teamctx)
+	}
+	if t.terminationReason != "" {
+		t.telemetryRecorder.RecordTermination(span, t.terminationReason)
+		operationData["terminationReason"] = t.terminationReason
+	}
 	if err != nil {
 		t.telemetryRecorder.RecordError(span, err)
 		t.eventingRecorder.Fail(teamctx, "TeamExecution", fmt.Sprintf("Team execution failed: %v", err), err, operationData)
@@ -253,7 +361,7 @@ func (t *Team) executeWithTracking(execFunc func(context.Context, Message, []Mes
 }
 
 // executeMemberAndAccumulate executes a member and accumulates new messages
-func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember, userInput Message, messages, newMessages *[]Message, turn int) error {
+func (t *teamRun) executeMemberAndAccumulate(ctx context.Context, member TeamMember, userInput Message, messages, newMessages *[]Message, turn int) error {
 	// Add team and current member to execution metadata for streaming
 	ctx = WithExecutionMetadata(ctx, map[string]interface{}{
 		"team":  t.Name,
@@ -269,13 +377,22 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 	}
 	ctx = t.eventingRecorder.Start(ctx, "TeamMember", fmt.Sprintf("Executing member %s in team %s", member.GetName(), t.Name), operationData)
 
-	result, err := member.Execute(ctx, userInput, *messages, t.memory, t.eventStream)
+	history, err := t.scopeHistory(ctx, member, *messages)
 	if err != nil {
+		t.eventingRecorder.Fail(ctx, "TeamMember", fmt.Sprintf("Team member execution failed: %v", err), err, operationData)
+		return err
+	}
+
+	result, err := member.Execute(ctx, userInput, history, t.memory, t.eventStream)
+	if err != nil && !IsHandoffTeam(err) {
 		// Still accumulate messages even on error if result is not nil
 		if result != nil {
 			*messages = append(*messages, result.Messages...)
 			*newMessages = append(*newMessages, result.Messages...)
 		}
+		if IsTerminateTeam(err) {
+			t.setTerminationReason(fmt.Sprintf("%s called terminate", member.GetName()))
+		}
 		t.eventingRecorder.Fail(ctx, "TeamMember", fmt.Sprintf("Team member execution failed: %v", err), err, operationData)
 		return err
 	}
@@ -283,7 +400,12 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 	*messages = append(*messages, result.Messages...)
 	*newMessages = append(*newMessages, result.Messages...)
 	t.eventingRecorder.Complete(ctx, "TeamMember", "Team member execution completed successfully", operationData)
-	return nil
+
+	if reason := t.checkTermination(ctx, member, result.Messages); reason != "" {
+		t.setTerminationReason(reason)
+		return &TerminateTeam{}
+	}
+	return err
 }
 
 func loadTeamMember(ctx context.Context, k8sClient client.Client, memberSpec arkv1alpha1.TeamMember, namespace, teamName string, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (TeamMember, error) {
@@ -0,0 +1,114 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go/packages/param"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	TeamContextModeFull         = "full"
+	TeamContextModeLastMessages = "lastMessages"
+	TeamContextModeFinalOutputs = "finalOutputs"
+	TeamContextModeSummary      = "summary"
+)
+
+const teamContextSummaryPrompt = `Summarize the conversation below for a team member who continues the work.
+Keep the user's requests, the decisions made and the results so far. Be concise.`
+
+// memberContext returns the context configuration for a member, falling back to the team default
+func (t *Team) memberContext(member TeamMember) *arkv1alpha1.TeamContextSpec {
+	if spec, ok := t.MemberContexts[member.GetName()]; ok && spec != nil {
+		return spec
+	}
+	return t.Context
+}
+
+// scopeHistory returns the part of the team conversation a member receives as history
+func (t *Team) scopeHistory(ctx context.Context, member TeamMember, messages []Message) ([]Message, error) {
+	spec := t.memberContext(member)
+	if spec == nil {
+		return messages, nil
+	}
+
+	switch spec.Mode {
+	case "", TeamContextModeFull:
+		return messages, nil
+	case TeamContextModeLastMessages:
+		return lastMessages(messages, spec.LastMessages), nil
+	case TeamContextModeFinalOutputs:
+		return finalOutputs(messages), nil
+	case TeamContextModeSummary:
+		return t.summarizeHistory(ctx, spec, messages)
+	default:
+		return nil, fmt.Errorf("unsupported context mode %s for member %s in team %s", spec.Mode, member.GetName(), t.FullName())
+	}
+}
+
+// lastMessages keeps the n most recent messages. Tool results whose tool call was cut off are dropped,
+// as models reject them.
+func lastMessages(messages []Message, n int) []Message {
+	if n <= 0 || len(messages) <= n {
+		return messages
+	}
+
+	recent := messages[len(messages)-n:]
+	for len(recent) > 0 && recent[0].OfTool != nil {
+		recent = recent[1:]
+	}
+	return recent
+}
+
+// finalOutputs removes tool traffic, keeping the text of assistant messages that also called tools
+func finalOutputs(messages []Message) []Message {
+	outputs := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		switch {
+		case msg.OfTool != nil:
+			continue
+		case msg.OfAssistant != nil && len(msg.OfAssistant.ToolCalls) > 0:
+			content := msg.OfAssistant.Content.OfString.Value
+			if content == "" {
+				continue
+			}
+			output := NewAssistantMessage(content)
+			output.OfAssistant.Name = msg.OfAssistant.Name
+			outputs = append(outputs, output)
+		default:
+			outputs = append(outputs, msg)
+		}
+	}
+	return outputs
+}
+
+// summarizeHistory replaces the conversation with a summary written by the configured model
+func (t *Team) summarizeHistory(ctx context.Context, spec *arkv1alpha1.TeamContextSpec, messages []Message) ([]Message, error) {
+	transcript := buildHistory(messages)
+	if transcript == "" {
+		return nil, nil
+	}
+
+	var modelSpec any = ""
+	if spec.ModelRef != nil {
+		modelSpec = spec.ModelRef
+	}
+	model, err := LoadModel(ctx, t.Client, modelSpec, t.Namespace, nil, t.telemetry.ModelRecorder(), t.eventing.ModelRecorder())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load summary model for team %s: %w", t.FullName(), err)
+	}
+
+	response, err := model.ChatCompletion(ctx, []Message{NewSystemMessage(teamContextSummaryPrompt), NewUserMessage(transcript)}, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize conversation for team %s: %w", t.FullName(), err)
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return nil, fmt.Errorf("summary model returned no response for team %s", t.FullName())
+	}
+
+	summary := NewAssistantMessage(fmt.Sprintf("Summary of the conversation so far:\n%s", response.Choices[0].Message.Content))
+	summary.OfAssistant.Name = param.NewOpt(t.Name)
+	return []Message{summary}, nil
+}
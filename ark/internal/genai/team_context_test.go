@@ -0,0 +1,80 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func toolCallingMessage(content string) Message {
+	msg := NewAssistantMessage(content)
+	msg.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{{
+		ID:       "call-1",
+		Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "search", Arguments: "{}"},
+	}}
+	return msg
+}
+
+func TestLastMessages(t *testing.T) {
+	messages := []Message{
+		NewUserMessage("question"),
+		toolCallingMessage(""),
+		ToolMessage("result", "call-1"),
+		NewAssistantMessage("answer"),
+	}
+
+	require.Len(t, lastMessages(messages, 0), 4)
+	require.Len(t, lastMessages(messages, 10), 4)
+	require.Equal(t, []Message{messages[3]}, lastMessages(messages, 1))
+	require.Equal(t, []Message{messages[3]}, lastMessages(messages, 2), "tool result without its tool call is dropped")
+}
+
+func TestFinalOutputs(t *testing.T) {
+	messages := []Message{
+		NewUserMessage("question"),
+		toolCallingMessage(""),
+		ToolMessage("result", "call-1"),
+		toolCallingMessage("checking one more source"),
+		ToolMessage("result", "call-1"),
+		NewAssistantMessage("answer"),
+	}
+
+	outputs := finalOutputs(messages)
+	require.Len(t, outputs, 3)
+	require.Equal(t, "checking one more source", outputs[1].OfAssistant.Content.OfString.Value)
+	require.Empty(t, outputs[1].OfAssistant.ToolCalls)
+	require.Equal(t, "answer", ExtractLastAssistantMessageContent(outputs))
+}
+
+func TestTeamMemberContext(t *testing.T) {
+	history := []Message{
+		NewUserMessage("earlier question"),
+		toolCallingMessage(""),
+		ToolMessage("result", "call-1"),
+		NewAssistantMessage("earlier answer"),
+	}
+
+	researcher := &respondingTeamMember{name: "researcher", response: "findings"}
+	writer := &respondingTeamMember{name: "writer", response: "report"}
+	team := &Team{
+		Name:              "research",
+		Namespace:         "default",
+		Strategy:          "sequential",
+		Members:           []TeamMember{researcher, writer},
+		Context:           &arkv1alpha1.TeamContextSpec{Mode: TeamContextModeFinalOutputs},
+		MemberContexts:    map[string]*arkv1alpha1.TeamContextSpec{"writer": {Mode: TeamContextModeLastMessages, LastMessages: 1}},
+		telemetryRecorder: noop.NewProvider().TeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+
+	_, err := team.Execute(context.Background(), NewUserMessage("write a report"), history, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, researcher.historySize, "team default strips tool traffic")
+	require.Equal(t, 1, writer.historySize, "member context overrides the team default")
+}
@@ -2,10 +2,16 @@ package genai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
-func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (t *teamRun) executeGraph(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	if len(t.Members) == 0 {
 		return nil, fmt.Errorf("team %s has no members for graph execution", t.FullName())
 	}
@@ -18,16 +24,18 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		memberMap[member.GetName()] = member
 	}
 
-	transitionMap := make(map[string]string)
-	if t.Graph != nil {
-		for _, edge := range t.Graph.Edges {
-			transitionMap[edge.From] = edge.To
-		}
-	}
+	transitionMap := graphTransitions(t.Graph)
 
 	currentMemberName := t.Members[0].GetName()
+	start := 0
+	if checkpoint := t.resumeCheckpoint(ctx); checkpoint != nil {
+		messages = append(messages, checkpoint.Messages...)
+		newMessages = slices.Clone(checkpoint.Messages)
+		currentMemberName = checkpoint.Member
+		start = checkpoint.Turn
+	}
 
-	for turns := 0; ; turns++ {
+	for turns := start; ; turns++ {
 		member, exists := memberMap[currentMemberName]
 		if !exists {
 			return newMessages, fmt.Errorf("member %s not found in team %s", currentMemberName, t.FullName())
@@ -64,12 +72,16 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		turnSpan.End()
 		t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", turns), operationData)
 
-		nextMember := transitionMap[currentMemberName]
+		nextMember, err := selectGraphEdge(transitionMap[currentMemberName], ExtractLastAssistantMessageContent(messages))
+		if err != nil {
+			return newMessages, fmt.Errorf("team %s: %w", t.FullName(), err)
+		}
 		if nextMember == "" {
 			break
 		}
 
 		currentMemberName = nextMember
+		t.saveCheckpoint(ctx, TeamCheckpoint{Turn: turns + 1, Member: currentMemberName}, newMessages)
 
 		if t.MaxTurns != nil && turns+1 >= *t.MaxTurns {
 			return newMessages, nil
@@ -78,3 +90,66 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 
 	return newMessages, nil
 }
+
+// graphTransitions groups edges by source member, ordered by descending priority with default
+// edges last. Edges with equal priority keep their declaration order.
+func graphTransitions(graph *arkv1alpha1.TeamGraphSpec) map[string][]arkv1alpha1.TeamGraphEdge {
+	transitions := make(map[string][]arkv1alpha1.TeamGraphEdge)
+	if graph == nil {
+		return transitions
+	}
+	for _, edge := range graph.Edges {
+		transitions[edge.From] = append(transitions[edge.From], edge)
+	}
+	for _, edges := range transitions {
+		slices.SortStableFunc(edges, func(a, b arkv1alpha1.TeamGraphEdge) int {
+			if a.Default != b.Default {
+				if a.Default {
+					return 1
+				}
+				return -1
+			}
+			return b.Priority - a.Priority
+		})
+	}
+	return transitions
+}
+
+// selectGraphEdge returns the target of the first edge whose condition matches the content, or
+// an empty string when none does
+func selectGraphEdge(edges []arkv1alpha1.TeamGraphEdge, content string) (string, error) {
+	for _, edge := range edges {
+		matched, err := evaluateGraphEdgeCondition(edge.Condition, content)
+		if err != nil {
+			return "", fmt.Errorf("failed to evaluate condition of graph edge %s -> %s: %w", edge.From, edge.To, err)
+		}
+		if matched {
+			return edge.To, nil
+		}
+	}
+	return "", nil
+}
+
+// evaluateGraphEdgeCondition reports whether message content satisfies an edge condition. A nil
+// condition always matches. jq conditions do not match content that is not JSON.
+func evaluateGraphEdgeCondition(condition *arkv1alpha1.TeamGraphEdgeCondition, content string) (bool, error) {
+	switch {
+	case condition == nil:
+		return true, nil
+	case condition.Regex != "":
+		re, err := regexp.Compile(condition.Regex)
+		if err != nil {
+			return false, fmt.Errorf("invalid regex '%s': %w", condition.Regex, err)
+		}
+		return re.MatchString(content), nil
+	case condition.JQ != "":
+		if !json.Valid([]byte(content)) {
+			return false, nil
+		}
+		return evaluateJQCondition(condition.JQ, content)
+	case condition.Keyword != "":
+		return strings.Contains(strings.ToLower(content), strings.ToLower(condition.Keyword)), nil
+	default:
+		return true, nil
+	}
+}
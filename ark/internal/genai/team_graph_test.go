@@ -0,0 +1,72 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func newGraphTestTeam(edges []arkv1alpha1.TeamGraphEdge, members ...TeamMember) *Team {
+	maxTurns := 5
+	return &Team{
+		Name:              "review",
+		Namespace:         "default",
+		Strategy:          "graph",
+		Members:           members,
+		MaxTurns:          &maxTurns,
+		Graph:             &arkv1alpha1.TeamGraphSpec{Edges: edges},
+		telemetryRecorder: noop.NewProvider().TeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+}
+
+func TestExecuteGraphConditionalEdges(t *testing.T) {
+	edges := []arkv1alpha1.TeamGraphEdge{
+		{From: "triage", To: "fallback", Default: true},
+		{From: "triage", To: "billing", Condition: &arkv1alpha1.TeamGraphEdgeCondition{JQ: `.topic == "billing"`}},
+		{From: "triage", To: "urgent", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Keyword: "URGENT"}, Priority: 10},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{name: "routes on jq condition", response: `{"topic": "billing"}`, want: "billing"},
+		{name: "prefers higher priority edge", response: `{"topic": "billing", "note": "urgent"}`, want: "urgent"},
+		{name: "falls back to default edge", response: "no idea", want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triage := &respondingTeamMember{name: "triage", response: tt.response}
+			billing := &respondingTeamMember{name: "billing", response: "billing"}
+			urgent := &respondingTeamMember{name: "urgent", response: "urgent"}
+			fallback := &respondingTeamMember{name: "fallback", response: "fallback"}
+			team := newGraphTestTeam(edges, triage, billing, urgent, fallback)
+
+			result, err := team.Execute(context.Background(), NewUserMessage("help"), nil, nil, nil)
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 2)
+			require.Equal(t, tt.want, ExtractLastAssistantMessageContent(result.Messages))
+		})
+	}
+
+	t.Run("stops when no edge matches", func(t *testing.T) {
+		triage := &respondingTeamMember{name: "triage", response: "all good"}
+		billing := &respondingTeamMember{name: "billing", response: "billing"}
+		team := newGraphTestTeam([]arkv1alpha1.TeamGraphEdge{
+			{From: "triage", To: "billing", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Regex: "^invoice"}},
+		}, triage, billing)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("help"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 1)
+		require.Equal(t, "all good", ExtractLastAssistantMessageContent(result.Messages))
+	})
+}
@@ -0,0 +1,119 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// executeHandoff starts with the first member and lets each agent hand control to another member
+// by calling one of its transfer tools. The team finishes when a member responds without handing off.
+func (t *teamRun) executeHandoff(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	messages := slices.Clone(history)
+	var newMessages []Message
+
+	memberMap := make(map[string]TeamMember)
+	for _, member := range t.Members {
+		memberMap[member.GetName()] = member
+	}
+	t.registerHandoffTools()
+
+	member := t.Members[0]
+	t.handoffChain = []string{member.GetName()}
+	start := 0
+	if checkpoint := t.resumeCheckpoint(ctx); checkpoint != nil {
+		messages = append(messages, checkpoint.Messages...)
+		newMessages = slices.Clone(checkpoint.Messages)
+		member = memberMap[checkpoint.Member]
+		t.handoffChain = checkpoint.HandoffChain
+		start = checkpoint.Turn
+	}
+
+	for turn := start; ; turn++ {
+		if ctx.Err() != nil {
+			return newMessages, ctx.Err()
+		}
+
+		turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, member.GetName(), member.GetType())
+
+		operationData := map[string]string{
+			"teamName": t.Name,
+			"strategy": t.Strategy,
+			"turn":     fmt.Sprintf("%d", turn),
+		}
+		turnCtx = t.eventingRecorder.Start(turnCtx, "TeamTurn", fmt.Sprintf("Executing turn %d for team %s", turn, t.Name), operationData)
+
+		err := t.executeMemberAndAccumulate(turnCtx, member, userInput, &messages, &newMessages, turn)
+
+		if len(newMessages) > 0 {
+			t.telemetryRecorder.RecordTurnOutput(turnSpan, newMessages, len(newMessages))
+		}
+
+		var handoff *HandoffTeam
+		if err != nil && !errors.As(err, &handoff) {
+			t.telemetryRecorder.RecordError(turnSpan, err)
+			turnSpan.End()
+			t.eventingRecorder.Fail(turnCtx, "TeamTurn", fmt.Sprintf("Team turn failed: %v", err), err, operationData)
+			if IsTerminateTeam(err) {
+				return newMessages, nil
+			}
+			return newMessages, fmt.Errorf("member %s failed in team %s: %w", member.GetName(), t.FullName(), err)
+		}
+
+		if handoff != nil {
+			operationData["handoffTo"] = handoff.Target
+		}
+		t.telemetryRecorder.RecordSuccess(turnSpan)
+		turnSpan.End()
+		t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", turn), operationData)
+
+		if handoff == nil {
+			return newMessages, nil
+		}
+
+		if t.MaxTurns != nil && turn+1 >= *t.MaxTurns {
+			return newMessages, nil
+		}
+
+		next, exists := memberMap[handoff.Target]
+		if !exists {
+			return newMessages, fmt.Errorf("member %s handed off to unknown member %s in team %s", member.GetName(), handoff.Target, t.FullName())
+		}
+		member = next
+		t.handoffChain = append(t.handoffChain, member.GetName())
+		t.saveCheckpoint(ctx, TeamCheckpoint{Turn: turn + 1, Member: member.GetName(), HandoffChain: t.handoffChain}, newMessages)
+	}
+}
+
+// registerHandoffTools gives every agent member a transfer tool for each member it may hand off to.
+// Graph edges, when configured, restrict the transfers; otherwise any other member is allowed.
+func (t *Team) registerHandoffTools() {
+	for _, member := range t.Members {
+		agent, ok := member.(*Agent)
+		if !ok || agent.Tools == nil {
+			continue
+		}
+		for _, target := range t.handoffTargets(member.GetName()) {
+			agent.Tools.RegisterTool(GetHandoffTool(target), &HandoffExecutor{Target: target.GetName()})
+		}
+	}
+}
+
+func (t *Team) handoffTargets(from string) []TeamMember {
+	var targets []TeamMember
+	for _, member := range t.Members {
+		if member.GetName() == from {
+			continue
+		}
+		if t.Graph != nil && !slices.ContainsFunc(t.Graph.Edges, func(edge arkv1alpha1.TeamGraphEdge) bool {
+			return edge.From == from && edge.To == member.GetName()
+		}) {
+			continue
+		}
+		targets = append(targets, member)
+	}
+	return targets
+}
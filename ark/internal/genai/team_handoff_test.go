@@ -0,0 +1,106 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/mock"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// handoffTeamMember replies and then hands off to target, when set
+type handoffTeamMember struct {
+	name   string
+	target string
+	calls  int
+}
+
+func (m *handoffTeamMember) GetName() string        { return m.name }
+func (m *handoffTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *handoffTeamMember) GetDescription() string { return "" }
+
+func (m *handoffTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	m.calls++
+	result := &ExecutionResult{Messages: []Message{NewAssistantMessage(m.name + " reply")}}
+	if m.target == "" {
+		return result, nil
+	}
+	return result, &HandoffTeam{Target: m.target}
+}
+
+func newHandoffTestTeam(maxTurns int, members ...TeamMember) *Team {
+	return &Team{
+		Name:              "support",
+		Namespace:         "default",
+		Strategy:          "handoff",
+		Members:           members,
+		MaxTurns:          &maxTurns,
+		telemetryRecorder: noop.NewProvider().TeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+}
+
+func TestExecuteHandoff(t *testing.T) {
+	t.Run("follows handoffs until a member responds", func(t *testing.T) {
+		triage := &handoffTeamMember{name: "triage", target: "billing"}
+		billing := &handoffTeamMember{name: "billing"}
+		refunds := &handoffTeamMember{name: "refunds"}
+		team := newHandoffTestTeam(5, triage, billing, refunds)
+		recorder := mock.NewTeamRecorder()
+		team.telemetryRecorder = recorder
+
+		result, err := team.Execute(context.Background(), NewUserMessage("refund please"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 2)
+		require.Equal(t, "billing reply", ExtractLastAssistantMessageContent(result.Messages))
+		require.Zero(t, refunds.calls)
+		require.Equal(t, "triage -> billing", recorder.Tracer.FindSpan("team.execution").Attributes["team.handoff_chain"])
+	})
+
+	t.Run("stops at max turns", func(t *testing.T) {
+		first := &handoffTeamMember{name: "first", target: "second"}
+		second := &handoffTeamMember{name: "second", target: "first"}
+		team := newHandoffTestTeam(3, first, second)
+		recorder := mock.NewTeamRecorder()
+		team.telemetryRecorder = recorder
+
+		result, err := team.Execute(context.Background(), NewUserMessage("loop"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3)
+		require.Equal(t, "first -> second -> first", recorder.Tracer.FindSpan("team.execution").Attributes["team.handoff_chain"])
+	})
+
+	t.Run("registers transfer tools allowed by graph edges", func(t *testing.T) {
+		triage := &Agent{Name: "triage", Tools: NewToolRegistry(nil, noop.NewToolRecorder(), eventnoop.NewProvider().ToolRecorder())}
+		billing := &Agent{Name: "billing", Description: "Handles invoices", Tools: NewToolRegistry(nil, noop.NewToolRecorder(), eventnoop.NewProvider().ToolRecorder())}
+		refunds := &handoffTeamMember{name: "refunds.v2"}
+		team := newHandoffTestTeam(5, triage, billing, refunds)
+		team.Graph = &arkv1alpha1.TeamGraphSpec{Edges: []arkv1alpha1.TeamGraphEdge{
+			{From: "triage", To: "billing"},
+			{From: "billing", To: "refunds.v2"},
+		}}
+
+		team.registerHandoffTools()
+
+		require.ElementsMatch(t, []string{"transfer_to_billing"}, toolNames(triage.Tools))
+		require.ElementsMatch(t, []string{"transfer_to_refunds_v2"}, toolNames(billing.Tools))
+
+		_, err := triage.Tools.ExecuteTool(context.Background(), ToolCall{ID: "1", Type: "function", Function: openai.ChatCompletionMessageToolCallFunction{Name: "transfer_to_billing", Arguments: "{}"}})
+		var handoff *HandoffTeam
+		require.ErrorAs(t, err, &handoff)
+		require.Equal(t, "billing", handoff.Target)
+	})
+}
+
+func toolNames(registry *ToolRegistry) []string {
+	var names []string
+	for _, def := range registry.GetToolDefinitions() {
+		names = append(names, def.Name)
+	}
+	return names
+}
@@ -0,0 +1,275 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/packages/param"
+	"k8s.io/apimachinery/pkg/types"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	ParallelFailurePolicyFail     = "fail"
+	ParallelFailurePolicyContinue = "continue"
+)
+
+const aggregatorInstruction = "Combine the responses from the team members above into a single, complete answer to the original request."
+
+type parallelMemberResult struct {
+	member   TeamMember
+	messages []Message
+	err      error
+}
+
+func (t *teamRun) executeParallel(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	members, err := t.parallelMembers()
+	if err != nil {
+		return nil, err
+	}
+	t.eventStream = synchronizeEventStream(t.eventStream)
+
+	failurePolicy := ParallelFailurePolicyFail
+	if t.Parallel != nil && t.Parallel.FailurePolicy != "" {
+		failurePolicy = t.Parallel.FailurePolicy
+	}
+
+	parallelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]parallelMemberResult, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(turn int, member TeamMember) {
+			defer wg.Done()
+			results[turn] = t.executeParallelTurn(parallelCtx, member, userInput, history, turn)
+			if results[turn].err != nil && failurePolicy == ParallelFailurePolicyFail {
+				cancel()
+			}
+		}(i, member)
+	}
+	wg.Wait()
+
+	var newMessages []Message
+	var succeeded []parallelMemberResult
+	var failures []error
+	for _, result := range results {
+		newMessages = append(newMessages, result.messages...)
+		if result.err != nil {
+			failures = append(failures, fmt.Errorf("member %s: %w", result.member.GetName(), result.err))
+			continue
+		}
+		succeeded = append(succeeded, result)
+	}
+
+	if ctx.Err() != nil {
+		return newMessages, ctx.Err()
+	}
+	if len(failures) > 0 && (failurePolicy == ParallelFailurePolicyFail || len(succeeded) == 0) {
+		return newMessages, fmt.Errorf("parallel execution failed in team %s: %w", t.FullName(), errors.Join(failures...))
+	}
+
+	if t.Parallel != nil && t.Parallel.Aggregator != "" {
+		aggregatorMessages, err := t.aggregateParallelResults(ctx, userInput, history, succeeded, len(members))
+		newMessages = append(newMessages, aggregatorMessages...)
+		return newMessages, err
+	}
+
+	return append(newMessages, t.concatenateParallelResults(succeeded)), nil
+}
+
+// parallelMembers returns the members selected for parallel execution, in team order
+func (t *Team) parallelMembers() ([]TeamMember, error) {
+	if t.Parallel == nil {
+		return t.Members, nil
+	}
+
+	members, err := t.namedMembers(t.Parallel.Members)
+	if err != nil {
+		return nil, fmt.Errorf("parallel members of team %s: %w", t.FullName(), err)
+	}
+	return members, nil
+}
+
+// namedMembers returns the members with the given names in team order, or all members when no names
+// are given. Every name must match exactly one member.
+func (t *Team) namedMembers(names []string) ([]TeamMember, error) {
+	if len(names) == 0 {
+		return t.Members, nil
+	}
+
+	members := make([]TeamMember, 0, len(names))
+	for i, name := range names {
+		if slices.Contains(names[:i], name) {
+			return nil, fmt.Errorf("member %s is listed more than once", name)
+		}
+		matches := 0
+		for _, member := range t.Members {
+			if member.GetName() == name {
+				matches++
+			}
+		}
+		switch matches {
+		case 0:
+			return nil, fmt.Errorf("%s is not a member of the team", name)
+		case 1:
+		default:
+			return nil, fmt.Errorf("%s names %d members of the team", name, matches)
+		}
+	}
+	for _, member := range t.Members {
+		if slices.Contains(names, member.GetName()) {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+// executeParallelTurn runs one member on an isolated copy of the history
+func (t *teamRun) executeParallelTurn(ctx context.Context, member TeamMember, userInput Message, history []Message, turn int) parallelMemberResult {
+	messages := slices.Clone(history)
+	var newMessages []Message
+
+	turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, member.GetName(), member.GetType())
+	defer turnSpan.End()
+
+	operationData := map[string]string{
+		"teamName":   t.Name,
+		"strategy":   t.Strategy,
+		"turn":       fmt.Sprintf("%d", turn),
+		"memberName": member.GetName(),
+	}
+	turnCtx = t.eventingRecorder.Start(turnCtx, "TeamTurn", fmt.Sprintf("Executing turn %d for team %s", turn, t.Name), operationData)
+
+	err := t.executeMemberAndAccumulate(turnCtx, member, userInput, &messages, &newMessages, turn)
+
+	if len(newMessages) > 0 {
+		t.telemetryRecorder.RecordTurnOutput(turnSpan, newMessages, len(newMessages))
+	}
+
+	// A member terminating the team only ends its own branch. The termination reason is kept on the
+	// run, and the other members finish so that their responses can still be combined.
+	if err != nil && !IsTerminateTeam(err) {
+		t.telemetryRecorder.RecordError(turnSpan, err)
+		t.eventingRecorder.Fail(turnCtx, "TeamTurn", fmt.Sprintf("Team turn failed: %v", err), err, operationData)
+		return parallelMemberResult{member: member, messages: newMessages, err: err}
+	}
+
+	t.telemetryRecorder.RecordSuccess(turnSpan)
+	t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", turn), operationData)
+	return parallelMemberResult{member: member, messages: newMessages}
+}
+
+// concatenateParallelResults merges member outputs into one assistant message, attributed by member name
+func (t *Team) concatenateParallelResults(results []parallelMemberResult) Message {
+	sections := make([]string, 0, len(results))
+	for _, result := range results {
+		sections = append(sections, fmt.Sprintf("## %s\n%s", result.member.GetName(), ExtractLastAssistantMessageContent(result.messages)))
+	}
+
+	merged := NewAssistantMessage(strings.Join(sections, "\n\n"))
+	merged.OfAssistant.Name = param.NewOpt(t.Name)
+	return merged
+}
+
+// aggregateParallelResults asks the aggregator agent to combine member outputs, which it receives
+// as assistant messages following the original input
+func (t *teamRun) aggregateParallelResults(ctx context.Context, userInput Message, history []Message, results []parallelMemberResult, turn int) ([]Message, error) {
+	aggregator, err := t.loadAggregatorAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := slices.Clone(history)
+	messages = append(messages, userInput)
+	for _, result := range results {
+		output := NewAssistantMessage(ExtractLastAssistantMessageContent(result.messages))
+		output.OfAssistant.Name = param.NewOpt(result.member.GetName())
+		messages = append(messages, output)
+	}
+
+	turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, aggregator.GetName(), aggregator.GetType())
+	defer turnSpan.End()
+
+	operationData := map[string]string{
+		"teamName":   t.Name,
+		"strategy":   t.Strategy,
+		"turn":       fmt.Sprintf("%d", turn),
+		"memberName": aggregator.GetName(),
+	}
+	turnCtx = t.eventingRecorder.Start(turnCtx, "TeamTurn", fmt.Sprintf("Aggregating results for team %s", t.Name), operationData)
+
+	var newMessages []Message
+	err = t.executeMemberAndAccumulate(turnCtx, aggregator, NewUserMessage(aggregatorInstruction), &messages, &newMessages, turn)
+
+	if len(newMessages) > 0 {
+		t.telemetryRecorder.RecordTurnOutput(turnSpan, newMessages, len(newMessages))
+	}
+
+	if err != nil && !IsTerminateTeam(err) {
+		t.telemetryRecorder.RecordError(turnSpan, err)
+		t.eventingRecorder.Fail(turnCtx, "TeamTurn", fmt.Sprintf("Team turn failed: %v", err), err, operationData)
+		return newMessages, fmt.Errorf("aggregator %s failed in team %s: %w", aggregator.GetName(), t.FullName(), err)
+	}
+
+	t.telemetryRecorder.RecordSuccess(turnSpan)
+	t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", turn), operationData)
+	return newMessages, nil
+}
+
+func (t *Team) loadAggregatorAgent(ctx context.Context) (TeamMember, error) {
+	agentName := t.Parallel.Aggregator
+
+	var agentCRD arkv1alpha1.Agent
+	key := types.NamespacedName{Name: agentName, Namespace: t.Namespace}
+	if err := t.Client.Get(ctx, key, &agentCRD); err != nil {
+		return nil, fmt.Errorf("failed to get aggregator agent %s in namespace %s: %w", agentName, t.Namespace, err)
+	}
+
+	agent, err := MakeAgent(ctx, t.Client, &agentCRD, t.telemetry, t.eventing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator agent: %w", err)
+	}
+	return agent, nil
+}
+
+// synchronizedEventStream passes on the chunks of members running in parallel one at a time, as
+// event streams are not safe for concurrent use
+type synchronizedEventStream struct {
+	mu     sync.Mutex
+	stream EventStreamInterface
+}
+
+func synchronizeEventStream(stream EventStreamInterface) EventStreamInterface {
+	if stream == nil {
+		return nil
+	}
+	if _, ok := stream.(*synchronizedEventStream); ok {
+		return stream
+	}
+	return &synchronizedEventStream{stream: stream}
+}
+
+func (s *synchronizedEventStream) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.StreamChunk(ctx, chunk)
+}
+
+func (s *synchronizedEventStream) NotifyCompletion(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.NotifyCompletion(ctx)
+}
+
+func (s *synchronizedEventStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.Close()
+}
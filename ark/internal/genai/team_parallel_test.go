@@ -0,0 +1,139 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// respondingTeamMember replies with a fixed response and records the history it received
+type respondingTeamMember struct {
+	name        string
+	response    string
+	err         error
+	historySize int
+}
+
+func (m *respondingTeamMember) GetName() string        { return m.name }
+func (m *respondingTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *respondingTeamMember) GetDescription() string { return "" }
+
+func (m *respondingTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	m.historySize = len(history)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ExecutionResult{Messages: []Message{NewAssistantMessage(m.response)}}, nil
+}
+
+// streamingTeamMember streams its response in chunks before replying
+type streamingTeamMember struct {
+	name   string
+	chunks []string
+}
+
+func (m *streamingTeamMember) GetName() string        { return m.name }
+func (m *streamingTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *streamingTeamMember) GetDescription() string { return "" }
+
+func (m *streamingTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	for _, chunk := range m.chunks {
+		if err := eventStream.StreamChunk(ctx, chunk); err != nil {
+			return nil, err
+		}
+	}
+	return &ExecutionResult{Messages: []Message{NewAssistantMessage(strings.Join(m.chunks, ""))}}, nil
+}
+
+func newParallelTestTeam(parallel *arkv1alpha1.TeamParallelSpec, members ...TeamMember) *Team {
+	return &Team{
+		Name:              "research",
+		Namespace:         "default",
+		Strategy:          "parallel",
+		Members:           members,
+		Parallel:          parallel,
+		telemetryRecorder: noop.NewProvider().TeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+}
+
+func TestExecuteParallel(t *testing.T) {
+	history := []Message{NewUserMessage("earlier question"), NewAssistantMessage("earlier answer")}
+
+	t.Run("concatenates member outputs with attribution", func(t *testing.T) {
+		web := &respondingTeamMember{name: "web", response: "web findings"}
+		docs := &respondingTeamMember{name: "docs", response: "docs findings"}
+		team := newParallelTestTeam(nil, web, docs)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("research"), history, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3)
+		require.Equal(t, "## web\nweb findings\n\n## docs\ndocs findings", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, len(history), web.historySize)
+		require.Equal(t, len(history), docs.historySize)
+	})
+
+	t.Run("runs only the selected members", func(t *testing.T) {
+		web := &respondingTeamMember{name: "web", response: "web findings"}
+		docs := &respondingTeamMember{name: "docs", response: "docs findings"}
+		team := newParallelTestTeam(&arkv1alpha1.TeamParallelSpec{Members: []string{"docs"}}, web, docs)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "## docs\ndocs findings", ExtractLastAssistantMessageContent(result.Messages))
+	})
+
+	t.Run("fails when a member fails by default", func(t *testing.T) {
+		web := &respondingTeamMember{name: "web", response: "web findings"}
+		docs := &respondingTeamMember{name: "docs", err: errors.New("model unavailable")}
+		team := newParallelTestTeam(nil, web, docs)
+
+		_, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, nil)
+		require.ErrorContains(t, err, "member docs: model unavailable")
+	})
+
+	t.Run("continues with successful members", func(t *testing.T) {
+		web := &respondingTeamMember{name: "web", response: "web findings"}
+		docs := &respondingTeamMember{name: "docs", err: errors.New("model unavailable")}
+		team := newParallelTestTeam(&arkv1alpha1.TeamParallelSpec{FailurePolicy: ParallelFailurePolicyContinue}, web, docs)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "## web\nweb findings", ExtractLastAssistantMessageContent(result.Messages))
+	})
+
+	t.Run("fails when a member is listed twice", func(t *testing.T) {
+		web := &respondingTeamMember{name: "web", response: "web findings"}
+		docs := &respondingTeamMember{name: "docs", response: "docs findings"}
+		team := newParallelTestTeam(&arkv1alpha1.TeamParallelSpec{Members: []string{"docs", "docs"}}, web, docs)
+
+		_, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, nil)
+		require.ErrorContains(t, err, "member docs is listed more than once")
+	})
+
+	t.Run("passes on the chunks of every member to the stream", func(t *testing.T) {
+		web := &streamingTeamMember{name: "web", chunks: []string{"web ", "findings"}}
+		docs := &streamingTeamMember{name: "docs", chunks: []string{"docs ", "findings"}}
+		team := newParallelTestTeam(nil, web, docs)
+
+		stream := &collectingEventStream{}
+		_, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, stream)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []interface{}{"web ", "findings", "docs ", "findings"}, stream.chunks)
+	})
+
+	t.Run("fails when every member fails", func(t *testing.T) {
+		docs := &respondingTeamMember{name: "docs", err: errors.New("model unavailable")}
+		team := newParallelTestTeam(&arkv1alpha1.TeamParallelSpec{FailurePolicy: ParallelFailurePolicyContinue}, docs)
+
+		_, err := team.Execute(context.Background(), NewUserMessage("research"), nil, nil, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,566 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/packages/param"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	PlanStepPending   = "pending"
+	PlanStepCompleted = "completed"
+	PlanStepFailed    = "failed"
+	PlanStepCancelled = "cancelled"
+)
+
+// planMaxAttempts is how many times the planner is asked for a valid plan before the team fails
+const planMaxAttempts = 3
+
+// TeamPlan is the structured response expected from the planner
+type TeamPlan struct {
+	Steps []TeamPlanStep `json:"steps"`
+}
+
+// TeamPlanStep is a task assigned to a member. It runs once the steps it depends on have completed
+// and receives their results.
+type TeamPlanStep struct {
+	ID        string   `json:"id"`
+	Member    string   `json:"member"`
+	Task      string   `json:"task"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// planStepState is a step of a plan revision and how it ran
+type planStepState struct {
+	TeamPlanStep
+	Revision int    `json:"revision"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	output   string
+}
+
+type planStepResult struct {
+	step     *planStepState
+	messages []Message
+	err      error
+}
+
+func (t *teamRun) executePlan(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	planner, workers, err := t.planMembers()
+	if err != nil {
+		return nil, err
+	}
+	t.eventStream = synchronizeEventStream(t.eventStream)
+
+	maxReplans := t.Plan.MaxReplans
+	completed := make(map[string]*planStepState)
+	var newMessages []Message
+	var failure string
+	turn := 0
+
+	for revision := 0; ; revision++ {
+		if ctx.Err() != nil {
+			return newMessages, ctx.Err()
+		}
+
+		plan, err := t.requestPlan(ctx, planner, workers, userInput, history, completed, failure, revision, &turn)
+		if err != nil {
+			if IsTerminateTeam(err) {
+				return newMessages, nil
+			}
+			return newMessages, err
+		}
+		steps := t.addPlanRevision(plan, revision)
+
+		stepMessages, failed, err := t.runPlanSteps(ctx, steps, userInput, history, completed, &turn)
+		newMessages = append(newMessages, stepMessages...)
+		if IsTerminateTeam(err) {
+			return newMessages, nil
+		}
+		if err == nil {
+			return append(newMessages, t.planResult(steps, newMessages)...), nil
+		}
+		if ctx.Err() != nil || failed == nil || revision >= maxReplans {
+			return newMessages, fmt.Errorf("plan step %s failed in team %s: %w", planStepName(failed), t.FullName(), err)
+		}
+
+		logf.FromContext(ctx).Info("plan step failed, replanning", "team", t.FullName(), "step", failed.ID, "revision", revision, "error", err.Error())
+		failure = fmt.Sprintf("Step %s assigned to %s failed: %v", failed.ID, failed.Member, err)
+	}
+}
+
+// planMembers returns the planner and the members that can be assigned plan steps, in team order
+func (t *Team) planMembers() (TeamMember, []TeamMember, error) {
+	if t.Plan == nil || t.Plan.Planner == "" {
+		return nil, nil, fmt.Errorf("plan strategy in team %s requires a planner", t.FullName())
+	}
+
+	var planner TeamMember
+	workers := make([]TeamMember, 0, len(t.Members))
+	for _, member := range t.Members {
+		if member.GetName() == t.Plan.Planner {
+			planner = member
+			continue
+		}
+		workers = append(workers, member)
+	}
+	if planner == nil {
+		return nil, nil, fmt.Errorf("planner %s is not a member of team %s", t.Plan.Planner, t.FullName())
+	}
+	if len(workers) == 0 {
+		return nil, nil, fmt.Errorf("team %s has no members besides the planner", t.FullName())
+	}
+	return planner, workers, nil
+}
+
+// requestPlan asks the planner for a plan, retrying with the validation error when the plan is invalid
+func (t *teamRun) requestPlan(ctx context.Context, planner TeamMember, workers []TeamMember, userInput Message, history []Message, completed map[string]*planStepState, failure string, revision int, turn *int) (*TeamPlan, error) {
+	if agent, ok := planner.(*Agent); ok {
+		schema, err := planOutputSchema(workers)
+		if err != nil {
+			return nil, err
+		}
+		previousSchema := agent.OutputSchema
+		agent.OutputSchema = schema
+		defer func() { agent.OutputSchema = previousSchema }()
+	}
+
+	plannerHistory := slices.Clone(history)
+	input := NewUserMessage(buildPlannerInput(userInput, workers, completed, failure))
+	for attempt := 1; ; attempt++ {
+		content, err := t.executePlannerTurn(ctx, planner, input, plannerHistory, revision, *turn)
+		*turn++
+		if err != nil {
+			return nil, err
+		}
+
+		plan, err := parsePlan(content, workers, completed)
+		if err == nil {
+			return plan, nil
+		}
+		if attempt >= planMaxAttempts {
+			return nil, fmt.Errorf("planner %s did not return a valid plan for team %s: %w", planner.GetName(), t.FullName(), err)
+		}
+
+		logf.FromContext(ctx).Info("planner returned an invalid plan, retrying", "team", t.FullName(), "attempt", attempt, "error", err.Error())
+		plannerHistory = append(plannerHistory, input, NewAssistantMessage(content))
+		input = NewUserMessage(fmt.Sprintf("Invalid plan: %v. Respond with a corrected plan.", err))
+	}
+}
+
+// executePlannerTurn runs the planner and returns its response. The planner conversation is not part
+// of the team output.
+func (t *teamRun) executePlannerTurn(ctx context.Context, planner TeamMember, input Message, history []Message, revision, turn int) (string, error) {
+	turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, planner.GetName(), planner.GetType())
+	defer turnSpan.End()
+
+	operationData := map[string]string{
+		"teamName":     t.Name,
+		"strategy":     t.Strategy,
+		"turn":         fmt.Sprintf("%d", turn),
+		"memberName":   planner.GetName(),
+		"planRevision": fmt.Sprintf("%d", revision),
+	}
+	turnCtx = t.eventingRecorder.Start(turnCtx, "TeamPlan", fmt.Sprintf("Planning steps for team %s", t.Name), operationData)
+
+	messages := slices.Clone(history)
+	var plannerMessages []Message
+	err := t.executeMemberAndAccumulate(turnCtx, planner, input, &messages, &plannerMessages, turn)
+	if len(plannerMessages) > 0 {
+		t.telemetryRecorder.RecordTurnOutput(turnSpan, plannerMessages, len(plannerMessages))
+	}
+	if err != nil {
+		t.telemetryRecorder.RecordError(turnSpan, err)
+		t.eventingRecorder.Fail(turnCtx, "TeamPlan", fmt.Sprintf("Planning failed: %v", err), err, operationData)
+		if IsTerminateTeam(err) {
+			return "", err
+		}
+		return "", fmt.Errorf("planner %s failed in team %s: %w", planner.GetName(), t.FullName(), err)
+	}
+
+	content := ExtractLastAssistantMessageContent(plannerMessages)
+	operationData["plan"] = content
+	t.telemetryRecorder.RecordSuccess(turnSpan)
+	t.eventingRecorder.Complete(turnCtx, "TeamPlan", fmt.Sprintf("Planner %s responded", planner.GetName()), operationData)
+	return content, nil
+}
+
+// addPlanRevision records the steps of a new plan. Steps of earlier revisions that did not run are
+// cancelled.
+func (t *teamRun) addPlanRevision(plan *TeamPlan, revision int) []*planStepState {
+	for _, step := range t.planSteps {
+		if step.Status == PlanStepPending {
+			step.Status = PlanStepCancelled
+		}
+	}
+
+	steps := make([]*planStepState, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		state := &planStepState{TeamPlanStep: step, Revision: revision, Status: PlanStepPending}
+		steps = append(steps, state)
+		t.planSteps = append(t.planSteps, state)
+	}
+	t.planRevisions = revision + 1
+	return steps
+}
+
+// runPlanSteps executes the steps of a plan in dependency order. All steps whose dependencies have
+// completed run in parallel, except that each member runs one step at a time. On failure it returns the first failed step after the running steps finish.
+func (t *teamRun) runPlanSteps(ctx context.Context, steps []*planStepState, userInput Message, history []Message, completed map[string]*planStepState, turn *int) ([]Message, *planStepState, error) {
+	var newMessages []Message
+
+	for {
+		if ctx.Err() != nil {
+			return newMessages, nil, ctx.Err()
+		}
+
+		ready := readyPlanSteps(steps, completed)
+		if len(ready) == 0 {
+			return newMessages, nil, nil
+		}
+
+		// Steps assigned to the same member share its agent or team instance, so they run one at a time
+		memberLocks := make(map[string]*sync.Mutex)
+		for _, step := range ready {
+			if memberLocks[step.Member] == nil {
+				memberLocks[step.Member] = &sync.Mutex{}
+			}
+		}
+
+		results := make([]planStepResult, len(ready))
+		var wg sync.WaitGroup
+		for i, step := range ready {
+			wg.Add(1)
+			input := NewUserMessage(buildPlanStepInput(step, completed))
+			go func(i, turn int, step *planStepState) {
+				defer wg.Done()
+				lock := memberLocks[step.Member]
+				lock.Lock()
+				defer lock.Unlock()
+				results[i] = t.executePlanStep(ctx, step, input, userInput, history, turn)
+			}(i, *turn, step)
+			*turn++
+		}
+		wg.Wait()
+
+		var failed *planStepResult
+		var terminated error
+		for i := range results {
+			result := &results[i]
+			newMessages = append(newMessages, result.messages...)
+			switch {
+			case IsTerminateTeam(result.err):
+				result.step.Status = PlanStepCompleted
+				terminated = result.err
+			case result.err != nil:
+				result.step.Status = PlanStepFailed
+				result.step.Error = result.err.Error()
+				if failed == nil {
+					failed = result
+				}
+			default:
+				result.step.Status = PlanStepCompleted
+				result.step.output = ExtractLastAssistantMessageContent(result.messages)
+				completed[result.step.ID] = result.step
+			}
+		}
+
+		if terminated != nil {
+			return newMessages, nil, terminated
+		}
+		if failed != nil {
+			return newMessages, failed.step, failed.err
+		}
+	}
+}
+
+// readyPlanSteps returns the pending steps whose dependencies have all completed
+func readyPlanSteps(steps []*planStepState, completed map[string]*planStepState) []*planStepState {
+	var ready []*planStepState
+	for _, step := range steps {
+		if step.Status != PlanStepPending {
+			continue
+		}
+		if !slices.ContainsFunc(step.DependsOn, func(id string) bool { return completed[id] == nil }) {
+			ready = append(ready, step)
+		}
+	}
+	return ready
+}
+
+// executePlanStep runs a step on a copy of the conversation that ends with the original request
+func (t *teamRun) executePlanStep(ctx context.Context, step *planStepState, input, userInput Message, history []Message, turn int) planStepResult {
+	member := t.memberByName(step.Member)
+	messages := append(slices.Clone(history), userInput)
+	var newMessages []Message
+
+	turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, member.GetName(), member.GetType())
+	defer turnSpan.End()
+
+	operationData := map[string]string{
+		"teamName":     t.Name,
+		"strategy":     t.Strategy,
+		"turn":         fmt.Sprintf("%d", turn),
+		"memberName":   member.GetName(),
+		"planStep":     step.ID,
+		"planRevision": fmt.Sprintf("%d", step.Revision),
+		"dependsOn":    strings.Join(step.DependsOn, ","),
+	}
+	turnCtx = t.eventingRecorder.Start(turnCtx, "TeamTurn", fmt.Sprintf("Executing plan step %s for team %s", step.ID, t.Name), operationData)
+
+	err := t.executeMemberAndAccumulate(turnCtx, member, input, &messages, &newMessages, turn)
+	if len(newMessages) > 0 {
+		t.telemetryRecorder.RecordTurnOutput(turnSpan, newMessages, len(newMessages))
+	}
+
+	if err != nil && !IsTerminateTeam(err) {
+		operationData["planStepStatus"] = PlanStepFailed
+		t.telemetryRecorder.RecordPlanStep(turnSpan, step.ID, PlanStepFailed)
+		t.telemetryRecorder.RecordError(turnSpan, err)
+		t.eventingRecorder.Fail(turnCtx, "TeamTurn", fmt.Sprintf("Plan step %s failed: %v", step.ID, err), err, operationData)
+		return planStepResult{step: step, messages: newMessages, err: err}
+	}
+
+	operationData["planStepStatus"] = PlanStepCompleted
+	t.telemetryRecorder.RecordPlanStep(turnSpan, step.ID, PlanStepCompleted)
+	t.telemetryRecorder.RecordSuccess(turnSpan)
+	t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Plan step %s completed successfully", step.ID), operationData)
+	return planStepResult{step: step, messages: newMessages, err: err}
+}
+
+// planResult returns a final message with the outputs of the steps no other step depends on, unless
+// the only such output is already the last message
+func (t *Team) planResult(steps []*planStepState, messages []Message) []Message {
+	var finalSteps []*planStepState
+	for _, step := range steps {
+		isDependency := slices.ContainsFunc(steps, func(other *planStepState) bool {
+			return slices.Contains(other.DependsOn, step.ID)
+		})
+		if !isDependency {
+			finalSteps = append(finalSteps, step)
+		}
+	}
+
+	if len(finalSteps) == 1 && ExtractLastAssistantMessageContent(messages) == finalSteps[0].output {
+		return nil
+	}
+
+	sections := make([]string, 0, len(finalSteps))
+	for _, step := range finalSteps {
+		sections = append(sections, fmt.Sprintf("## %s\n%s", step.ID, step.output))
+	}
+	if len(finalSteps) == 1 {
+		sections = []string{finalSteps[0].output}
+	}
+
+	result := NewAssistantMessage(strings.Join(sections, "\n\n"))
+	result.OfAssistant.Name = param.NewOpt(t.Name)
+	return []Message{result}
+}
+
+// planJSON returns the steps of all plan revisions with their statuses
+func (t *teamRun) planJSON() string {
+	if len(t.planSteps) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(t.planSteps)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (t *Team) memberByName(name string) TeamMember {
+	for _, member := range t.Members {
+		if member.GetName() == name {
+			return member
+		}
+	}
+	return nil
+}
+
+func planStepName(step *planStepState) string {
+	if step == nil {
+		return "unknown"
+	}
+	return step.ID
+}
+
+func buildPlannerInput(userInput Message, workers []TeamMember, completed map[string]*planStepState, failure string) string {
+	var b strings.Builder
+	b.WriteString("Plan how the team members below should handle the request. Break the work into steps and assign each step to one member. ")
+	b.WriteString("List in dependsOn the ids of the steps whose results a step needs. Steps that do not depend on each other run in parallel.\n\n")
+	fmt.Fprintf(&b, "Members: %s\n\n", buildRoles(workers))
+	fmt.Fprintf(&b, "Request:\n%s\n", messageText(userInput))
+
+	if len(completed) > 0 {
+		b.WriteString("\nCompleted steps, which new steps can depend on by id:\n")
+		ids := make([]string, 0, len(completed))
+		for id := range completed {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			fmt.Fprintf(&b, "- %s (%s): %s\n", id, completed[id].Member, completed[id].output)
+		}
+	}
+	if failure != "" {
+		fmt.Fprintf(&b, "\nThe previous plan failed. %s\nPlan the remaining work with new step ids.\n", failure)
+	}
+
+	b.WriteString(`
+Respond with JSON only: {"steps": [{"id": "...", "member": "...", "task": "...", "dependsOn": ["..."]}]}`)
+	return b.String()
+}
+
+func buildPlanStepInput(step *planStepState, completed map[string]*planStepState) string {
+	if len(step.DependsOn) == 0 {
+		return step.Task
+	}
+
+	var b strings.Builder
+	b.WriteString(step.Task)
+	b.WriteString("\n\nResults of the steps this task depends on:")
+	for _, id := range step.DependsOn {
+		fmt.Fprintf(&b, "\n\n## %s (%s)\n%s", id, completed[id].Member, completed[id].output)
+	}
+	return b.String()
+}
+
+func messageText(msg Message) string {
+	if msg.OfUser != nil {
+		return msg.OfUser.Content.OfString.Value
+	}
+	return ExtractLastAssistantMessageContent([]Message{msg})
+}
+
+// planOutputSchema restricts the planner response to steps assigned to the given members
+func planOutputSchema(workers []TeamMember) (*runtime.RawExtension, error) {
+	names := make([]string, 0, len(workers))
+	for _, member := range workers {
+		names = append(names, member.GetName())
+	}
+
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"steps": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":        map[string]any{"type": "string", "description": "Unique step id"},
+						"member":    map[string]any{"type": "string", "enum": names, "description": "Member that executes the step"},
+						"task":      map[string]any{"type": "string", "description": "Instructions for the member"},
+						"dependsOn": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Ids of the steps whose results this step needs"},
+					},
+					"required":             []string{"id", "member", "task", "dependsOn"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"steps"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build plan output schema: %w", err)
+	}
+	return &runtime.RawExtension{Raw: schema}, nil
+}
+
+// parsePlan reads and validates a plan. Steps may depend on steps of the same plan and on steps
+// completed under earlier plans.
+func parsePlan(content string, workers []TeamMember, completed map[string]*planStepState) (*TeamPlan, error) {
+	var plan TeamPlan
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &plan); err != nil {
+		return nil, fmt.Errorf("plan is not valid JSON: %w", err)
+	}
+	if len(plan.Steps) == 0 {
+		return nil, errors.New("plan has no steps")
+	}
+
+	ids := make(map[string]bool, len(plan.Steps))
+	for _, step := range plan.Steps {
+		switch {
+		case step.ID == "":
+			return nil, errors.New("every step needs an id")
+		case ids[step.ID] || completed[step.ID] != nil:
+			return nil, fmt.Errorf("step id %s is used more than once", step.ID)
+		case step.Task == "":
+			return nil, fmt.Errorf("step %s has no task", step.ID)
+		case !slices.ContainsFunc(workers, func(m TeamMember) bool { return m.GetName() == step.Member }):
+			return nil, fmt.Errorf("step %s is assigned to %s, which is not one of: %s", step.ID, step.Member, buildParticipants(workers))
+		}
+		ids[step.ID] = true
+	}
+
+	for _, step := range plan.Steps {
+		for _, dep := range step.DependsOn {
+			if !ids[dep] && completed[dep] == nil {
+				return nil, fmt.Errorf("step %s depends on unknown step %s", step.ID, dep)
+			}
+		}
+	}
+
+	if cycle := planCycle(plan.Steps); cycle != "" {
+		return nil, fmt.Errorf("steps depend on each other in a cycle: %s", cycle)
+	}
+	return &plan, nil
+}
+
+// stripCodeFence removes a markdown code fence that models sometimes put around JSON responses
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	return strings.TrimSuffix(content, "```")
+}
+
+// planCycle returns a step dependency cycle, or an empty string when the plan can complete
+func planCycle(steps []TeamPlanStep) string {
+	dependsOn := make(map[string][]string, len(steps))
+	for _, step := range steps {
+		dependsOn[step.ID] = step.DependsOn
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(steps))
+	var path []string
+	var visit func(id string) string
+	visit = func(id string) string {
+		switch state[id] {
+		case visiting:
+			return strings.Join(append(path[slices.Index(path, id):], id), " -> ")
+		case done:
+			return ""
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range dependsOn[id] {
+			if cycle := visit(dep); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return ""
+	}
+
+	for _, step := range steps {
+		if cycle := visit(step.ID); cycle != "" {
+			return cycle
+		}
+	}
+	return ""
+}
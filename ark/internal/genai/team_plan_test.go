@@ -0,0 +1,239 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/mock"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// scriptedTeamMember replies with the next response, or fails with the next error, on every call
+type scriptedTeamMember struct {
+	name      string
+	responses []string
+	errs      []error
+	mu        sync.Mutex
+	inputs    []string
+	histories [][]Message
+}
+
+func (m *scriptedTeamMember) GetName() string        { return m.name }
+func (m *scriptedTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *scriptedTeamMember) GetDescription() string { return "" }
+
+func (m *scriptedTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	call := len(m.inputs)
+	m.inputs = append(m.inputs, messageText(userInput))
+	m.histories = append(m.histories, history)
+	if call < len(m.errs) && m.errs[call] != nil {
+		return nil, m.errs[call]
+	}
+	return &ExecutionResult{Messages: []Message{NewAssistantMessage(m.responses[min(call, len(m.responses)-1)])}}, nil
+}
+
+// exclusiveTeamMember keeps per-call state on itself, like an agent does, and records how many calls
+// overlapped
+type exclusiveTeamMember struct {
+	name       string
+	running    atomic.Int32
+	overlapped atomic.Bool
+	lastInput  string
+}
+
+func (m *exclusiveTeamMember) GetName() string        { return m.name }
+func (m *exclusiveTeamMember) GetType() string        { return MemberTypeAgent }
+func (m *exclusiveTeamMember) GetDescription() string { return "" }
+
+func (m *exclusiveTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	if m.running.Add(1) > 1 {
+		m.overlapped.Store(true)
+	}
+	defer m.running.Add(-1)
+
+	m.lastInput = messageText(userInput)
+	time.Sleep(10 * time.Millisecond)
+	return &ExecutionResult{Messages: []Message{NewAssistantMessage("done: " + m.lastInput)}}, nil
+}
+
+func newPlanTestTeam(maxReplans int, members ...TeamMember) (*Team, *mock.MockTeamRecorder) {
+	recorder := mock.NewTeamRecorder()
+	return &Team{
+		Name:              "research",
+		Namespace:         "default",
+		Strategy:          "plan",
+		Members:           members,
+		Plan:              &arkv1alpha1.TeamPlanSpec{Planner: "planner", MaxReplans: maxReplans},
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}, recorder
+}
+
+// planStatuses reads the status of every plan step from the plan recorded on the team span
+func planStatuses(t *testing.T, recorder *mock.MockTeamRecorder) map[string]string {
+	var steps []planStepState
+	require.NoError(t, json.Unmarshal([]byte(recorder.Tracer.FindSpan("team.execution").Attributes["team.plan"].(string)), &steps))
+
+	statuses := make(map[string]string)
+	for _, step := range steps {
+		statuses[step.ID] = step.Status
+	}
+	return statuses
+}
+
+func TestExecutePlan(t *testing.T) {
+	t.Run("runs steps in dependency order and passes results on", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{`{"steps": [
+			{"id": "research", "member": "researcher", "task": "Find sources"},
+			{"id": "analyze", "member": "analyst", "task": "Analyze the market"},
+			{"id": "write", "member": "writer", "task": "Write the report", "dependsOn": ["research", "analyze"]}
+		]}`}}
+		researcher := &scriptedTeamMember{name: "researcher", responses: []string{"three sources"}}
+		analyst := &scriptedTeamMember{name: "analyst", responses: []string{"growing market"}}
+		writer := &scriptedTeamMember{name: "writer", responses: []string{"final report"}}
+		team, recorder := newPlanTestTeam(0, planner, researcher, analyst, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("report on the market"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3, "planner responses are not part of the output")
+		require.Equal(t, "final report", ExtractLastAssistantMessageContent(result.Messages))
+
+		require.Contains(t, planner.inputs[0], "report on the market")
+		require.Equal(t, "Find sources", researcher.inputs[0])
+		require.Equal(t, "report on the market", messageText(researcher.histories[0][0]), "steps see the original request")
+		require.Contains(t, writer.inputs[0], "## research (researcher)\nthree sources")
+		require.Contains(t, writer.inputs[0], "## analyze (analyst)\ngrowing market")
+
+		require.Equal(t, map[string]string{"research": PlanStepCompleted, "analyze": PlanStepCompleted, "write": PlanStepCompleted}, planStatuses(t, recorder))
+		span := recorder.Tracer.FindSpan("team.execution")
+		require.Contains(t, span.Attributes["team.plan"], `"id":"write"`)
+		require.Equal(t, 1, span.Attributes["team.plan.revisions"])
+	})
+
+	t.Run("combines the outputs of independent final steps", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{`{"steps": [
+			{"id": "pros", "member": "researcher", "task": "List pros"},
+			{"id": "cons", "member": "analyst", "task": "List cons"}
+		]}`}}
+		team, _ := newPlanTestTeam(0, planner,
+			&scriptedTeamMember{name: "researcher", responses: []string{"cheap"}},
+			&scriptedTeamMember{name: "analyst", responses: []string{"slow"}})
+
+		result, err := team.Execute(context.Background(), NewUserMessage("compare"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3)
+		require.Equal(t, "## pros\ncheap\n\n## cons\nslow", ExtractLastAssistantMessageContent(result.Messages))
+	})
+
+	t.Run("replans after a failed step", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{
+			`{"steps": [{"id": "research", "member": "researcher", "task": "Find sources"}, {"id": "write", "member": "writer", "task": "Write", "dependsOn": ["research"]}]}`,
+			`{"steps": [{"id": "summarize", "member": "researcher", "task": "Summarize", "dependsOn": ["research"]}]}`,
+		}}
+		researcher := &scriptedTeamMember{name: "researcher", responses: []string{"three sources", "short summary"}}
+		writer := &scriptedTeamMember{name: "writer", errs: []error{errors.New("model unavailable")}}
+		team, recorder := newPlanTestTeam(1, planner, researcher, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("report"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "short summary", ExtractLastAssistantMessageContent(result.Messages))
+
+		require.Len(t, planner.inputs, 2)
+		require.Contains(t, planner.inputs[1], "- research (researcher): three sources")
+		require.Contains(t, planner.inputs[1], "Step write assigned to writer failed: model unavailable")
+		require.Equal(t, map[string]string{"research": PlanStepCompleted, "write": PlanStepFailed, "summarize": PlanStepCompleted}, planStatuses(t, recorder))
+		require.Equal(t, 2, recorder.Tracer.FindSpan("team.execution").Attributes["team.plan.revisions"])
+	})
+
+	t.Run("fails when no replans are left", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{`{"steps": [{"id": "write", "member": "writer", "task": "Write"}]}`}}
+		writer := &scriptedTeamMember{name: "writer", errs: []error{errors.New("model unavailable")}}
+		team, _ := newPlanTestTeam(0, planner, writer)
+
+		_, err := team.Execute(context.Background(), NewUserMessage("report"), nil, nil, nil)
+		require.ErrorContains(t, err, "plan step write failed in team default/research: model unavailable")
+		require.Len(t, planner.inputs, 1)
+	})
+
+	t.Run("runs independent steps of the same member one at a time", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{`{"steps": [
+			{"id": "pros", "member": "researcher", "task": "List pros"},
+			{"id": "cons", "member": "researcher", "task": "List cons"}
+		]}`}}
+		researcher := &exclusiveTeamMember{name: "researcher"}
+		team, recorder := newPlanTestTeam(0, planner, researcher)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("compare"), nil, nil, nil)
+		require.NoError(t, err)
+		require.False(t, researcher.overlapped.Load())
+		require.Equal(t, "## pros\ndone: List pros\n\n## cons\ndone: List cons", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, map[string]string{"pros": PlanStepCompleted, "cons": PlanStepCompleted}, planStatuses(t, recorder))
+	})
+
+	t.Run("restores the output schema of a planner agent", func(t *testing.T) {
+		schema := &runtime.RawExtension{Raw: []byte(`{"type": "object"}`)}
+		planner := &Agent{
+			Name:              "planner",
+			Namespace:         "default",
+			OutputSchema:      schema,
+			telemetryRecorder: noop.NewProvider().AgentRecorder(),
+			eventingRecorder:  eventnoop.NewProvider().AgentRecorder(),
+		}
+		team, _ := newPlanTestTeam(0, planner, &scriptedTeamMember{name: "writer", responses: []string{"report"}})
+
+		_, err := team.Execute(context.Background(), NewUserMessage("report"), nil, nil, nil)
+		require.ErrorContains(t, err, "has no model configured")
+		require.Same(t, schema, planner.OutputSchema)
+	})
+
+	t.Run("asks the planner again for an invalid plan", func(t *testing.T) {
+		planner := &scriptedTeamMember{name: "planner", responses: []string{
+			`{"steps": [{"id": "write", "member": "editor", "task": "Write"}]}`,
+			"```json\n{\"steps\": [{\"id\": \"write\", \"member\": \"writer\", \"task\": \"Write\"}]}\n```",
+		}}
+		writer := &scriptedTeamMember{name: "writer", responses: []string{"report"}}
+		team, _ := newPlanTestTeam(0, planner, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("report"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "report", ExtractLastAssistantMessageContent(result.Messages))
+		require.Contains(t, planner.inputs[1], "Invalid plan: step write is assigned to editor")
+		require.Len(t, planner.histories[1], 2, "the retry sees the rejected plan")
+	})
+}
+
+func TestParsePlan(t *testing.T) {
+	workers := []TeamMember{&scriptedTeamMember{name: "researcher"}, &scriptedTeamMember{name: "writer"}}
+	completed := map[string]*planStepState{"research": {TeamPlanStep: TeamPlanStep{ID: "research", Member: "researcher"}}}
+
+	plan, err := parsePlan(`{"steps": [{"id": "write", "member": "writer", "task": "Write", "dependsOn": ["research"]}]}`, workers, completed)
+	require.NoError(t, err)
+	require.Equal(t, []string{"research"}, plan.Steps[0].DependsOn)
+
+	tests := map[string]string{
+		`not json`:      "plan is not valid JSON",
+		`{"steps": []}`: "plan has no steps",
+		`{"steps": [{"member": "writer", "task": "Write"}]}`:                                                                                              "every step needs an id",
+		`{"steps": [{"id": "research", "member": "writer", "task": "Write"}]}`:                                                                            "step id research is used more than once",
+		`{"steps": [{"id": "a", "member": "writer"}]}`:                                                                                                    "step a has no task",
+		`{"steps": [{"id": "a", "member": "writer", "task": "Write", "dependsOn": ["b"]}]}`:                                                               "step a depends on unknown step b",
+		`{"steps": [{"id": "a", "member": "writer", "task": "x", "dependsOn": ["b"]}, {"id": "b", "member": "writer", "task": "y", "dependsOn": ["a"]}]}`: "cycle: a -> b -> a",
+	}
+	for content, expected := range tests {
+		_, err := parsePlan(content, workers, completed)
+		require.ErrorContains(t, err, expected, content)
+	}
+}
@@ -3,22 +3,48 @@ package genai
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"text/template"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
 const defaultSelectorPrompt = `You are in a role play game. The following roles are available:
 {{.Roles}}.
-Read the following conversation. Then select the next role from {{.Participants}} to play. Only return the role.
+Read the following conversation. Then select the next role from {{.Participants}} to play. Return the role and the reason for choosing it.
 
 {{.History}}
 
-Read the above conversation. Then select the next role from {{.Participants}} to play. Only return the role.`
+Read the above conversation. Then select the next role from {{.Participants}} to play. Return the role and the reason for choosing it.`
+
+const (
+	SelectorInvalidSelectionFail      = "fail"
+	SelectorInvalidSelectionFallback  = "fallback"
+	SelectorInvalidSelectionTerminate = "terminate"
+)
+
+// selectorMaxAttempts is how many times the selector agent is asked before the invalid selection policy applies
+const selectorMaxAttempts = 3
+
+// SelectorDecision is the structured response expected from the selector agent
+type SelectorDecision struct {
+	Member string `json:"member"`
+	Reason string `json:"reason"`
+}
+
+// selection is the member chosen for a turn and the reason for choosing it
+type selection struct {
+	member   TeamMember
+	reason   string
+	attempts int
+}
 
 type SelectorTemplateData struct {
 	Roles        string
@@ -80,8 +106,7 @@ func (t *Team) loadSelectorAgent(ctx context.Context) (*Agent, error) {
 	return agent, nil
 }
 
-//nolint:gocognit // Complex function handling selector agent logic, but cohesive responsibilities
-func (t *Team) selectMember(ctx context.Context, messages []Message, tmpl *template.Template, participantsList, rolesList, previousMember string, candidateMembers []TeamMember) (TeamMember, error) {
+func (t *teamRun) selectMember(ctx context.Context, messages []Message, tmpl *template.Template, participantsList, rolesList, previousMember string, candidateMembers []TeamMember) (selection, error) {
 	history := buildHistory(messages)
 	data := SelectorTemplateData{
 		Roles:        rolesList,
@@ -91,67 +116,137 @@ func (t *Team) selectMember(ctx context.Context, messages []Message, tmpl *templ
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, err
+		return selection{}, err
 	}
 
 	selectorAgent, err := t.loadSelectorAgent(ctx)
 	if err != nil {
-		return nil, err
+		return selection{}, err
+	}
+
+	// Use candidateMembers if provided, otherwise use all team members
+	membersToSearch := t.Members
+	if candidateMembers != nil {
+		membersToSearch = candidateMembers
 	}
 
-	result, err := selectorAgent.Execute(ctx, NewUserMessage("Select the next participant to respond."), []Message{NewSystemMessage(buf.String())}, nil, nil)
+	selectorAgent.OutputSchema, err = selectorOutputSchema(membersToSearch)
 	if err != nil {
-		if IsTerminateTeam(err) {
-			return nil, err
+		return selection{}, err
+	}
+
+	selectorHistory := []Message{NewSystemMessage(buf.String())}
+	input := NewUserMessage("Select the next participant to respond.")
+	for attempt := 1; ; attempt++ {
+		result, err := selectorAgent.Execute(ctx, input, selectorHistory, nil, nil)
+		if err != nil {
+			if IsTerminateTeam(err) {
+				return selection{}, err
+			}
+			return selection{}, fmt.Errorf("selector agent call failed: %w", err)
+		}
+
+		if len(result.Messages) == 0 {
+			return selection{}, fmt.Errorf("selector agent returned no messages")
 		}
-		return nil, fmt.Errorf("selector agent call failed: %w", err)
+
+		content := ExtractLastAssistantMessageContent(result.Messages)
+		member, reason, err := parseSelectorDecision(content, membersToSearch)
+		if err == nil {
+			return selection{member: member, reason: reason, attempts: attempt}, nil
+		}
+
+		if attempt >= selectorMaxAttempts {
+			return t.handleInvalidSelection(err, membersToSearch, previousMember, attempt)
+		}
+
+		logf.FromContext(ctx).Info("selector agent made an invalid selection, retrying", "team", t.FullName(), "attempt", attempt, "error", err.Error())
+		selectorHistory = append(selectorHistory, input, NewAssistantMessage(content))
+		input = NewUserMessage(fmt.Sprintf("Invalid selection: %v. Select one of: %s.", err, participantsList))
 	}
+}
 
-	if len(result.Messages) == 0 {
-		return nil, fmt.Errorf("selector agent returned no messages")
+// selectorOutputSchema restricts the selector agent response to one of the candidates and a reason
+func selectorOutputSchema(candidates []TeamMember) (*runtime.RawExtension, error) {
+	names := make([]string, 0, len(candidates))
+	for _, member := range candidates {
+		names = append(names, member.GetName())
 	}
 
-	var selectedName string
-	lastMsg := result.Messages[len(result.Messages)-1]
-	if lastMsg.OfAssistant != nil && lastMsg.OfAssistant.Content.OfString.Value != "" {
-		selectedName = strings.TrimSpace(lastMsg.OfAssistant.Content.OfString.Value)
-	} else {
-		return nil, fmt.Errorf("selector agent returned invalid response")
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"member": map[string]any{
+				"type":        "string",
+				"enum":        names,
+				"description": "Name of the participant who should respond next",
+			},
+			"reason": map[string]any{
+				"type":        "string",
+				"description": "Why this participant should respond next",
+			},
+		},
+		"required":             []string{"member", "reason"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build selector output schema: %w", err)
 	}
+	return &runtime.RawExtension{Raw: schema}, nil
+}
 
-	// Use candidateMembers if provided, otherwise use all team members
-	membersToSearch := t.Members
-	if candidateMembers != nil {
-		membersToSearch = candidateMembers
+// parseSelectorDecision returns the selected candidate and the reason given for it. Responses that
+// are not JSON are treated as a bare member name, for providers without structured output.
+func parseSelectorDecision(content string, candidates []TeamMember) (TeamMember, string, error) {
+	var decision SelectorDecision
+	if err := json.Unmarshal([]byte(content), &decision); err != nil {
+		decision = SelectorDecision{Member: strings.TrimSpace(content)}
 	}
 
-	// Find selected member
-	for _, member := range membersToSearch {
-		if member.GetName() == selectedName {
-			return member, nil
+	if decision.Member == "" {
+		return nil, "", fmt.Errorf("no member selected")
+	}
+	for _, member := range candidates {
+		if member.GetName() == decision.Member {
+			return member, decision.Reason, nil
 		}
 	}
+	return nil, "", fmt.Errorf("'%s' is not a valid participant", decision.Member)
+}
+
+// handleInvalidSelection applies the invalid selection policy once the selector agent has used all attempts
+func (t *teamRun) handleInvalidSelection(selectionErr error, candidates []TeamMember, previousMember string, attempts int) (selection, error) {
+	policy := SelectorInvalidSelectionFallback
+	if t.Selector != nil && t.Selector.InvalidSelectionPolicy != "" {
+		policy = t.Selector.InvalidSelectionPolicy
+	}
 
-	// Fallback to first member if not found
-	if len(membersToSearch) > 0 {
-		fallback := membersToSearch[0]
+	switch policy {
+	case SelectorInvalidSelectionFail:
+		return selection{}, fmt.Errorf("selector agent made no valid selection after %d attempts: %w", attempts, selectionErr)
+	case SelectorInvalidSelectionTerminate:
+		t.setTerminationReason(fmt.Sprintf("invalid selection: selector agent made no valid selection after %d attempts", attempts))
+		return selection{}, &TerminateTeam{}
+	}
 
-		// Avoid repeating same member
-		if fallback.GetName() == previousMember && len(membersToSearch) > 1 {
-			fallback = membersToSearch[1]
-		}
-		return fallback, nil
+	if len(candidates) == 0 {
+		return selection{}, fmt.Errorf("no members available")
 	}
 
-	return nil, fmt.Errorf("no members available")
+	fallback := candidates[0]
+	// Avoid repeating same member
+	if fallback.GetName() == previousMember && len(candidates) > 1 {
+		fallback = candidates[1]
+	}
+	return selection{member: fallback, reason: fmt.Sprintf("fallback after invalid selection: %v", selectionErr), attempts: attempts}, nil
 }
 
 // determineNextMember routes to the appropriate selection logic based on whether graph constraints exist.
-func (t *Team) determineNextMember(ctx context.Context, messages []Message, tmpl *template.Template, previousMember string, legalTransitions map[string][]TeamMember) (TeamMember, error) {
+func (t *teamRun) determineNextMember(ctx context.Context, messages []Message, tmpl *template.Template, previousMember string, legalTransitions map[string][]TeamMember) (selection, error) {
 	switch {
 	case previousMember == "":
 		// First turn: use first member
-		return t.Members[0], nil
+		return selection{member: t.Members[0], reason: "first member"}, nil
 	case len(legalTransitions) == 0:
 		// No graph constraints: use standard selector (all members available)
 		participantsList := buildParticipants(t.Members)
@@ -164,7 +259,7 @@ func (t *Team) determineNextMember(ctx context.Context, messages []Message, tmpl
 }
 
 // selectFromGraphConstraints selects a member from the graph-constrained legal transitions.
-func (t *Team) selectFromGraphConstraints(ctx context.Context, messages []Message, tmpl *template.Template, previousMember string, legalTransitions map[string][]TeamMember) (TeamMember, error) {
+func (t *teamRun) selectFromGraphConstraints(ctx context.Context, messages []Message, tmpl *template.Template, previousMember string, legalTransitions map[string][]TeamMember) (selection, error) {
 	// Build name-to-member lookup map once
 	memberLookup := make(map[string]TeamMember, len(t.Members))
 	for _, member := range t.Members {
@@ -176,7 +271,7 @@ func (t *Team) selectFromGraphConstraints(ctx context.Context, messages []Messag
 
 	if previousMemberObj == nil {
 		// Previous member not found, fallback to first member
-		return t.Members[0], nil
+		return selection{member: t.Members[0], reason: fmt.Sprintf("member %s not found, restarting from first member", previousMember)}, nil
 	}
 
 	legal := legalTransitions[previousMember]
@@ -184,11 +279,11 @@ func (t *Team) selectFromGraphConstraints(ctx context.Context, messages []Messag
 	switch len(legal) {
 	case 0:
 		// No legal transitions - fallback to first member
-		return t.Members[0], nil
+		return selection{member: t.Members[0], reason: fmt.Sprintf("no graph transitions from %s, restarting from first member", previousMember)}, nil
 	case 1:
 		// Only one legal transition - use it directly (skip selector agent for optimization)
 		selectedMember := legal[0]
-		return selectedMember, nil
+		return selection{member: selectedMember, reason: fmt.Sprintf("only graph transition from %s", previousMember)}, nil
 	default:
 		// Multiple legal transitions - use selector agent to choose from candidates
 		participantsList := buildParticipants(legal)
@@ -198,7 +293,7 @@ func (t *Team) selectFromGraphConstraints(ctx context.Context, messages []Messag
 }
 
 //nolint:gocognit // Complex function orchestrating selector logic with graph constraints, but cohesive responsibilities
-func (t *Team) executeSelector(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (t *teamRun) executeSelector(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	messages := append([]Message{}, history...)
 	var newMessages []Message
 
@@ -231,24 +326,34 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 	}
 
 	previousMember := ""
+	start := 0
+	if checkpoint := t.resumeCheckpoint(ctx); checkpoint != nil {
+		messages = append(messages, checkpoint.Messages...)
+		newMessages = slices.Clone(checkpoint.Messages)
+		previousMember = checkpoint.Member
+		start = checkpoint.Turn
+	}
 
-	for turn := 0; ; turn++ {
+	for turn := start; ; turn++ {
 		// Determine next member based on graph constraints (if any)
-		nextMember, err := t.determineNextMember(ctx, messages, tmpl, previousMember, legalTransitions)
+		next, err := t.determineNextMember(ctx, messages, tmpl, previousMember, legalTransitions)
 		if err != nil {
 			if IsTerminateTeam(err) {
 				return newMessages, nil
 			}
 			return newMessages, err
 		}
+		nextMember := next.member
 
 		// Start turn-level telemetry span
 		turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, nextMember.GetName(), nextMember.GetType())
+		t.telemetryRecorder.RecordSelectorDecision(turnSpan, next.reason, next.attempts)
 
 		operationData := map[string]string{
-			"teamName": t.Name,
-			"strategy": t.Strategy,
-			"turn":     fmt.Sprintf("%d", turn),
+			"teamName":       t.Name,
+			"strategy":       t.Strategy,
+			"turn":           fmt.Sprintf("%d", turn),
+			"selectorReason": next.reason,
 		}
 		turnCtx = t.eventingRecorder.Start(turnCtx, "TeamTurn", fmt.Sprintf("Executing turn %d for team %s", turn, t.Name), operationData)
 
@@ -274,6 +379,7 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 		t.eventingRecorder.Complete(turnCtx, "TeamTurn", fmt.Sprintf("Team turn %d completed successfully", turn), operationData)
 
 		previousMember = nextMember.GetName()
+		t.saveCheckpoint(ctx, TeamCheckpoint{Turn: turn + 1, Member: previousMember}, newMessages)
 
 		if t.MaxTurns != nil && turn+1 >= *t.MaxTurns {
 			return newMessages, nil
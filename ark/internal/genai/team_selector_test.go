@@ -266,7 +266,7 @@ func TestDetermineNextMember(t *testing.T) {
 				return
 			}
 
-			member, err := team.determineNextMember(ctx, messages, tmpl, tt.previousMember, tt.legalTransitions)
+			next, err := (&teamRun{Team: team}).determineNextMember(ctx, messages, tmpl, tt.previousMember, tt.legalTransitions)
 
 			if tt.wantError {
 				require.Error(t, err)
@@ -274,8 +274,8 @@ func TestDetermineNextMember(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.NotNil(t, member)
-			assert.Equal(t, tt.wantMember, member.GetName())
+			require.NotNil(t, next.member)
+			assert.Equal(t, tt.wantMember, next.member.GetName())
 			// Index is no longer returned, verify member name matches expected
 		})
 	}
@@ -349,7 +349,7 @@ func TestSelectFromGraphConstraints(t *testing.T) {
 				return
 			}
 
-			member, err := team.selectFromGraphConstraints(ctx, messages, tmpl, tt.previousMember, tt.legalTransitions)
+			next, err := (&teamRun{Team: team}).selectFromGraphConstraints(ctx, messages, tmpl, tt.previousMember, tt.legalTransitions)
 
 			if tt.wantError {
 				require.Error(t, err)
@@ -360,8 +360,8 @@ func TestSelectFromGraphConstraints(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.NotNil(t, member)
-			assert.Equal(t, tt.wantMember, member.GetName())
+			require.NotNil(t, next.member)
+			assert.Equal(t, tt.wantMember, next.member.GetName())
 			// Index is no longer returned, verify member name matches expected
 		})
 	}
@@ -462,3 +462,104 @@ func TestBuildRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSelectorDecision(t *testing.T) {
+	candidates := []TeamMember{
+		&mockTeamMember{name: "researcher"},
+		&mockTeamMember{name: "analyst"},
+	}
+
+	tests := []struct {
+		name       string
+		content    string
+		wantMember string
+		wantReason string
+		wantError  string
+	}{
+		{
+			name:       "structured decision",
+			content:    `{"member": "analyst", "reason": "research is complete"}`,
+			wantMember: "analyst",
+			wantReason: "research is complete",
+		},
+		{
+			name:       "bare member name",
+			content:    " researcher\n",
+			wantMember: "researcher",
+		},
+		{
+			name:      "unknown member",
+			content:   `{"member": "writer", "reason": "time to write"}`,
+			wantError: "'writer' is not a valid participant",
+		},
+		{
+			name:      "empty member",
+			content:   `{"reason": "unsure"}`,
+			wantError: "no member selected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, reason, err := parseSelectorDecision(tt.content, candidates)
+			if tt.wantError != "" {
+				require.ErrorContains(t, err, tt.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMember, member.GetName())
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestHandleInvalidSelection(t *testing.T) {
+	candidates := []TeamMember{
+		&mockTeamMember{name: "researcher"},
+		&mockTeamMember{name: "analyst"},
+	}
+	selectionErr := assert.AnError
+
+	t.Run("falls back to a candidate other than the previous member by default", func(t *testing.T) {
+		team := &Team{Members: candidates}
+
+		next, err := (&teamRun{Team: team}).handleInvalidSelection(selectionErr, candidates, "researcher", selectorMaxAttempts)
+		require.NoError(t, err)
+		assert.Equal(t, "analyst", next.member.GetName())
+		assert.Contains(t, next.reason, "fallback after invalid selection")
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		team := &Team{Members: candidates, Selector: &arkv1alpha1.TeamSelectorSpec{InvalidSelectionPolicy: SelectorInvalidSelectionFail}}
+
+		_, err := (&teamRun{Team: team}).handleInvalidSelection(selectionErr, candidates, "researcher", selectorMaxAttempts)
+		require.ErrorContains(t, err, "no valid selection after 3 attempts")
+	})
+
+	t.Run("terminates", func(t *testing.T) {
+		team := &Team{Members: candidates, Selector: &arkv1alpha1.TeamSelectorSpec{InvalidSelectionPolicy: SelectorInvalidSelectionTerminate}}
+
+		run := &teamRun{Team: team}
+		_, err := run.handleInvalidSelection(selectionErr, candidates, "researcher", selectorMaxAttempts)
+		require.True(t, IsTerminateTeam(err))
+		require.Equal(t, "invalid selection: selector agent made no valid selection after 3 attempts", run.terminationReason)
+	})
+}
+
+func TestSelectorOutputSchema(t *testing.T) {
+	schema, err := selectorOutputSchema([]TeamMember{
+		&mockTeamMember{name: "researcher"},
+		&mockTeamMember{name: "analyst"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"member": {"type": "string", "enum": ["researcher", "analyst"], "description": "Name of the participant who should respond next"},
+			"reason": {"type": "string", "description": "Why this participant should respond next"}
+		},
+		"required": ["member", "reason"],
+		"additionalProperties": false
+	}`, string(schema.Raw))
+}
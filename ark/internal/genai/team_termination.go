@@ -0,0 +1,86 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// compileTerminationRegex compiles the regex termination condition once, when the team is loaded
+func compileTerminationRegex(spec *arkv1alpha1.TeamTerminationSpec) (*regexp.Regexp, error) {
+	if spec == nil || spec.Regex == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(spec.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", spec.Regex, err)
+	}
+	return re, nil
+}
+
+// checkTermination returns why the team should stop after a member turn, or an empty string when
+// no termination condition is met
+func (t *teamRun) checkTermination(ctx context.Context, member TeamMember, memberMessages []Message) string {
+	spec := t.Termination
+	if spec == nil {
+		return ""
+	}
+
+	content := ExtractLastAssistantMessageContent(memberMessages)
+
+	if t.terminationRegex != nil && content != "" && t.terminationRegex.MatchString(content) {
+		return fmt.Sprintf("response of %s matched regex %q", member.GetName(), spec.Regex)
+	}
+
+	if spec.Keyword != "" && strings.Contains(strings.ToLower(content), strings.ToLower(spec.Keyword)) {
+		return fmt.Sprintf("response of %s contained keyword %q", member.GetName(), spec.Keyword)
+	}
+
+	if spec.MaxTokens > 0 {
+		if usage := t.eventingRecorder.GetTokenSummary(ctx); usage.TotalTokens >= spec.MaxTokens {
+			return fmt.Sprintf("token budget of %d reached with %d tokens used", spec.MaxTokens, usage.TotalTokens)
+		}
+	}
+
+	if spec.MaxDuration != nil && !t.startedAt.IsZero() {
+		if elapsed := time.Since(t.startedAt); elapsed >= spec.MaxDuration.Duration {
+			return fmt.Sprintf("duration limit of %s reached after %s", spec.MaxDuration.Duration, elapsed.Round(time.Millisecond))
+		}
+	}
+
+	if spec.NoProgress && t.repeatsPreviousResponse(member, content) {
+		return fmt.Sprintf("%s repeated its previous response", member.GetName())
+	}
+
+	return ""
+}
+
+// repeatsPreviousResponse records the latest response of a member and reports whether it is the same
+// as the one before. Members of parallel teams call it concurrently.
+func (t *teamRun) repeatsPreviousResponse(member TeamMember, content string) bool {
+	normalized := strings.Join(strings.Fields(content), " ")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastResponses == nil {
+		t.lastResponses = make(map[string]string)
+	}
+	previous, seen := t.lastResponses[member.GetName()]
+	t.lastResponses[member.GetName()] = normalized
+	return seen && normalized != "" && previous == normalized
+}
+
+// setTerminationReason keeps the first reason the team stopped for
+func (t *teamRun) setTerminationReason(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.terminationReason == "" {
+		t.terminationReason = reason
+	}
+}
@@ -0,0 +1,145 @@
+package genai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/mock"
+)
+
+// tokenSpendingTeamMember reports token usage for every turn, like a model call would
+type tokenSpendingTeamMember struct {
+	respondingTeamMember
+	tokens int64
+}
+
+func (m *tokenSpendingTeamMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	eventnoop.NewModelRecorder().AddTokens(ctx, m.tokens, 0, m.tokens)
+	return m.respondingTeamMember.Execute(ctx, userInput, history, memory, eventStream)
+}
+
+func newTerminationTestTeam(t *testing.T, termination *arkv1alpha1.TeamTerminationSpec, members ...TeamMember) (*Team, *mock.MockTeamRecorder) {
+	maxTurns := 6
+	recorder := mock.NewTeamRecorder()
+	terminationRegex, err := compileTerminationRegex(termination)
+	require.NoError(t, err)
+	return &Team{
+		Name:              "review",
+		Namespace:         "default",
+		Strategy:          "round-robin",
+		Members:           members,
+		MaxTurns:          &maxTurns,
+		Termination:       termination,
+		terminationRegex:  terminationRegex,
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}, recorder
+}
+
+func TestCompileTerminationRegex(t *testing.T) {
+	re, err := compileTerminationRegex(&arkv1alpha1.TeamTerminationSpec{Regex: `^FINAL ANSWER:`})
+	require.NoError(t, err)
+	require.True(t, re.MatchString("FINAL ANSWER: 42"))
+
+	re, err = compileTerminationRegex(&arkv1alpha1.TeamTerminationSpec{Keyword: "done"})
+	require.NoError(t, err)
+	require.Nil(t, re)
+
+	_, err = compileTerminationRegex(&arkv1alpha1.TeamTerminationSpec{Regex: `(unclosed`})
+	require.ErrorContains(t, err, `invalid regex "(unclosed"`)
+}
+
+func TestTeamTermination(t *testing.T) {
+	t.Run("stops on keyword", func(t *testing.T) {
+		writer := &respondingTeamMember{name: "writer", response: "draft"}
+		reviewer := &respondingTeamMember{name: "reviewer", response: "Looks good. APPROVED"}
+		team, recorder := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{Keyword: "approved"}, writer, reviewer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 2)
+		require.Equal(t, `response of reviewer contained keyword "approved"`, result.TerminationReason)
+		require.Equal(t, result.TerminationReason, recorder.Tracer.FindSpan("team.execution").Attributes["team.termination_reason"])
+	})
+
+	t.Run("stops on regex", func(t *testing.T) {
+		writer := &respondingTeamMember{name: "writer", response: "FINAL ANSWER: 42"}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{Regex: `^FINAL ANSWER:`}, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 1)
+		require.Contains(t, result.TerminationReason, "matched regex")
+	})
+
+	t.Run("stops when a member repeats itself", func(t *testing.T) {
+		writer := &respondingTeamMember{name: "writer", response: "same  draft"}
+		reviewer := &respondingTeamMember{name: "reviewer", response: "try again"}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{NoProgress: true}, writer, reviewer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3)
+		require.Equal(t, "writer repeated its previous response", result.TerminationReason)
+	})
+
+	t.Run("keeps the progress of concurrent runs apart", func(t *testing.T) {
+		writer := &scriptedTeamMember{name: "writer", responses: []string{"same draft"}}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{NoProgress: true}, writer)
+
+		results := make([]*ExecutionResult, 4)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+				require.NoError(t, err)
+				results[i] = result
+			}()
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			require.Len(t, result.Messages, 2, "every run sees only its own previous responses")
+			require.Equal(t, "writer repeated its previous response", result.TerminationReason)
+		}
+	})
+
+	t.Run("stops when the token budget is spent", func(t *testing.T) {
+		writer := &tokenSpendingTeamMember{respondingTeamMember: respondingTeamMember{name: "writer", response: "draft"}, tokens: 400}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{MaxTokens: 1000}, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 3)
+		require.Equal(t, "token budget of 1000 reached with 1200 tokens used", result.TerminationReason)
+	})
+
+	t.Run("stops when the duration limit is reached", func(t *testing.T) {
+		writer := &respondingTeamMember{name: "writer", response: "draft"}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{MaxDuration: &metav1.Duration{Duration: time.Nanosecond}}, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 1)
+		require.Contains(t, result.TerminationReason, "duration limit of 1ns reached")
+	})
+
+	t.Run("runs to max turns without a matching condition", func(t *testing.T) {
+		writer := &respondingTeamMember{name: "writer", response: "draft"}
+		team, _ := newTerminationTestTeam(t, &arkv1alpha1.TeamTerminationSpec{Keyword: "approved"}, writer)
+
+		result, err := team.Execute(context.Background(), NewUserMessage("write"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 6)
+		require.Empty(t, result.TerminationReason)
+	})
+}
@@ -0,0 +1,348 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/packages/param"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	VoteMethodMajority = "majority"
+	VoteMethodJudge    = "judge"
+)
+
+const (
+	// judgeMaxAttempts is how many times the judge is asked for valid scores before the team fails
+	judgeMaxAttempts = 3
+	maxJudgeScore    = 10
+)
+
+const defaultJudgeRubric = "Correctness, completeness and clarity of the response."
+
+// JudgeScore is the score the judge gives one candidate
+type JudgeScore struct {
+	Candidate string `json:"candidate"`
+	Score     int    `json:"score"`
+	Reason    string `json:"reason"`
+}
+
+// JudgeDecision is the structured response expected from the judge agent
+type JudgeDecision struct {
+	Scores []JudgeScore `json:"scores"`
+}
+
+func (t *teamRun) executeVote(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	members, err := t.voteMembers()
+	if err != nil {
+		return nil, err
+	}
+	t.eventStream = synchronizeEventStream(t.eventStream)
+
+	results := make([]parallelMemberResult, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(turn int, member TeamMember) {
+			defer wg.Done()
+			results[turn] = t.executeParallelTurn(ctx, member, userInput, history, turn)
+		}(i, member)
+	}
+	wg.Wait()
+
+	var newMessages []Message
+	var failures []error
+	candidates := make([]arkv1alpha1.VoteCandidate, len(results))
+	for i, result := range results {
+		newMessages = append(newMessages, result.messages...)
+		candidates[i].Member = result.member.GetName()
+		if result.err != nil {
+			candidates[i].Error = result.err.Error()
+			failures = append(failures, fmt.Errorf("member %s: %w", result.member.GetName(), result.err))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return newMessages, ctx.Err()
+	}
+	if len(failures) == len(results) {
+		return newMessages, fmt.Errorf("vote failed in team %s, no member answered: %w", t.FullName(), errors.Join(failures...))
+	}
+
+	method := t.voteMethod()
+	var winner int
+	if method == VoteMethodJudge {
+		winner, err = t.judgeCandidates(ctx, userInput, history, results, candidates, len(members))
+		if err != nil {
+			return newMessages, err
+		}
+	} else {
+		winner = majorityVote(results, candidates)
+	}
+	t.voteResult = &arkv1alpha1.VoteResult{Method: method, Winner: candidates[winner].Member, Candidates: candidates}
+
+	chosen := NewAssistantMessage(ExtractLastAssistantMessageContent(results[winner].messages))
+	chosen.OfAssistant.Name = param.NewOpt(candidates[winner].Member)
+	return append(newMessages, chosen), nil
+}
+
+// voteMembers returns the members that answer, in team order
+func (t *Team) voteMembers() ([]TeamMember, error) {
+	if t.Vote == nil {
+		return t.Members, nil
+	}
+
+	members, err := t.namedMembers(t.Vote.Members)
+	if err != nil {
+		return nil, fmt.Errorf("vote members of team %s: %w", t.FullName(), err)
+	}
+	return members, nil
+}
+
+func (t *Team) voteMethod() string {
+	if t.Vote == nil || t.Vote.Method == "" {
+		return VoteMethodMajority
+	}
+	return t.Vote.Method
+}
+
+// majorityVote scores each answer with the number of members that gave the same normalized answer,
+// and returns the index of the first member with the most common answer
+func majorityVote(results []parallelMemberResult, candidates []arkv1alpha1.VoteCandidate) int {
+	answers := make([]string, len(results))
+	counts := make(map[string]int)
+	for i, result := range results {
+		if result.err == nil {
+			answers[i] = normalizeAnswer(ExtractLastAssistantMessageContent(result.messages))
+			counts[answers[i]]++
+		}
+	}
+
+	winner := -1
+	for i, result := range results {
+		if result.err != nil {
+			continue
+		}
+		candidates[i].Score = counts[answers[i]]
+		if winner < 0 || candidates[i].Score > candidates[winner].Score {
+			winner = i
+		}
+	}
+	return winner
+}
+
+// normalizeAnswer makes answers that differ only in case, whitespace or trailing punctuation equal
+func normalizeAnswer(answer string) string {
+	answer = strings.Join(strings.Fields(strings.ToLower(answer)), " ")
+	return strings.TrimRight(answer, ".!?")
+}
+
+// judgeCandidates asks the judge agent to score the answers and returns the index of the first member
+// with the highest score. Answers are shown to the judge without member names.
+func (t *teamRun) judgeCandidates(ctx context.Context, userInput Message, history []Message, results []parallelMemberResult, candidates []arkv1alpha1.VoteCandidate, turn int) (int, error) {
+	judge, err := t.loadJudgeAgent(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []string
+	indexes := make(map[string]int)
+	for i, result := range results {
+		if result.err == nil {
+			id := fmt.Sprintf("candidate-%d", len(ids)+1)
+			ids = append(ids, id)
+			indexes[id] = i
+		}
+	}
+
+	judge.OutputSchema, err = judgeOutputSchema(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	judgeHistory := slices.Clone(history)
+	input := NewUserMessage(buildJudgeInput(userInput, t.Vote.Rubric, ids, indexes, results))
+	for attempt := 1; ; attempt++ {
+		content, err := t.executeJudgeTurn(ctx, judge, input, judgeHistory, turn)
+		if err != nil {
+			return 0, err
+		}
+
+		scores, err := parseJudgeDecision(content, ids)
+		if err == nil {
+			return applyJudgeScores(scores, ids, indexes, candidates), nil
+		}
+		if attempt >= judgeMaxAttempts {
+			return 0, fmt.Errorf("judge %s did not return valid scores for team %s: %w", judge.GetName(), t.FullName(), err)
+		}
+
+		judgeHistory = append(judgeHistory, input, NewAssistantMessage(content))
+		input = NewUserMessage(fmt.Sprintf("Invalid scores: %v. Respond with a score for every candidate.", err))
+		turn++
+	}
+}
+
+// applyJudgeScores copies the judge scores to the candidates and returns the index of the first
+// member with the highest score
+func applyJudgeScores(scores []JudgeScore, ids []string, indexes map[string]int, candidates []arkv1alpha1.VoteCandidate) int {
+	for _, score := range scores {
+		i := indexes[score.Candidate]
+		candidates[i].Score = score.Score
+		candidates[i].Reason = score.Reason
+	}
+
+	winner := -1
+	for _, id := range ids {
+		if i := indexes[id]; winner < 0 || candidates[i].Score > candidates[winner].Score {
+			winner = i
+		}
+	}
+	return winner
+}
+
+// executeJudgeTurn runs the judge and returns its response. The judge conversation is not part of the
+// team output.
+func (t *teamRun) executeJudgeTurn(ctx context.Context, judge *Agent, input Message, history []Message, turn int) (string, error) {
+	turnCtx, turnSpan := t.telemetryRecorder.StartTurn(ctx, turn, judge.GetName(), judge.GetType())
+	defer turnSpan.End()
+
+	operationData := map[string]string{
+		"teamName":   t.Name,
+		"strategy":   t.Strategy,
+		"turn":       fmt.Sprintf("%d", turn),
+		"memberName": judge.GetName(),
+	}
+	turnCtx = t.eventingRecorder.Start(turnCtx, "TeamJudge", fmt.Sprintf("Judging answers for team %s", t.Name), operationData)
+
+	messages := slices.Clone(history)
+	var judgeMessages []Message
+	err := t.executeMemberAndAccumulate(turnCtx, judge, input, &messages, &judgeMessages, turn)
+	if len(judgeMessages) > 0 {
+		t.telemetryRecorder.RecordTurnOutput(turnSpan, judgeMessages, len(judgeMessages))
+	}
+	if err != nil {
+		t.telemetryRecorder.RecordError(turnSpan, err)
+		t.eventingRecorder.Fail(turnCtx, "TeamJudge", fmt.Sprintf("Judging failed: %v", err), err, operationData)
+		return "", fmt.Errorf("judge %s failed in team %s: %w", judge.GetName(), t.FullName(), err)
+	}
+
+	content := ExtractLastAssistantMessageContent(judgeMessages)
+	operationData["scores"] = content
+	t.telemetryRecorder.RecordSuccess(turnSpan)
+	t.eventingRecorder.Complete(turnCtx, "TeamJudge", fmt.Sprintf("Judge %s responded", judge.GetName()), operationData)
+	return content, nil
+}
+
+func (t *Team) loadJudgeAgent(ctx context.Context) (*Agent, error) {
+	if t.Vote == nil || t.Vote.Judge == "" {
+		return nil, fmt.Errorf("judge agent must be specified")
+	}
+
+	agentName := t.Vote.Judge
+
+	var agentCRD arkv1alpha1.Agent
+	key := types.NamespacedName{Name: agentName, Namespace: t.Namespace}
+	if err := t.Client.Get(ctx, key, &agentCRD); err != nil {
+		return nil, fmt.Errorf("failed to get judge agent %s in namespace %s: %w", agentName, t.Namespace, err)
+	}
+
+	agent, err := MakeAgent(ctx, t.Client, &agentCRD, t.telemetry, t.eventing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create judge agent: %w", err)
+	}
+
+	return agent, nil
+}
+
+func buildJudgeInput(userInput Message, rubric string, ids []string, indexes map[string]int, results []parallelMemberResult) string {
+	if rubric == "" {
+		rubric = defaultJudgeRubric
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Score each candidate response to the request below from 0 (worst) to %d (best).\n\n", maxJudgeScore)
+	fmt.Fprintf(&b, "Rubric:\n%s\n\n", rubric)
+	fmt.Fprintf(&b, "Request:\n%s\n", messageText(userInput))
+	for _, id := range ids {
+		fmt.Fprintf(&b, "\n## %s\n%s\n", id, ExtractLastAssistantMessageContent(results[indexes[id]].messages))
+	}
+	b.WriteString(`
+Respond with JSON only: {"scores": [{"candidate": "...", "score": 0, "reason": "..."}]}`)
+	return b.String()
+}
+
+// judgeOutputSchema asks the judge for one score and reason per candidate
+func judgeOutputSchema(ids []string) (*runtime.RawExtension, error) {
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"scores": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"candidate": map[string]any{"type": "string", "enum": ids},
+						"score":     map[string]any{"type": "integer", "minimum": 0, "maximum": maxJudgeScore},
+						"reason":    map[string]any{"type": "string", "description": "Why the candidate received this score"},
+					},
+					"required":             []string{"candidate", "score", "reason"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"scores"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build judge output schema: %w", err)
+	}
+	return &runtime.RawExtension{Raw: schema}, nil
+}
+
+// parseJudgeDecision reads the judge scores and checks that every candidate is scored exactly once
+func parseJudgeDecision(content string, ids []string) ([]JudgeScore, error) {
+	var decision JudgeDecision
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &decision); err != nil {
+		return nil, fmt.Errorf("scores are not valid JSON: %w", err)
+	}
+
+	scored := make(map[string]bool, len(decision.Scores))
+	for _, score := range decision.Scores {
+		switch {
+		case !slices.Contains(ids, score.Candidate):
+			return nil, fmt.Errorf("unknown candidate %q, expected one of: %s", score.Candidate, strings.Join(ids, ", "))
+		case scored[score.Candidate]:
+			return nil, fmt.Errorf("candidate %s is scored more than once", score.Candidate)
+		case score.Score < 0 || score.Score > maxJudgeScore:
+			return nil, fmt.Errorf("score %d of candidate %s is not between 0 and %d", score.Score, score.Candidate, maxJudgeScore)
+		}
+		scored[score.Candidate] = true
+	}
+	for _, id := range ids {
+		if !scored[id] {
+			return nil, fmt.Errorf("candidate %s is not scored", id)
+		}
+	}
+	return decision.Scores, nil
+}
+
+// voteJSON returns the vote candidates as JSON for traces and events
+func (t *teamRun) voteJSON() string {
+	if t.voteResult == nil {
+		return ""
+	}
+	data, err := json.Marshal(t.voteResult.Candidates)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,153 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/mock"
+)
+
+func newVoteTestTeam(vote *arkv1alpha1.TeamVoteSpec, members ...TeamMember) (*Team, *mock.MockTeamRecorder) {
+	recorder := mock.NewTeamRecorder()
+	return &Team{
+		Name:              "reviewers",
+		Namespace:         "default",
+		Strategy:          "vote",
+		Members:           members,
+		Vote:              vote,
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}, recorder
+}
+
+func TestExecuteVote(t *testing.T) {
+	t.Run("returns the majority answer and the vote counts", func(t *testing.T) {
+		team, recorder := newVoteTestTeam(nil,
+			&respondingTeamMember{name: "gpt", response: "Paris"},
+			&respondingTeamMember{name: "claude", response: "Lyon"},
+			&respondingTeamMember{name: "gemini", response: " paris. "})
+
+		result, err := team.Execute(context.Background(), NewUserMessage("capital of France?"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 4)
+		require.Equal(t, "Paris", ExtractLastAssistantMessageContent(result.Messages))
+		require.Equal(t, "gpt", result.Messages[3].OfAssistant.Name.Value)
+
+		require.Equal(t, &arkv1alpha1.VoteResult{
+			Method: VoteMethodMajority,
+			Winner: "gpt",
+			Candidates: []arkv1alpha1.VoteCandidate{
+				{Member: "gpt", Score: 2},
+				{Member: "claude", Score: 1},
+				{Member: "gemini", Score: 2},
+			},
+		}, result.Vote)
+
+		span := recorder.Tracer.FindSpan("team.execution")
+		require.Equal(t, "gpt", span.Attributes["team.vote.winner"])
+		require.Contains(t, span.Attributes["team.vote.candidates"], `{"member":"claude","score":1}`)
+	})
+
+	t.Run("breaks ties by member order", func(t *testing.T) {
+		team, _ := newVoteTestTeam(nil,
+			&respondingTeamMember{name: "gpt", response: "yes"},
+			&respondingTeamMember{name: "claude", response: "no"})
+
+		result, err := team.Execute(context.Background(), NewUserMessage("question"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "gpt", result.Vote.Winner)
+	})
+
+	t.Run("votes only between the selected members", func(t *testing.T) {
+		team, _ := newVoteTestTeam(&arkv1alpha1.TeamVoteSpec{Members: []string{"claude", "gemini"}},
+			&respondingTeamMember{name: "gpt", response: "yes"},
+			&respondingTeamMember{name: "claude", response: "no"},
+			&respondingTeamMember{name: "gemini", response: "No"})
+
+		result, err := team.Execute(context.Background(), NewUserMessage("question"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "claude", result.Vote.Winner)
+		require.Len(t, result.Vote.Candidates, 2)
+	})
+
+	t.Run("leaves failed members out of the vote", func(t *testing.T) {
+		team, _ := newVoteTestTeam(nil,
+			&respondingTeamMember{name: "gpt", err: errors.New("rate limited")},
+			&respondingTeamMember{name: "claude", response: "no"})
+
+		result, err := team.Execute(context.Background(), NewUserMessage("question"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "claude", result.Vote.Winner)
+		require.Equal(t, arkv1alpha1.VoteCandidate{Member: "gpt", Error: "rate limited"}, result.Vote.Candidates[0])
+	})
+
+	t.Run("fails when no member answers", func(t *testing.T) {
+		team, _ := newVoteTestTeam(nil,
+			&respondingTeamMember{name: "gpt", err: errors.New("rate limited")},
+			&respondingTeamMember{name: "claude", err: errors.New("timeout")})
+
+		_, err := team.Execute(context.Background(), NewUserMessage("question"), nil, nil, nil)
+		require.ErrorContains(t, err, "vote failed in team default/reviewers, no member answered")
+		require.ErrorContains(t, err, "member claude: timeout")
+	})
+}
+
+func TestNormalizeAnswer(t *testing.T) {
+	require.Equal(t, "the answer is 42", normalizeAnswer("  The answer\n is   42!\n"))
+	require.Equal(t, "yes", normalizeAnswer("Yes."))
+	require.NotEqual(t, normalizeAnswer("yes"), normalizeAnswer("no"))
+}
+
+func TestParseJudgeDecision(t *testing.T) {
+	ids := []string{"candidate-1", "candidate-2"}
+
+	scores, err := parseJudgeDecision("```json\n"+`{"scores": [{"candidate": "candidate-2", "score": 9, "reason": "cites sources"}, {"candidate": "candidate-1", "score": 4, "reason": "vague"}]}`+"\n```", ids)
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+
+	tests := map[string]string{
+		`not json`: "scores are not valid JSON",
+		`{"scores": [{"candidate": "candidate-3", "score": 1}]}`:                                            "unknown candidate \"candidate-3\"",
+		`{"scores": [{"candidate": "candidate-1", "score": 1}, {"candidate": "candidate-1", "score": 2}]}`:  "candidate candidate-1 is scored more than once",
+		`{"scores": [{"candidate": "candidate-1", "score": 11}, {"candidate": "candidate-2", "score": 2}]}`: "score 11 of candidate candidate-1 is not between 0 and 10",
+		`{"scores": [{"candidate": "candidate-1", "score": 5}]}`:                                            "candidate candidate-2 is not scored",
+	}
+	for content, expected := range tests {
+		_, err := parseJudgeDecision(content, ids)
+		require.ErrorContains(t, err, expected, content)
+	}
+}
+
+func TestApplyJudgeScores(t *testing.T) {
+	candidates := []arkv1alpha1.VoteCandidate{{Member: "gpt", Error: "rate limited"}, {Member: "claude"}, {Member: "gemini"}}
+	ids := []string{"candidate-1", "candidate-2"}
+	indexes := map[string]int{"candidate-1": 1, "candidate-2": 2}
+
+	winner := applyJudgeScores([]JudgeScore{
+		{Candidate: "candidate-2", Score: 8, Reason: "complete"},
+		{Candidate: "candidate-1", Score: 6, Reason: "misses edge cases"},
+	}, ids, indexes, candidates)
+
+	require.Equal(t, 2, winner)
+	require.Equal(t, arkv1alpha1.VoteCandidate{Member: "claude", Score: 6, Reason: "misses edge cases"}, candidates[1])
+	require.Equal(t, arkv1alpha1.VoteCandidate{Member: "gemini", Score: 8, Reason: "complete"}, candidates[2])
+}
+
+func TestBuildJudgeInput(t *testing.T) {
+	results := []parallelMemberResult{
+		{member: &respondingTeamMember{name: "gpt"}, messages: []Message{NewAssistantMessage("Paris")}},
+		{member: &respondingTeamMember{name: "claude"}, messages: []Message{NewAssistantMessage("Lyon")}},
+	}
+	input := buildJudgeInput(NewUserMessage("capital of France?"), "", []string{"candidate-1", "candidate-2"}, map[string]int{"candidate-1": 0, "candidate-2": 1}, results)
+
+	require.Contains(t, input, "Rubric:\n"+defaultJudgeRubric)
+	require.Contains(t, input, "Request:\ncapital of France?")
+	require.Contains(t, input, "## candidate-1\nParis")
+	require.Contains(t, input, "## candidate-2\nLyon")
+	require.NotContains(t, input, "gpt", "member names are hidden from the judge")
+}
@@ -0,0 +1,90 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// errToolHiddenByPolicy is returned when a tool is not registered because the query tool policy hides it
+var errToolHiddenByPolicy = errors.New("tool hidden by query tool policy")
+
+// queryToolPolicy returns the tool policy of the query being executed, if any
+func queryToolPolicy(ctx context.Context) *arkv1alpha1.QueryToolPolicy {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok || query == nil {
+		return nil
+	}
+	return query.Spec.ToolPolicy
+}
+
+// ToolAllowedByPolicy reports whether a tool may be used under the given policy. exposedName is the
+// name the tool is registered under, which differs from the Tool resource name for partial tools.
+func ToolAllowedByPolicy(policy *arkv1alpha1.QueryToolPolicy, exposedName string, tool *arkv1alpha1.Tool) bool {
+	if policy == nil {
+		return true
+	}
+
+	for _, matcher := range policy.Deny {
+		if matchesToolMatcher(matcher, exposedName, tool) {
+			return false
+		}
+	}
+
+	if len(policy.Allow) == 0 {
+		return true
+	}
+	for _, matcher := range policy.Allow {
+		if matchesToolMatcher(matcher, exposedName, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesToolMatcher(matcher arkv1alpha1.QueryToolMatcher, exposedName string, tool *arkv1alpha1.Tool) bool {
+	if matcher.Name != "" && !matchesToolNamePattern(matcher.Name, exposedName) && !matchesToolNamePattern(matcher.Name, tool.Name) {
+		return false
+	}
+	if matcher.Type != "" && matcher.Type != tool.Spec.Type {
+		return false
+	}
+	if matcher.Annotations != nil && !matchesAnnotationHints(matcher.Annotations, tool.Spec.Annotations) {
+		return false
+	}
+	return true
+}
+
+func matchesToolNamePattern(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	if err != nil {
+		return pattern == name
+	}
+	return matched
+}
+
+func matchesAnnotationHints(hints *arkv1alpha1.ToolAnnotationHints, annotations *arkv1alpha1.ToolAnnotations) bool {
+	// Defaults for tools without annotations follow the MCP specification
+	effective := arkv1alpha1.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true}
+	if annotations != nil {
+		effective = *annotations
+	}
+
+	checks := []struct {
+		want   *bool
+		actual bool
+	}{
+		{hints.ReadOnlyHint, effective.ReadOnlyHint},
+		{hints.DestructiveHint, effective.DestructiveHint},
+		{hints.IdempotentHint, effective.IdempotentHint},
+		{hints.OpenWorldHint, effective.OpenWorldHint},
+	}
+	for _, check := range checks {
+		if check.want != nil && *check.want != check.actual {
+			return false
+		}
+	}
+	return true
+}
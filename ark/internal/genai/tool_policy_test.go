@@ -0,0 +1,153 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestToolAllowedByPolicy(t *testing.T) {
+	readOnlyTool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "github-search"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type:        ToolTypeMCP,
+			Annotations: &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true},
+		},
+	}
+	unannotatedTool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "create-ticket"},
+		Spec:       arkv1alpha1.ToolSpec{Type: ToolTypeHTTP},
+	}
+
+	tests := []struct {
+		name        string
+		policy      *arkv1alpha1.QueryToolPolicy
+		exposedName string
+		tool        *arkv1alpha1.Tool
+		expected    bool
+	}{
+		{
+			name:        "no policy allows everything",
+			exposedName: "create-ticket",
+			tool:        unannotatedTool,
+			expected:    true,
+		},
+		{
+			name:        "deny by name",
+			policy:      &arkv1alpha1.QueryToolPolicy{Deny: []arkv1alpha1.QueryToolMatcher{{Name: "create-ticket"}}},
+			exposedName: "create-ticket",
+			tool:        unannotatedTool,
+			expected:    false,
+		},
+		{
+			name:        "deny matches resource name of partial tool",
+			policy:      &arkv1alpha1.QueryToolPolicy{Deny: []arkv1alpha1.QueryToolMatcher{{Name: "create-ticket"}}},
+			exposedName: "open-bug",
+			tool:        unannotatedTool,
+			expected:    false,
+		},
+		{
+			name:        "allow by glob",
+			policy:      &arkv1alpha1.QueryToolPolicy{Allow: []arkv1alpha1.QueryToolMatcher{{Name: "github-*"}}},
+			exposedName: "github-search",
+			tool:        readOnlyTool,
+			expected:    true,
+		},
+		{
+			name:        "allow list excludes unmatched tools",
+			policy:      &arkv1alpha1.QueryToolPolicy{Allow: []arkv1alpha1.QueryToolMatcher{{Type: ToolTypeMCP}}},
+			exposedName: "create-ticket",
+			tool:        unannotatedTool,
+			expected:    false,
+		},
+		{
+			name: "read-only only",
+			policy: &arkv1alpha1.QueryToolPolicy{Allow: []arkv1alpha1.QueryToolMatcher{
+				{Annotations: &arkv1alpha1.ToolAnnotationHints{ReadOnlyHint: ptr.To(true)}},
+			}},
+			exposedName: "github-search",
+			tool:        readOnlyTool,
+			expected:    true,
+		},
+		{
+			name: "unannotated tools are treated as destructive",
+			policy: &arkv1alpha1.QueryToolPolicy{Deny: []arkv1alpha1.QueryToolMatcher{
+				{Annotations: &arkv1alpha1.ToolAnnotationHints{DestructiveHint: ptr.To(true)}},
+			}},
+			exposedName: "create-ticket",
+			tool:        unannotatedTool,
+			expected:    false,
+		},
+		{
+			name: "deny takes precedence over allow",
+			policy: &arkv1alpha1.QueryToolPolicy{
+				Allow: []arkv1alpha1.QueryToolMatcher{{Type: ToolTypeMCP}},
+				Deny:  []arkv1alpha1.QueryToolMatcher{{Name: "github-search", Type: ToolTypeMCP}},
+			},
+			exposedName: "github-search",
+			tool:        readOnlyTool,
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ToolAllowedByPolicy(tt.policy, tt.exposedName, tt.tool))
+		})
+	}
+}
+
+func TestRegisterToolsAppliesQueryToolPolicy(t *testing.T) {
+	tools := []client.Object{
+		&arkv1alpha1.Tool{
+			ObjectMeta: metav1.ObjectMeta{Name: "noop", Namespace: "default"},
+			Spec: arkv1alpha1.ToolSpec{
+				Type:        ToolTypeBuiltin,
+				Annotations: &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true},
+			},
+		},
+		&arkv1alpha1.Tool{
+			ObjectMeta: metav1.ObjectMeta{Name: "terminate", Namespace: "default"},
+			Spec:       arkv1alpha1.ToolSpec{Type: ToolTypeBuiltin},
+		},
+	}
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: arkv1alpha1.AgentSpec{
+			Tools: []arkv1alpha1.AgentTool{
+				{Type: AgentToolTypeBuiltIn, Name: "noop"},
+				{Type: AgentToolTypeBuiltIn, Name: "terminate"},
+			},
+		},
+	}
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: "default"},
+		Spec: arkv1alpha1.QuerySpec{
+			ToolPolicy: &arkv1alpha1.QueryToolPolicy{
+				Allow: []arkv1alpha1.QueryToolMatcher{
+					{Annotations: &arkv1alpha1.ToolAnnotationHints{ReadOnlyHint: ptr.To(true)}},
+				},
+			},
+		},
+	}
+	ctx := context.WithValue(context.Background(), QueryContextKey, query)
+
+	telemetryProvider := noop.NewProvider()
+	eventingProvider := eventnoop.NewProvider()
+	registry := NewToolRegistry(nil, telemetryProvider.ToolRecorder(), eventingProvider.ToolRecorder())
+
+	err := registry.registerTools(ctx, setupTestClientForTools(tools), agent, telemetryProvider, eventingProvider)
+	require.NoError(t, err)
+
+	definitions := registry.GetToolDefinitions()
+	require.Len(t, definitions, 1)
+	require.Equal(t, "noop", definitions[0].Name)
+}
@@ -35,6 +35,7 @@ type HTTPExecutor struct {
 	K8sClient     client.Client
 	ToolName      string
 	ToolNamespace string
+	Eventing      eventing.ToolRecorder
 }
 
 // Execute implements ToolExecutor interface for HTTP tools
@@ -172,6 +173,10 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult,
 
 	log.Info("HTTP request completed", "status", resp.StatusCode, "responseSize", len(body))
 
+	if httpSpec.Async != nil {
+		return h.pollAsyncResult(ctx, call, httpSpec.Async, httpClient, parsedURL, resp, body, req.Header)
+	}
+
 	return ToolResult{
 		ID:      call.ID,
 		Name:    call.Function.Name,
@@ -212,28 +217,46 @@ func (tr *ToolRegistry) GetToolDefinitions() []ToolDefinition {
 	return definitions
 }
 
+// GetToolType returns the type of the executor of a tool. Post-processing, partials and function
+// filters wrap the executor of the tool, so the executor they wrap determines the type.
 func (tr *ToolRegistry) GetToolType(toolName string) string {
 	executor, exists := tr.executors[toolName]
 	if !exists {
 		return "unknown"
 	}
 
-	switch executor.(type) {
+	switch unwrapToolExecutor(executor).(type) {
 	case *NoopExecutor:
 		return "builtin"
 	case *TerminateExecutor:
 		return "builtin"
+	case *HandoffExecutor:
+		return "builtin"
 	case *HTTPExecutor:
 		return "custom"
 	case *MCPExecutor:
 		return "mcp"
-	case *FilteredToolExecutor:
-		return "filtered"
 	default:
 		return "unknown"
 	}
 }
 
+// unwrapToolExecutor returns the executor that the wrappers of an executor run
+func unwrapToolExecutor(executor ToolExecutor) ToolExecutor {
+	for {
+		switch wrapper := executor.(type) {
+		case *PostProcessToolExecutor:
+			executor = wrapper.BaseExecutor
+		case *PartialToolExecutor:
+			executor = wrapper.BaseExecutor
+		case *FilteredToolExecutor:
+			executor = wrapper.BaseExecutor
+		default:
+			return executor
+		}
+	}
+}
+
 func (tr *ToolRegistry) ExecuteTool(ctx context.Context, call ToolCall) (ToolResult, error) {
 	executor, exists := tr.executors[call.Function.Name]
 	if !exists {
@@ -262,6 +285,8 @@ func (tr *ToolRegistry) ExecuteTool(ctx context.Context, call ToolCall) (ToolRes
 		if IsTerminateTeam(err) {
 			operationData["terminationMessage"] = "TerminateTeam"
 			tr.eventingRecorder.Complete(ctx, "ToolCall", "Tool execution completed with termination", operationData)
+		} else if IsHandoffTeam(err) {
+			tr.eventingRecorder.Complete(ctx, "ToolCall", "Tool execution completed with handoff", operationData)
 		} else {
 			tr.eventingRecorder.Fail(ctx, "ToolCall", fmt.Sprintf("Tool execution failed: %v", err), err, operationData)
 		}
@@ -370,6 +395,35 @@ func GetTerminateTool() ToolDefinition {
 	}
 }
 
+type HandoffExecutor struct {
+	Target string
+}
+
+func (h *HandoffExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: fmt.Sprintf("Transferred to %s", h.Target)}, &HandoffTeam{Target: h.Target}
+}
+
+// GetHandoffTool returns the tool that transfers control of a handoff team to the given member
+func GetHandoffTool(member TeamMember) ToolDefinition {
+	description := fmt.Sprintf("Transfer the conversation to %s, who continues with the full conversation history", member.GetName())
+	if member.GetDescription() != "" {
+		description = fmt.Sprintf("%s. %s: %s", description, member.GetName(), member.GetDescription())
+	}
+	return ToolDefinition{
+		Name:        HandoffToolName(member.GetName()),
+		Description: description,
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+	}
+}
+
+// HandoffToolName returns the transfer tool name for a member. Dots are not allowed in function names.
+func HandoffToolName(memberName string) string {
+	return HandoffToolPrefix + strings.ReplaceAll(memberName, ".", "_")
+}
+
 func (h *HTTPExecutor) getTimeout(timeoutStr string) time.Duration {
 	if timeoutStr == "" {
 		return 30 * time.Second
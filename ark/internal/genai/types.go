@@ -62,3 +62,20 @@ func IsTerminateTeam(err error) bool {
 	var terminateErr *TerminateTeam
 	return errors.As(err, &terminateErr)
 }
+
+// HandoffTeam is returned by a transfer tool to hand control to another member of a handoff team
+type HandoffTeam struct {
+	Target string
+}
+
+func (e *HandoffTeam) Error() string {
+	return "HandoffTeam: " + e.Target
+}
+
+func IsHandoffTeam(err error) bool {
+	if err == nil {
+		return false
+	}
+	var handoffErr *HandoffTeam
+	return errors.As(err, &handoffErr)
+}
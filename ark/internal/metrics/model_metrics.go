@@ -0,0 +1,41 @@
+/* Copyright 2025. McKinsey & Company */
+
+// Package metrics defines the Prometheus metrics of the controller. They are registered with the
+// controller-runtime registry and served on the manager metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var (
+	modelTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_model_tokens_total",
+		Help: "Tokens used by calls to a Model, by token type (prompt, cached_prompt, completion, reasoning). " +
+			"cached_prompt tokens are part of prompt and reasoning tokens part of completion, so the total is prompt plus completion.",
+	}, []string{"namespace", "model", "type"})
+
+	modelCost = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_model_cost_total",
+		Help: "Cost of calls to a Model with pricing",
+	}, []string{"namespace", "model", "currency"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(modelTokens, modelCost)
+}
+
+// RecordModelUsage adds the usage of a model call, and its cost if currency is set. The cached prompt
+// and reasoning types break down the prompt and completion tokens, as the usage reports them.
+func RecordModelUsage(namespace, model string, usage arkv1alpha1.TokenUsage, cost float64, currency string) {
+	modelTokens.WithLabelValues(namespace, model, "prompt").Add(float64(usage.PromptTokens))
+	modelTokens.WithLabelValues(namespace, model, "cached_prompt").Add(float64(usage.CachedPromptTokens))
+	modelTokens.WithLabelValues(namespace, model, "completion").Add(float64(usage.CompletionTokens))
+	modelTokens.WithLabelValues(namespace, model, "reasoning").Add(float64(usage.ReasoningTokens))
+	if currency != "" {
+		modelCost.WithLabelValues(namespace, model, currency).Add(cost)
+	}
+}
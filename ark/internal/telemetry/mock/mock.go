@@ -4,6 +4,7 @@ package mock
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"mckinsey.com/ark/internal/telemetry"
@@ -242,6 +243,13 @@ func (r *MockQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens,
 	)
 }
 
+func (r *MockQueryRecorder) RecordCost(span telemetry.Span, cost float64, currency string) {
+	span.SetAttributes(
+		telemetry.Float64(telemetry.AttrCost, cost),
+		telemetry.String(telemetry.AttrCostCurrency, currency),
+	)
+}
+
 func (r *MockQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
@@ -362,6 +370,48 @@ func (r *MockTeamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, c
 	)
 }
 
+func (r *MockTeamRecorder) RecordHandoffChain(span telemetry.Span, chain []string) {
+	span.SetAttributes(
+		telemetry.String("team.handoff_chain", strings.Join(chain, " -> ")),
+	)
+}
+
+func (r *MockTeamRecorder) RecordSelectorDecision(span telemetry.Span, reason string, attempts int) {
+	span.SetAttributes(
+		telemetry.String("turn.selector.reason", reason),
+		telemetry.Int("turn.selector.attempts", attempts),
+	)
+}
+
+func (r *MockTeamRecorder) RecordTermination(span telemetry.Span, reason string) {
+	span.SetAttributes(telemetry.String("team.termination_reason", reason))
+}
+
+func (r *MockTeamRecorder) RecordPlan(span telemetry.Span, plan string, revisions int) {
+	span.SetAttributes(
+		telemetry.String("team.plan", plan),
+		telemetry.Int("team.plan.revisions", revisions),
+	)
+}
+
+func (r *MockTeamRecorder) RecordPlanStep(span telemetry.Span, stepID, status string) {
+	span.SetAttributes(
+		telemetry.String("turn.plan.step_id", stepID),
+		telemetry.String("turn.plan.step_status", status),
+	)
+}
+
+func (r *MockTeamRecorder) RecordVote(span telemetry.Span, winner, candidates string) {
+	span.SetAttributes(
+		telemetry.String("team.vote.winner", winner),
+		telemetry.String("team.vote.candidates", candidates),
+	)
+}
+
+func (r *MockTeamRecorder) RecordResume(span telemetry.Span, turn int) {
+	span.SetAttributes(telemetry.Int("team.resumed_from_turn", turn))
+}
+
 func (r *MockTeamRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
@@ -4,6 +4,7 @@ package noop
 
 import (
 	"context"
+	"time"
 
 	"mckinsey.com/ark/internal/telemetry"
 )
@@ -54,6 +55,8 @@ func (r *noopQueryRecorder) RecordRootOutput(span telemetry.Span, content string
 func (r *noopQueryRecorder) RecordInput(span telemetry.Span, content string)      {} //nolint:revive
 func (r *noopQueryRecorder) RecordOutput(span telemetry.Span, content string)     {} //nolint:revive
 func (r *noopQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
+} //nolint:revive
+func (r *noopQueryRecorder) RecordCost(span telemetry.Span, cost float64, currency string) {
 }                                                                                  //nolint:revive
 func (r *noopQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {} //nolint:revive
 func (r *noopQueryRecorder) RecordSuccess(span telemetry.Span)                     {} //nolint:revive
@@ -105,6 +108,14 @@ func (r *noopModelRecorder) RecordOutput(span telemetry.Span, output any)  {} //
 func (r *noopModelRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
 } //nolint:revive
 func (r *noopModelRecorder) RecordModelDetails(span telemetry.Span, modelName, modelType string) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordReasoningTokens(span telemetry.Span, reasoningTokens int64) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordRateLimit(span telemetry.Span, waited time.Duration, reservedTokens int64) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordCost(span telemetry.Span, cost float64, currency string) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordRoute(span telemetry.Span, model, reason string, candidates []string) {
 }                                                                       //nolint:revive
 func (r *noopModelRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
 func (r *noopModelRecorder) RecordError(span telemetry.Span, err error) {} //nolint:revive
@@ -140,9 +151,16 @@ func (r *noopTeamRecorder) StartTurn(ctx context.Context, turn int, memberName,
 func (r *noopTeamRecorder) RecordTurnOutput(span telemetry.Span, messages any, messageCount int) {
 } //nolint:revive
 func (r *noopTeamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
-}                                                                      //nolint:revive
-func (r *noopTeamRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
-func (r *noopTeamRecorder) RecordError(span telemetry.Span, err error) {} //nolint:revive
+}                                                                                                   //nolint:revive
+func (r *noopTeamRecorder) RecordHandoffChain(span telemetry.Span, chain []string)                  {} //nolint:revive
+func (r *noopTeamRecorder) RecordSelectorDecision(span telemetry.Span, reason string, attempts int) {} //nolint:revive
+func (r *noopTeamRecorder) RecordTermination(span telemetry.Span, reason string)                    {} //nolint:revive
+func (r *noopTeamRecorder) RecordPlan(span telemetry.Span, plan string, revisions int)              {} //nolint:revive
+func (r *noopTeamRecorder) RecordPlanStep(span telemetry.Span, stepID, status string)               {} //nolint:revive
+func (r *noopTeamRecorder) RecordVote(span telemetry.Span, winner, candidates string)               {} //nolint:revive
+func (r *noopTeamRecorder) RecordResume(span telemetry.Span, turn int)                              {} //nolint:revive
+func (r *noopTeamRecorder) RecordSuccess(span telemetry.Span)                                       {} //nolint:revive
+func (r *noopTeamRecorder) RecordError(span telemetry.Span, err error)                              {} //nolint:revive
 
 type noopProvider struct{}
 
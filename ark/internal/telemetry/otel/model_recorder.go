@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/openai/openai-go"
 	"mckinsey.com/ark/internal/telemetry"
@@ -170,6 +172,37 @@ func (r *modelRecorder) RecordModelDetails(span telemetry.Span, modelName, model
 	)
 }
 
+func (r *modelRecorder) RecordReasoningTokens(span telemetry.Span, reasoningTokens int64) {
+	span.SetAttributes(telemetry.Int64(telemetry.AttrTokensReasoning, reasoningTokens))
+}
+
+func (r *modelRecorder) RecordRateLimit(span telemetry.Span, waited time.Duration, reservedTokens int64) {
+	span.SetAttributes(
+		telemetry.Int64(telemetry.AttrRateLimitWaitMs, waited.Milliseconds()),
+		telemetry.Int64(telemetry.AttrRateLimitReservedTokens, reservedTokens),
+	)
+	if waited > 0 {
+		span.AddEvent("rate_limit.wait", telemetry.Int64(telemetry.AttrRateLimitWaitMs, waited.Milliseconds()))
+	}
+}
+
+func (r *modelRecorder) RecordCost(span telemetry.Span, cost float64, currency string) {
+	span.SetAttributes(
+		telemetry.Float64(telemetry.AttrCost, cost),
+		telemetry.String(telemetry.AttrCostCurrency, currency),
+	)
+}
+
+func (r *modelRecorder) RecordRoute(span telemetry.Span, model, reason string, candidates []string) {
+	attributes := []telemetry.Attribute{
+		telemetry.String(telemetry.AttrRouterModel, model),
+		telemetry.String(telemetry.AttrRouterReason, reason),
+		telemetry.String(telemetry.AttrRouterCandidates, strings.Join(candidates, ",")),
+	}
+	span.SetAttributes(attributes...)
+	span.AddEvent("router.route", attributes...)
+}
+
 func (r *modelRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
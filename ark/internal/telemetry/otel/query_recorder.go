@@ -80,6 +80,13 @@ func (r *queryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+func (r *queryRecorder) RecordCost(span telemetry.Span, cost float64, currency string) {
+	span.SetAttributes(
+		telemetry.Float64(telemetry.AttrCost, cost),
+		telemetry.String(telemetry.AttrCostCurrency, currency),
+	)
+}
+
 func (r *queryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
@@ -5,6 +5,7 @@ package otel
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"mckinsey.com/ark/internal/telemetry"
 )
@@ -76,6 +77,49 @@ func (r *teamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, compl
 	)
 }
 
+func (r *teamRecorder) RecordHandoffChain(span telemetry.Span, chain []string) {
+	span.SetAttributes(
+		telemetry.String("team.handoff_chain", strings.Join(chain, " -> ")),
+		telemetry.Int("team.handoff_count", len(chain)-1),
+	)
+}
+
+func (r *teamRecorder) RecordSelectorDecision(span telemetry.Span, reason string, attempts int) {
+	span.SetAttributes(
+		telemetry.String("turn.selector.reason", reason),
+		telemetry.Int("turn.selector.attempts", attempts),
+	)
+}
+
+func (r *teamRecorder) RecordTermination(span telemetry.Span, reason string) {
+	span.SetAttributes(telemetry.String("team.termination_reason", reason))
+}
+
+func (r *teamRecorder) RecordPlan(span telemetry.Span, plan string, revisions int) {
+	span.SetAttributes(
+		telemetry.String("team.plan", plan),
+		telemetry.Int("team.plan.revisions", revisions),
+	)
+}
+
+func (r *teamRecorder) RecordPlanStep(span telemetry.Span, stepID, status string) {
+	span.SetAttributes(
+		telemetry.String("turn.plan.step_id", stepID),
+		telemetry.String("turn.plan.step_status", status),
+	)
+}
+
+func (r *teamRecorder) RecordVote(span telemetry.Span, winner, candidates string) {
+	span.SetAttributes(
+		telemetry.String("team.vote.winner", winner),
+		telemetry.String("team.vote.candidates", candidates),
+	)
+}
+
+func (r *teamRecorder) RecordResume(span telemetry.Span, turn int) {
+	span.SetAttributes(telemetry.Int("team.resumed_from_turn", turn))
+}
+
 func (r *teamRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
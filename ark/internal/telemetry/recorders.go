@@ -4,6 +4,7 @@ package telemetry
 
 import (
 	"context"
+	"time"
 )
 
 // QueryRecorder provides domain-specific telemetry for query execution.
@@ -30,6 +31,9 @@ type QueryRecorder interface {
 	// RecordTokenUsage records LLM token consumption.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordCost records the cost of the models with pricing.
+	RecordCost(span Span, cost float64, currency string)
+
 	// RecordSessionID associates a span with a session for multi-query tracking.
 	RecordSessionID(span Span, sessionID string)
 
@@ -87,6 +91,18 @@ type ModelRecorder interface {
 	// RecordModelDetails records model configuration. Provider is extracted from modelType.
 	RecordModelDetails(span Span, modelName, modelType string)
 
+	// RecordReasoningTokens records the completion tokens a reasoning model used before answering.
+	RecordReasoningTokens(span Span, reasoningTokens int64)
+
+	// RecordRateLimit records the time a call waited for the model limits and the tokens it reserved.
+	RecordRateLimit(span Span, waited time.Duration, reservedTokens int64)
+
+	// RecordCost records the cost of the model call, for models with pricing.
+	RecordCost(span Span, cost float64, currency string)
+
+	// RecordRoute records the model a router sent the call to, why, and the models that matched the call.
+	RecordRoute(span Span, model, reason string, candidates []string)
+
 	// RecordSuccess marks a span as successfully completed.
 	RecordSuccess(span Span)
 
@@ -125,6 +141,27 @@ type TeamRecorder interface {
 	// RecordTokenUsage records token consumption for team execution.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordHandoffChain records the members that handed off control, in order.
+	RecordHandoffChain(span Span, chain []string)
+
+	// RecordSelectorDecision records why the selector agent chose the member of a turn.
+	RecordSelectorDecision(span Span, reason string, attempts int)
+
+	// RecordTermination records why a team stopped early.
+	RecordTermination(span Span, reason string)
+
+	// RecordPlan records the steps of a plan team and their statuses, as JSON.
+	RecordPlan(span Span, plan string, revisions int)
+
+	// RecordPlanStep records the plan step a turn executed and how it ended.
+	RecordPlanStep(span Span, stepID, status string)
+
+	// RecordVote records the winner of a vote team and the candidate scores, as JSON.
+	RecordVote(span Span, winner, candidates string)
+
+	// RecordResume records the turn a team continued from after an interrupted execution.
+	RecordResume(span Span, turn int)
+
 	// RecordSuccess marks a span as successfully completed.
 	RecordSuccess(span Span)
 
@@ -159,10 +196,24 @@ const (
 	AttrModelProvider = "llm.model.provider"
 	AttrModelType     = "llm.model.type"
 
+	// Model rate limit attributes
+	AttrRateLimitWaitMs         = "ark.rate_limit.wait_ms"
+	AttrRateLimitReservedTokens = "ark.rate_limit.reserved_tokens"
+
+	// Model router attributes
+	AttrRouterModel      = "ark.router.model"
+	AttrRouterReason     = "ark.router.reason"
+	AttrRouterCandidates = "ark.router.candidates"
+
 	// Token usage (aligned with OpenTelemetry GenAI conventions)
 	AttrTokensPrompt     = "gen_ai.usage.input_tokens"
 	AttrTokensCompletion = "gen_ai.usage.output_tokens"
 	AttrTokensTotal      = "gen_ai.usage.total_tokens"
+	AttrTokensReasoning  = "gen_ai.usage.reasoning_tokens"
+
+	// Cost of models with pricing
+	AttrCost         = "gen_ai.usage.cost"
+	AttrCostCurrency = "gen_ai.usage.cost_currency"
 
 	// Langfuse-specific attributes for compatibility
 	AttrLangfuseModel    = "model"
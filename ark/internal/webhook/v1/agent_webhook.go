@@ -5,14 +5,19 @@ package v1
 import (
 	"context"
 	"fmt"
+	"slices"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
 )
 
 // SetupAgentWebhookWithManager registers the webhook for Agent in the manager.
@@ -99,6 +104,10 @@ func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1al
 		return warnings, err
 	}
 
+	if _, err := genai.ParseGenerationParameters(agent.Spec.Generation); err != nil {
+		return warnings, fmt.Errorf("spec.generation: %w", err)
+	}
+
 	for i, tool := range agent.Spec.Tools {
 		toolWarnings, err := v.validateTool(i, tool)
 		if err != nil {
@@ -107,9 +116,54 @@ func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1al
 		warnings = append(warnings, toolWarnings...)
 	}
 
+	if err := v.validateToolNameCollisions(ctx, agent, nil); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidateNoReferenceCycle(ctx, agent); err != nil {
+		return warnings, err
+	}
+
+	warnings = append(warnings, v.modelCapabilityWarnings(ctx, agent)...)
+
 	return warnings, nil
 }
 
+// modelCapabilityWarnings warns when the model of the agent is an embedding model or was detected
+// to lack a capability the agent needs. Models without detected capabilities are not checked. Vision
+// is not checked, as whether an agent receives images depends on the queries, not on its spec.
+func (v *AgentCustomValidator) modelCapabilityWarnings(ctx context.Context, agent *arkv1alpha1.Agent) admission.Warnings {
+	if agent.Spec.ModelRef == nil {
+		return nil
+	}
+	namespace := agent.Spec.ModelRef.Namespace
+	if namespace == "" {
+		namespace = agent.Namespace
+	}
+
+	var model arkv1alpha1.Model
+	key := types.NamespacedName{Name: agent.Spec.ModelRef.Name, Namespace: namespace}
+	if err := v.Client.Get(ctx, key, &model); err != nil {
+		return nil
+	}
+	if model.Spec.Task == genai.ModelTaskEmbedding {
+		return admission.Warnings{fmt.Sprintf("model '%s' is an embedding model, agents need a completion model", model.Name)}
+	}
+	if model.Status.Capabilities == nil {
+		return nil
+	}
+
+	var warnings admission.Warnings
+	capabilities := model.Status.Capabilities
+	if len(agent.Spec.Tools) > 0 && capabilities.ToolCalling != nil && !*capabilities.ToolCalling {
+		warnings = append(warnings, fmt.Sprintf("agent has tools but model '%s' does not support tool calling", model.Name))
+	}
+	if agent.Spec.OutputSchema != nil && capabilities.StructuredOutput != nil && !*capabilities.StructuredOutput {
+		warnings = append(warnings, fmt.Sprintf("agent has an output schema but model '%s' does not support structured output", model.Name))
+	}
+	return warnings
+}
+
 func (v *AgentCustomValidator) validateAgentModel(ctx context.Context, agent *arkv1alpha1.Agent) error {
 	// Model validation is now handled at runtime via status conditions
 	// Agents without valid models will show as Available: False
@@ -139,6 +193,146 @@ func (v *AgentCustomValidator) validateCustomTool(tool arkv1alpha1.AgentTool, ha
 	return warnings, nil
 }
 
+func (v *AgentCustomValidator) validateMCPServerTools(tool arkv1alpha1.AgentTool, index int) error {
+	if tool.MCPServer == nil || tool.MCPServer.Name == "" {
+		return fmt.Errorf("tool[%d]: mcp tools must specify mcpServer.name", index)
+	}
+	if tool.Selector != nil {
+		return fmt.Errorf("tool[%d]: mcp tools cannot specify a selector", index)
+	}
+	// MCP server tools are expanded at runtime, so the MCPServer may be created after the agent
+	return validateExpandedTool(tool, index)
+}
+
+func (v *AgentCustomValidator) validateSelectorTools(tool arkv1alpha1.AgentTool, index int) error {
+	if tool.Selector == nil {
+		return fmt.Errorf("tool[%d]: selector tools must specify a selector", index)
+	}
+	if tool.MCPServer != nil {
+		return fmt.Errorf("tool[%d]: selector tools cannot specify mcpServer", index)
+	}
+	if len(tool.Selector.MatchLabels) == 0 && len(tool.Selector.MatchExpressions) == 0 {
+		return fmt.Errorf("tool[%d]: selector must specify matchLabels or matchExpressions, an empty selector would match every tool", index)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(tool.Selector); err != nil {
+		return fmt.Errorf("tool[%d]: invalid selector: %v", index, err)
+	}
+	return validateExpandedTool(tool, index)
+}
+
+func (v *AgentCustomValidator) validateToolSetTools(tool arkv1alpha1.AgentTool, index int) error {
+	if tool.ToolSet == nil || tool.ToolSet.Name == "" {
+		return fmt.Errorf("tool[%d]: toolset tools must specify toolSet.name", index)
+	}
+	if tool.MCPServer != nil || tool.Selector != nil {
+		return fmt.Errorf("tool[%d]: toolset tools cannot specify mcpServer or selector", index)
+	}
+	if len(tool.Functions) > 0 {
+		return fmt.Errorf("tool[%d]: toolset tools cannot specify functions", index)
+	}
+	return validateExpandedTool(tool, index)
+}
+
+// validateToolNameCollisions rejects agents where the same tool name is provided more than once,
+// either directly or through referenced ToolSets. When override is set, it is used in place of the
+// stored ToolSet with the same name so ToolSet updates can be checked against dependent agents.
+// ToolSets that do not exist yet are skipped; the agent reports them as unavailable at runtime.
+//
+// MCP server and selector entries are checked once all tools are resolved. A resolved Tool may share
+// its name with a custom tool listed directly only when that entry uses the same Tool, never with a
+// built-in tool, and a Tool attached by several entries must be filtered to the same functions by
+// each of them.
+func (v *ResourceValidator) validateToolNameCollisions(ctx context.Context, agent *arkv1alpha1.Agent, override *arkv1alpha1.ToolSet) error {
+	type toolSource struct {
+		tool   arkv1alpha1.AgentTool
+		source string
+	}
+	var sourced []toolSource
+	for _, tool := range agent.Spec.Tools {
+		sourced = append(sourced, toolSource{tool, fmt.Sprintf("agent '%s'", agent.Name)})
+	}
+
+	for _, tool := range agent.Spec.Tools {
+		if tool.Type != "toolset" || tool.ToolSet == nil {
+			continue
+		}
+
+		toolSet := override
+		if toolSet == nil || toolSet.Name != tool.ToolSet.Name {
+			toolSet = &arkv1alpha1.ToolSet{}
+			key := types.NamespacedName{Name: tool.ToolSet.Name, Namespace: agent.Namespace}
+			if err := v.Client.Get(ctx, key, toolSet); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return fmt.Errorf("failed to get toolset '%s' in namespace '%s': %v", tool.ToolSet.Name, agent.Namespace, err)
+				}
+				continue
+			}
+		}
+
+		for _, setTool := range toolSet.Spec.Tools {
+			sourced = append(sourced, toolSource{setTool, fmt.Sprintf("toolset '%s'", toolSet.Name)})
+		}
+	}
+
+	explicit := make(map[string]toolSource)
+	for _, entry := range sourced {
+		if entry.tool.Type != "built-in" && entry.tool.Type != "custom" {
+			continue
+		}
+		if existing, exists := explicit[entry.tool.Name]; exists {
+			return fmt.Errorf("tool name '%s' is provided by both %s and %s", entry.tool.Name, existing.source, entry.source)
+		}
+		explicit[entry.tool.Name] = entry
+	}
+
+	expanded := make(map[string]toolSource)
+	for _, entry := range sourced {
+		if entry.tool.Type != "mcp" && entry.tool.Type != "selector" {
+			continue
+		}
+		tools, err := genai.ListExpandedTools(ctx, v.Client, entry.tool, agent.Namespace)
+		if err != nil {
+			return err
+		}
+		if entry.tool.Type == "mcp" {
+			entry.source = fmt.Sprintf("mcp server '%s' in %s", entry.tool.MCPServer.Name, entry.source)
+		} else {
+			entry.source = fmt.Sprintf("selector in %s", entry.source)
+		}
+		for _, resolved := range tools {
+			if existing, exists := explicit[resolved.Name]; exists {
+				if existing.tool.Type != "custom" || existing.tool.GetToolCRDName() != resolved.Name {
+					return fmt.Errorf("tool name '%s' is provided by both %s and %s", resolved.Name, existing.source, entry.source)
+				}
+				continue
+			}
+			if existing, exists := expanded[resolved.Name]; exists {
+				if !slices.Equal(existing.tool.Functions, entry.tool.Functions) {
+					return fmt.Errorf("tool '%s' is attached by both %s and %s with different functions", resolved.Name, existing.source, entry.source)
+				}
+				continue
+			}
+			expanded[resolved.Name] = entry
+		}
+	}
+
+	return nil
+}
+
+// validateExpandedTool rejects per-tool settings on tool entries that expand to several tools
+func validateExpandedTool(tool arkv1alpha1.AgentTool, index int) error {
+	if tool.Name != "" {
+		return fmt.Errorf("tool[%d]: %s tools cannot specify a name", index, tool.Type)
+	}
+	if tool.Description != "" {
+		return fmt.Errorf("tool[%d]: %s tools cannot specify a description", index, tool.Type)
+	}
+	if tool.Partial != nil {
+		return fmt.Errorf("tool[%d]: %s tools cannot specify partial", index, tool.Type)
+	}
+	return nil
+}
+
 func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentTool) (admission.Warnings, error) {
 	var warnings admission.Warnings
 	hasName := tool.Name != ""
@@ -150,8 +344,20 @@ func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentToo
 		}
 	case "custom":
 		return v.validateCustomTool(tool, hasName, index)
+	case "mcp":
+		if err := v.validateMCPServerTools(tool, index); err != nil {
+			return warnings, err
+		}
+	case "selector":
+		if err := v.validateSelectorTools(tool, index); err != nil {
+			return warnings, err
+		}
+	case "toolset":
+		if err := v.validateToolSetTools(tool, index); err != nil {
+			return warnings, err
+		}
 	default:
-		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, custom", index, tool.Type)
+		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, custom, mcp, selector, toolset", index, tool.Type)
 	}
 
 	return warnings, nil
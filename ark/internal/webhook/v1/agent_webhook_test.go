@@ -97,6 +97,207 @@ var _ = Describe("Agent Webhook", func() {
 		})
 	})
 
+	Context("When validating MCP server and selector tools", func() {
+		It("Should allow tools attached from an MCP server", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{
+				Type:      "mcp",
+				MCPServer: &arkv1alpha1.AgentToolMCPServer{Name: "github", Exclude: []string{"delete_repo"}},
+			}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject mcp tools without a server name", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "mcp"}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("mcpServer.name"))
+		})
+
+		It("Should reject selector tools with an invalid selector", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{
+				Type: "selector",
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "category", Operator: "Unknown"},
+				}},
+			}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid selector"))
+		})
+
+		It("Should reject an empty selector", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{
+				Type:     "selector",
+				Selector: &metav1.LabelSelector{},
+			}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("empty selector"))
+		})
+
+		It("Should reject a selected tool that collides with a renamed partial tool", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "default", Labels: map[string]string{"category": "search"}},
+				Spec:       arkv1alpha1.ToolSpec{Type: "http"},
+			})).To(Succeed())
+
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{
+				{Type: "custom", Name: "search", Partial: &arkv1alpha1.ToolPartial{Name: "web-search"}},
+				{Type: "selector", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "search"}}},
+			}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tool name 'search' is provided by both agent 'test-agent' and selector in agent 'test-agent'"))
+
+			agent.Spec.Tools[0].Partial = nil
+			_, err = validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a selected tool named like a built-in tool", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "terminate", Namespace: "default", Labels: map[string]string{"category": "control"}},
+				Spec:       arkv1alpha1.ToolSpec{Type: "http"},
+			})).To(Succeed())
+
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{
+				{Type: "built-in", Name: "terminate"},
+				{Type: "selector", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "control"}}},
+			}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tool name 'terminate' is provided by both agent 'test-agent' and selector in agent 'test-agent'"))
+		})
+
+		It("Should reject a tool attached by an MCP server and a selector with different functions", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "github-search",
+					Namespace: "default",
+					Labels:    map[string]string{"mcp/server": "github", "category": "search"},
+				},
+				Spec: arkv1alpha1.ToolSpec{Type: "mcp"},
+			})).To(Succeed())
+
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{
+				{Type: "mcp", MCPServer: &arkv1alpha1.AgentToolMCPServer{Name: "github"}},
+				{Type: "selector", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "search"}}},
+			}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+
+			agent.Spec.Tools[1].Functions = []arkv1alpha1.ToolFunction{{Name: "search"}}
+			_, err = validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tool 'github-search' is attached by both mcp server 'github' in agent 'test-agent' and selector in agent 'test-agent' with different functions"))
+		})
+
+		It("Should reject partial on selector tools", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{
+				Type:     "selector",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "weather"}},
+				Partial:  &arkv1alpha1.ToolPartial{Name: "weather"},
+			}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot specify partial"))
+		})
+	})
+
+	Context("When validating generation parameters", func() {
+		It("Should allow generation overrides", func() {
+			temperature := "0"
+			agent.Spec.Generation = &arkv1alpha1.GenerationParameters{Temperature: &temperature}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a penalty out of range", func() {
+			penalty := "-3"
+			agent.Spec.Generation = &arkv1alpha1.GenerationParameters{PresencePenalty: &penalty}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.generation: presencePenalty must be between -2 and 2"))
+		})
+	})
+
+	Context("When validating model capabilities", func() {
+		createModel := func(capabilities *arkv1alpha1.ModelCapabilities) {
+			model := &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+				Spec:       arkv1alpha1.ModelSpec{Type: genai.ModelTypeOpenAI, Model: arkv1alpha1.ValueSource{Value: "gpt-4o"}},
+				Status:     arkv1alpha1.ModelStatus{Capabilities: capabilities},
+			}
+			Expect(validator.Client.Create(ctx, model)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "default"}
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "built-in", Name: "noop"}}
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type":"object"}`)}
+		})
+
+		It("Should warn when the model lacks capabilities the agent needs", func() {
+			unsupported := false
+			createModel(&arkv1alpha1.ModelCapabilities{ToolCalling: &unsupported, StructuredOutput: &unsupported})
+
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"agent has tools but model 'default' does not support tool calling",
+				"agent has an output schema but model 'default' does not support structured output",
+			))
+		})
+
+		It("Should not warn when capabilities are unknown or supported", func() {
+			supported := true
+			createModel(&arkv1alpha1.ModelCapabilities{ToolCalling: &supported})
+
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn when the model is an embedding model", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+				Spec: arkv1alpha1.ModelSpec{
+					Type:  genai.ModelTypeOpenAI,
+					Task:  genai.ModelTaskEmbedding,
+					Model: arkv1alpha1.ValueSource{Value: "text-embedding-3-small"},
+				},
+			})).To(Succeed())
+
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("model 'default' is an embedding model, agents need a completion model"))
+		})
+	})
+
+	Context("When validating reference cycles", func() {
+		It("Should reject selector tools that call the agent back", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "delegate", Namespace: "default", Labels: map[string]string{"category": "delegation"}},
+				Spec:       arkv1alpha1.ToolSpec{Type: "agent", Agent: &arkv1alpha1.AgentToolRef{Name: "test-agent"}},
+			})).To(Succeed())
+
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{
+				Type:     "selector",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"category": "delegation"}},
+			}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("reference cycle detected: agent/test-agent -> tool/delegate -> agent/test-agent"))
+		})
+
+		It("Should allow custom tools that do not exist yet", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "custom", Name: "not-created-yet"}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("When defaulting agent model", func() {
 		var defaulter *AgentCustomDefaulter
 
@@ -5,8 +5,10 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -75,6 +77,9 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 	modellog.Info("Validating Model", "name", model.GetName(), "namespace", model.GetNamespace())
 
 	// Validate model field ValueSource
+	if model.Spec.Type != genai.ModelTypeRouter && model.Spec.Model.Value == "" && model.Spec.Model.ValueFrom == nil {
+		return nil, fmt.Errorf("spec.model is required for %s models", model.Spec.Type)
+	}
 	if err := v.validateValueSource(ctx, &model.Spec.Model, model.GetNamespace(), "spec.model"); err != nil {
 		return nil, err
 	}
@@ -84,11 +89,46 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 		return nil, err
 	}
 
+	if _, err := genai.ParseGenerationParameters(model.Spec.Generation); err != nil {
+		return nil, fmt.Errorf("spec.generation: %w", err)
+	}
+
+	// Bedrock has no models endpoint in the runtime API, and routers have no provider
+	if model.Spec.Probe != nil && model.Spec.Probe.Mode == genai.ProbeModeListModels &&
+		(model.Spec.Type == genai.ModelTypeBedrock || model.Spec.Type == genai.ModelTypeRouter) {
+		return nil, fmt.Errorf("spec.probe.mode: listModels is not supported for %s models", model.Spec.Type)
+	}
+
+	if err := validateModelTask(model); err != nil {
+		return nil, err
+	}
+
 	modellog.Info("Model validation complete", "name", model.GetName())
 
 	return nil, nil
 }
 
+// validateModelTask rejects embedding models of types without an embeddings API, and embedding
+// settings on other models
+func validateModelTask(model *arkv1alpha1.Model) error {
+	if model.Spec.Task != genai.ModelTaskEmbedding {
+		if model.Spec.Embedding != nil {
+			return fmt.Errorf("spec.embedding is only used by embedding models")
+		}
+		return nil
+	}
+
+	switch model.Spec.Type {
+	case genai.ModelTypeOpenAI, genai.ModelTypeAzure, genai.ModelTypeBedrock, genai.ModelTypeGemini:
+	default:
+		return fmt.Errorf("spec.task: embedding is not supported for %s models", model.Spec.Type)
+	}
+	if model.Spec.Probe != nil && model.Spec.Probe.DetectCapabilities {
+		return fmt.Errorf("spec.probe.detectCapabilities is not supported for embedding models")
+	}
+	return nil
+}
+
 func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	switch model.Spec.Type {
 	case genai.ModelTypeAzure:
@@ -97,6 +137,12 @@ func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv
 		return v.validateOpenAIConfig(ctx, model)
 	case genai.ModelTypeBedrock:
 		return v.validateBedrockConfig(ctx, model)
+	case genai.ModelTypeAnthropic:
+		return v.validateAnthropicConfig(ctx, model)
+	case genai.ModelTypeGemini:
+		return v.validateGeminiConfig(ctx, model)
+	case genai.ModelTypeRouter:
+		return v.validateRouterConfig(ctx, model)
 	default:
 		return fmt.Errorf("unsupported model type: %s", model.Spec.Type)
 	}
@@ -197,6 +243,139 @@ func (v *ModelValidator) validateBedrockConfig(ctx context.Context, model *arkv1
 	return nil
 }
 
+func (v *ModelValidator) validateAnthropicConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Anthropic == nil {
+		return fmt.Errorf("anthropic configuration is required for anthropic model type")
+	}
+
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Anthropic.BaseURL, model.GetNamespace(), "spec.config.anthropic.baseUrl"); err != nil {
+		return err
+	}
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Anthropic.APIKey, model.GetNamespace(), "spec.config.anthropic.apiKey"); err != nil {
+		return err
+	}
+	if model.Spec.Config.Anthropic.Version != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Anthropic.Version, model.GetNamespace(), "spec.config.anthropic.version"); err != nil {
+			return err
+		}
+	}
+
+	_, err := v.Resolver.ResolveValueSource(ctx, model.Spec.Config.Anthropic.BaseURL, model.GetNamespace())
+	if err != nil {
+		modellog.Error(err, "Failed to resolve Anthropic BaseURL", "model", model.GetName())
+		return fmt.Errorf("failed to resolve Anthropic BaseURL: %w", err)
+	}
+
+	for i, header := range model.Spec.Config.Anthropic.Headers {
+		contextPrefix := fmt.Sprintf("spec.config.anthropic.headers[%d]", i)
+		if err := ValidateHeader(header, contextPrefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *ModelValidator) validateGeminiConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	config := model.Spec.Config.Gemini
+	if config == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+
+	if (config.APIKey == nil) == (config.ServiceAccount == nil) {
+		return fmt.Errorf("gemini configuration requires exactly one of apiKey or serviceAccount")
+	}
+
+	valueSources := []struct {
+		fieldName   string
+		valueSource *arkv1alpha1.ValueSource
+	}{
+		{"spec.config.gemini.baseUrl", config.BaseURL},
+		{"spec.config.gemini.apiKey", config.APIKey},
+		{"spec.config.gemini.serviceAccount", config.ServiceAccount},
+		{"spec.config.gemini.project", config.Project},
+		{"spec.config.gemini.location", config.Location},
+	}
+	for _, field := range valueSources {
+		if field.valueSource == nil {
+			continue
+		}
+		if err := v.validateValueSource(ctx, field.valueSource, model.GetNamespace(), field.fieldName); err != nil {
+			return err
+		}
+	}
+
+	if config.APIKey != nil && (config.Project != nil || config.Location != nil) {
+		return fmt.Errorf("spec.config.gemini.project and location require a serviceAccount, API keys call the Gemini API")
+	}
+
+	for i, header := range config.Headers {
+		contextPrefix := fmt.Sprintf("spec.config.gemini.headers[%d]", i)
+		if err := ValidateHeader(header, contextPrefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *ModelValidator) validateRouterConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	config := model.Spec.Config.Router
+	if config == nil {
+		return fmt.Errorf("router configuration is required for router model type")
+	}
+	if len(config.Routes) == 0 {
+		return fmt.Errorf("spec.config.router.routes must have at least one route")
+	}
+	// The route models record the usage and cost of the calls to a router
+	if model.Spec.Pricing != nil {
+		return fmt.Errorf("spec.pricing: routers are not priced, the models of their routes are")
+	}
+
+	for i, route := range config.Routes {
+		field := fmt.Sprintf("spec.config.router.routes[%d]", i)
+		if route.MinPromptTokens != nil && route.MaxPromptTokens != nil && *route.MinPromptTokens > *route.MaxPromptTokens {
+			return fmt.Errorf("%s: minPromptTokens must not exceed maxPromptTokens", field)
+		}
+		if route.MaxCost != "" {
+			if _, err := strconv.ParseFloat(route.MaxCost, 64); err != nil {
+				return fmt.Errorf("%s.maxCost must be a number: %q", field, route.MaxCost)
+			}
+		}
+		for _, name := range append([]string{route.Model}, route.Fallbacks...) {
+			if err := v.validateRouteModel(ctx, model, name, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRouteModel rejects routes to routers. Models that do not exist yet are accepted and
+// reported by the router when it is called.
+func (v *ModelValidator) validateRouteModel(ctx context.Context, router *arkv1alpha1.Model, name, field string) error {
+	if name == router.Name {
+		return fmt.Errorf("%s: router cannot route to itself", field)
+	}
+
+	var routeModel arkv1alpha1.Model
+	key := types.NamespacedName{Name: name, Namespace: router.Namespace}
+	if err := v.Client.Get(ctx, key, &routeModel); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get model '%s' in namespace '%s': %v", name, router.Namespace, err)
+		}
+		return nil
+	}
+	if routeModel.Spec.Type == genai.ModelTypeRouter {
+		return fmt.Errorf("%s: model '%s' is a router, routes must use other model types", field, name)
+	}
+	if routeModel.Spec.Task == genai.ModelTaskEmbedding {
+		return fmt.Errorf("%s: model '%s' is an embedding model, routes must use completion models", field, name)
+	}
+	return nil
+}
+
 func (v *ModelValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	return v.ValidateCreate(ctx, newObj)
 }
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
@@ -103,6 +104,257 @@ var _ = Describe("Model Webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(warnings).To(BeEmpty())
 		})
+
+		It("Should allow valid Anthropic model with direct values", func() {
+			model.Spec.Type = genai.ModelTypeAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Anthropic: &arkv1alpha1.AnthropicModelConfig{
+					BaseURL: arkv1alpha1.ValueSource{
+						Value: "https://api.anthropic.com",
+					},
+					APIKey: arkv1alpha1.ValueSource{
+						Value: "sk-ant-test-key",
+					},
+					Version: &arkv1alpha1.ValueSource{
+						Value: "2023-06-01",
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should fail when Anthropic model has no anthropic configuration", func() {
+			model.Spec.Type = genai.ModelTypeAnthropic
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("anthropic configuration is required"))
+		})
+
+		It("Should allow valid Gemini model with an API key", func() {
+			model.Spec.Type = genai.ModelTypeGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{
+					APIKey: &arkv1alpha1.ValueSource{
+						Value: "gemini-test-key",
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should allow valid Gemini model with a Vertex AI service account", func() {
+			model.Spec.Type = genai.ModelTypeGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{
+					ServiceAccount: &arkv1alpha1.ValueSource{
+						Value: `{"client_email": "ark@project.iam.gserviceaccount.com"}`,
+					},
+					Project: &arkv1alpha1.ValueSource{
+						Value: "my-project",
+					},
+					Location: &arkv1alpha1.ValueSource{
+						Value: "europe-west4",
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should fail when Gemini model has both or neither credentials", func() {
+			model.Spec.Type = genai.ModelTypeGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of apiKey or serviceAccount"))
+
+			model.Spec.Config.Gemini.APIKey = &arkv1alpha1.ValueSource{Value: "gemini-test-key"}
+			model.Spec.Config.Gemini.ServiceAccount = &arkv1alpha1.ValueSource{Value: "{}"}
+			_, err = validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of apiKey or serviceAccount"))
+		})
+
+		It("Should fail when Gemini model with an API key sets a Vertex AI project", func() {
+			model.Spec.Type = genai.ModelTypeGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{
+					APIKey: &arkv1alpha1.ValueSource{
+						Value: "gemini-test-key",
+					},
+					Project: &arkv1alpha1.ValueSource{
+						Value: "my-project",
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("require a serviceAccount"))
+		})
+	})
+
+	Context("When validating generation parameters", func() {
+		It("Should allow generation parameters within range", func() {
+			temperature, topP, maxTokens := "0.2", "0.9", int64(1024)
+			model.Spec.Generation = &arkv1alpha1.GenerationParameters{
+				Temperature:     &temperature,
+				TopP:            &topP,
+				MaxTokens:       &maxTokens,
+				Stop:            []string{"END"},
+				ReasoningEffort: "low",
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a temperature out of range", func() {
+			temperature := "2.5"
+			model.Spec.Generation = &arkv1alpha1.GenerationParameters{Temperature: &temperature}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.generation: temperature must be between 0 and 2"))
+		})
+
+		It("Should reject empty stop sequences", func() {
+			model.Spec.Generation = &arkv1alpha1.GenerationParameters{Stop: []string{"END", ""}}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("stop[1] must not be empty"))
+		})
+	})
+
+	Context("When validating probe settings", func() {
+		It("Should allow the listModels probe mode", func() {
+			model.Spec.Probe = &arkv1alpha1.ModelProbe{Mode: genai.ProbeModeListModels, DetectCapabilities: true}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject the listModels probe mode for Bedrock models", func() {
+			model.Spec.Type = genai.ModelTypeBedrock
+			model.Spec.Config = arkv1alpha1.ModelConfig{Bedrock: &arkv1alpha1.BedrockModelConfig{}}
+			model.Spec.Probe = &arkv1alpha1.ModelProbe{Mode: genai.ProbeModeListModels}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.probe.mode: listModels is not supported for bedrock models"))
+		})
+	})
+
+	Context("When validating embedding models", func() {
+		BeforeEach(func() {
+			model.Spec.Task = genai.ModelTaskEmbedding
+			model.Spec.Model = arkv1alpha1.ValueSource{Value: "text-embedding-3-small"}
+			model.Spec.Embedding = &arkv1alpha1.EmbeddingParameters{Dimensions: ptr.To(int64(256))}
+		})
+
+		It("Should allow an OpenAI embedding model", func() {
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject embedding models of types without embeddings", func() {
+			model.Spec.Type = genai.ModelTypeAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{Anthropic: &arkv1alpha1.AnthropicModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: "https://api.anthropic.com/v1"},
+				APIKey:  arkv1alpha1.ValueSource{Value: "sk-ant-test"},
+			}}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.task: embedding is not supported for anthropic models"))
+		})
+
+		It("Should reject embedding settings on completion models", func() {
+			model.Spec.Task = genai.ModelTaskCompletion
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.embedding is only used by embedding models"))
+		})
+
+		It("Should reject capability detection", func() {
+			model.Spec.Probe = &arkv1alpha1.ModelProbe{DetectCapabilities: true}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("detectCapabilities is not supported for embedding models"))
+		})
+	})
+
+	Context("When validating routers", func() {
+		var router *arkv1alpha1.Model
+
+		BeforeEach(func() {
+			Expect(validator.Client.Create(ctx, model)).To(Succeed())
+			router = &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-router", Namespace: "default"},
+				Spec: arkv1alpha1.ModelSpec{
+					Type: genai.ModelTypeRouter,
+					Config: arkv1alpha1.ModelConfig{Router: &arkv1alpha1.RouterModelConfig{
+						Routes: []arkv1alpha1.ModelRoute{{Model: "test-model", MaxCost: "0.05", Fallbacks: []string{"missing-model"}}},
+					}},
+				},
+			}
+		})
+
+		It("Should allow a router without a provider model", func() {
+			_, err := validator.ValidateCreate(ctx, router)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should require the model of other model types", func() {
+			model.Spec.Model = arkv1alpha1.ValueSource{}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.model is required for openai models"))
+		})
+
+		It("Should reject routes to routers", func() {
+			Expect(validator.Client.Create(ctx, router)).To(Succeed())
+			nested := router.DeepCopy()
+			nested.ObjectMeta = metav1.ObjectMeta{Name: "nested-router", Namespace: "default"}
+			nested.Spec.Config.Router.Routes[0].Model = "test-router"
+
+			_, err := validator.ValidateCreate(ctx, nested)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("model 'test-router' is a router"))
+		})
+
+		It("Should reject a prompt size range that is empty", func() {
+			router.Spec.Config.Router.Routes[0].MinPromptTokens = ptr.To(int64(1000))
+			router.Spec.Config.Router.Routes[0].MaxPromptTokens = ptr.To(int64(100))
+
+			_, err := validator.ValidateCreate(ctx, router)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("minPromptTokens must not exceed maxPromptTokens"))
+		})
+
+		It("Should reject pricing on routers", func() {
+			router.Spec.Pricing = &arkv1alpha1.ModelPricing{InputPerMillion: "1", OutputPerMillion: "2"}
+
+			_, err := validator.ValidateCreate(ctx, router)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("routers are not priced"))
+		})
 	})
 
 	Context("When validating models with Secret references", func() {
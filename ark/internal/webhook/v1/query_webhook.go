@@ -5,6 +5,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"path"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -88,9 +89,41 @@ func (v *QueryCustomValidator) validateQuery(ctx context.Context, query *arkv1al
 		return warnings, err
 	}
 
+	if err := v.validateToolPolicy(query.Spec.ToolPolicy); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+func (v *QueryCustomValidator) validateToolPolicy(policy *arkv1alpha1.QueryToolPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	rules := []struct {
+		list     string
+		matchers []arkv1alpha1.QueryToolMatcher
+	}{
+		{"allow", policy.Allow},
+		{"deny", policy.Deny},
+	}
+	for _, rule := range rules {
+		for i, matcher := range rule.matchers {
+			if matcher.Name == "" && matcher.Type == "" && matcher.Annotations == nil {
+				return fmt.Errorf("toolPolicy.%s[%d]: at least one of name, type or annotations must be specified", rule.list, i)
+			}
+			if matcher.Name != "" {
+				if _, err := path.Match(matcher.Name, ""); err != nil {
+					return fmt.Errorf("toolPolicy.%s[%d]: invalid name pattern '%s': %v", rule.list, i, matcher.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func (v *QueryCustomValidator) validateQueryTargets(ctx context.Context, query *arkv1alpha1.Query) error {
 	if len(query.Spec.Targets) == 0 && query.Spec.Selector == nil {
 		return fmt.Errorf("at least one target or selector must be specified")
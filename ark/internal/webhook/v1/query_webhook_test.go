@@ -53,4 +53,24 @@ var _ = Describe("Query Webhook", func() {
 		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
 		// })
 	})
+
+	Context("When validating the tool policy", func() {
+		It("Should admit allow and deny rules", func() {
+			policy := &arkv1alpha1.QueryToolPolicy{
+				Allow: []arkv1alpha1.QueryToolMatcher{{Name: "github-*"}},
+				Deny:  []arkv1alpha1.QueryToolMatcher{{Type: "http"}},
+			}
+			Expect(validator.validateToolPolicy(policy)).To(Succeed())
+		})
+
+		It("Should deny an empty matcher", func() {
+			policy := &arkv1alpha1.QueryToolPolicy{Deny: []arkv1alpha1.QueryToolMatcher{{}}}
+			Expect(validator.validateToolPolicy(policy)).To(MatchError(ContainSubstring("toolPolicy.deny[0]")))
+		})
+
+		It("Should deny an invalid name pattern", func() {
+			policy := &arkv1alpha1.QueryToolPolicy{Allow: []arkv1alpha1.QueryToolMatcher{{Name: "github-["}}}
+			Expect(validator.validateToolPolicy(policy)).To(MatchError(ContainSubstring("invalid name pattern")))
+		})
+	})
 })
@@ -0,0 +1,238 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const referenceKindTool = "tool"
+
+// referenceNode is an agent, team or tool that can lead to the execution of other agents and teams
+type referenceNode struct {
+	kind string
+	name string
+}
+
+func (n referenceNode) String() string {
+	return n.kind + "/" + n.name
+}
+
+// referenceGraph resolves the references of agents, teams and tools in a namespace. The object under
+// admission is used for the start node in place of the stored version.
+type referenceGraph struct {
+	validator *ResourceValidator
+	namespace string
+	start     referenceNode
+	pending   client.Object
+}
+
+// ValidateNoReferenceCycle rejects agents, teams and tools that can reach themselves through the
+// resources they reference, as executing them would fail with a call cycle. References to resources
+// that do not exist yet are skipped.
+func (v *ResourceValidator) ValidateNoReferenceCycle(ctx context.Context, obj client.Object) error {
+	start, ok := referenceNodeFor(obj)
+	if !ok {
+		return nil
+	}
+
+	graph := &referenceGraph{validator: v, namespace: obj.GetNamespace(), start: start, pending: obj}
+	cycle, err := graph.findPathBack(ctx, []referenceNode{start}, map[referenceNode]bool{start: true})
+	if err != nil {
+		return err
+	}
+	if cycle == nil {
+		return nil
+	}
+
+	names := make([]string, len(cycle))
+	for i, node := range cycle {
+		names[i] = node.String()
+	}
+	return fmt.Errorf("reference cycle detected: %s", strings.Join(names, " -> "))
+}
+
+func referenceNodeFor(obj client.Object) (referenceNode, bool) {
+	switch obj.(type) {
+	case *arkv1alpha1.Agent:
+		return referenceNode{kind: MemberTypeAgent, name: obj.GetName()}, true
+	case *arkv1alpha1.Team:
+		return referenceNode{kind: MemberTypeTeam, name: obj.GetName()}, true
+	case *arkv1alpha1.Tool:
+		return referenceNode{kind: referenceKindTool, name: obj.GetName()}, true
+	default:
+		return referenceNode{}, false
+	}
+}
+
+// findPathBack searches depth first for a path from the last node of path back to the start node
+func (g *referenceGraph) findPathBack(ctx context.Context, path []referenceNode, visited map[referenceNode]bool) ([]referenceNode, error) {
+	refs, err := g.references(ctx, path[len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range refs {
+		next := append(slices.Clone(path), ref)
+		if ref == g.start {
+			return next, nil
+		}
+		if visited[ref] {
+			continue
+		}
+		visited[ref] = true
+
+		cycle, err := g.findPathBack(ctx, next, visited)
+		if err != nil || cycle != nil {
+			return cycle, err
+		}
+	}
+	return nil, nil
+}
+
+func (g *referenceGraph) references(ctx context.Context, node referenceNode) ([]referenceNode, error) {
+	obj := g.pending
+	if node != g.start {
+		switch node.kind {
+		case MemberTypeAgent:
+			obj = &arkv1alpha1.Agent{}
+		case MemberTypeTeam:
+			obj = &arkv1alpha1.Team{}
+		case referenceKindTool:
+			obj = &arkv1alpha1.Tool{}
+		default:
+			return nil, nil
+		}
+		if found, err := g.get(ctx, node.kind, node.name, obj); !found || err != nil {
+			return nil, err
+		}
+	}
+
+	switch resource := obj.(type) {
+	case *arkv1alpha1.Agent:
+		return g.agentReferences(ctx, resource.Spec.Tools)
+	case *arkv1alpha1.Team:
+		return teamReferences(resource), nil
+	case *arkv1alpha1.Tool:
+		return toolReferences(resource), nil
+	default:
+		return nil, nil
+	}
+}
+
+// get reads a stored resource, reporting whether it exists
+func (g *referenceGraph) get(ctx context.Context, kind, name string, obj client.Object) (bool, error) {
+	key := types.NamespacedName{Name: name, Namespace: g.namespace}
+	if err := g.validator.Client.Get(ctx, key, obj); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to get %s '%s' in namespace '%s': %v", kind, name, g.namespace, err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (g *referenceGraph) agentReferences(ctx context.Context, agentTools []arkv1alpha1.AgentTool) ([]referenceNode, error) {
+	var refs []referenceNode
+	for _, tool := range agentTools {
+		switch tool.Type {
+		case genai.AgentToolTypeCustom:
+			refs = append(refs, referenceNode{kind: referenceKindTool, name: tool.GetToolCRDName()})
+		case genai.AgentToolTypeSelector:
+			selected, err := g.selectedTools(ctx, tool.Selector)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, selected...)
+		case genai.AgentToolTypeToolSet:
+			if tool.ToolSet == nil {
+				continue
+			}
+			toolSet := &arkv1alpha1.ToolSet{}
+			found, err := g.get(ctx, "toolset", tool.ToolSet.Name, toolSet)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				continue
+			}
+			// ToolSets cannot reference other ToolSets, so this does not recurse further
+			setRefs, err := g.agentReferences(ctx, toolSet.Spec.Tools)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, setRefs...)
+		}
+	}
+	return refs, nil
+}
+
+// selectedTools lists the tools matching an agent tool selector, including the tool under admission
+func (g *referenceGraph) selectedTools(ctx context.Context, selector *metav1.LabelSelector) ([]referenceNode, error) {
+	if selector == nil {
+		return nil, nil
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, nil
+	}
+
+	var toolList arkv1alpha1.ToolList
+	if err := g.validator.Client.List(ctx, &toolList, &client.ListOptions{Namespace: g.namespace, LabelSelector: labelSelector}); err != nil {
+		return nil, fmt.Errorf("failed to list tools in namespace '%s': %v", g.namespace, err)
+	}
+
+	// The stored labels of the tool under admission may be outdated
+	pendingTool, isTool := g.pending.(*arkv1alpha1.Tool)
+	var refs []referenceNode
+	for _, tool := range toolList.Items {
+		if isTool && tool.Name == pendingTool.Name {
+			continue
+		}
+		refs = append(refs, referenceNode{kind: referenceKindTool, name: tool.Name})
+	}
+	if isTool && labelSelector.Matches(labels.Set(pendingTool.Labels)) {
+		refs = append(refs, g.start)
+	}
+	return refs, nil
+}
+
+func teamReferences(team *arkv1alpha1.Team) []referenceNode {
+	refs := make([]referenceNode, 0, len(team.Spec.Members)+3)
+	for _, member := range team.Spec.Members {
+		refs = append(refs, referenceNode{kind: member.Type, name: member.Name})
+	}
+	// The selector, aggregator and judge agents run inside the team, so their tools count as well
+	if team.Spec.Selector != nil && team.Spec.Selector.Agent != "" {
+		refs = append(refs, referenceNode{kind: MemberTypeAgent, name: team.Spec.Selector.Agent})
+	}
+	if team.Spec.Parallel != nil && team.Spec.Parallel.Aggregator != "" {
+		refs = append(refs, referenceNode{kind: MemberTypeAgent, name: team.Spec.Parallel.Aggregator})
+	}
+	if team.Spec.Vote != nil && team.Spec.Vote.Judge != "" {
+		refs = append(refs, referenceNode{kind: MemberTypeAgent, name: team.Spec.Vote.Judge})
+	}
+	return refs
+}
+
+func toolReferences(tool *arkv1alpha1.Tool) []referenceNode {
+	switch {
+	case tool.Spec.Type == genai.ToolTypeAgent && tool.Spec.Agent != nil:
+		return []referenceNode{{kind: MemberTypeAgent, name: tool.Spec.Agent.Name}}
+	case tool.Spec.Type == genai.ToolTypeTeam && tool.Spec.Team != nil:
+		return []referenceNode{{kind: MemberTypeTeam, name: tool.Spec.Team.Name}}
+	default:
+		return nil
+	}
+}
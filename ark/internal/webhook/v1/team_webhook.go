@@ -5,7 +5,10 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
 
+	"github.com/itchyny/gojq"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -20,6 +23,10 @@ const (
 	MemberTypeAgent  = "agent"
 	MemberTypeTeam   = "team"
 	StrategySelector = "selector"
+	StrategyParallel = "parallel"
+	StrategyHandoff  = "handoff"
+	StrategyPlan     = "plan"
+	StrategyVote     = "vote"
 )
 
 func SetupTeamWebhookWithManager(mgr ctrl.Manager) error {
@@ -71,15 +78,34 @@ func (v *TeamCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Ob
 func (v *TeamCustomValidator) validateTeamMembers(ctx context.Context, team *arkv1alpha1.Team) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
+	if err := validateUniqueMemberNames(team); err != nil {
+		return warnings, err
+	}
+
 	if err := v.validateStrategy(ctx, team); err != nil {
 		return warnings, err
 	}
+	if team.Spec.Strategy == "graph" || team.Spec.Strategy == StrategyHandoff {
+		warnings = append(warnings, unreachableGraphMembers(team)...)
+	}
+
+	if err := v.validateTeamContext(ctx, team.Spec.Context, team.Namespace); err != nil {
+		return warnings, fmt.Errorf("team context: %v", err)
+	}
+
+	if err := validateTermination(team.Spec.Termination); err != nil {
+		return warnings, fmt.Errorf("team termination: %v", err)
+	}
 
 	for i, member := range team.Spec.Members {
 		if member.Name == team.Name {
 			return warnings, fmt.Errorf("team member %d: team '%s' cannot reference itself", i, member.Name)
 		}
 
+		if err := v.validateTeamContext(ctx, member.Context, team.Namespace); err != nil {
+			return warnings, fmt.Errorf("team member %d context: %v", i, err)
+		}
+
 		var err error
 		switch member.Type {
 		case MemberTypeAgent:
@@ -101,9 +127,59 @@ func (v *TeamCustomValidator) validateTeamMembers(ctx context.Context, team *ark
 		return warnings, err
 	}
 
+	if err := v.ValidateNoReferenceCycle(ctx, team); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+func (v *TeamCustomValidator) validateTeamContext(ctx context.Context, spec *arkv1alpha1.TeamContextSpec, namespace string) error {
+	if spec == nil {
+		return nil
+	}
+
+	switch spec.Mode {
+	case "", genai.TeamContextModeFull, genai.TeamContextModeFinalOutputs:
+	case genai.TeamContextModeLastMessages:
+		if spec.LastMessages <= 0 {
+			return fmt.Errorf("mode '%s' requires lastMessages to be greater than 0", spec.Mode)
+		}
+	case genai.TeamContextModeSummary:
+		if spec.ModelRef != nil {
+			modelNamespace := spec.ModelRef.Namespace
+			if modelNamespace == "" {
+				modelNamespace = namespace
+			}
+			if err := v.ValidateLoadModel(ctx, spec.ModelRef.Name, modelNamespace); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported mode '%s': must be '%s', '%s', '%s' or '%s'", spec.Mode,
+			genai.TeamContextModeFull, genai.TeamContextModeLastMessages, genai.TeamContextModeFinalOutputs, genai.TeamContextModeSummary)
+	}
+	return nil
+}
+
+func validateTermination(spec *arkv1alpha1.TeamTerminationSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if spec.Regex != "" {
+		if _, err := regexp.Compile(spec.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+	if spec.MaxTokens < 0 {
+		return fmt.Errorf("maxTokens must be greater than 0")
+	}
+	if spec.MaxDuration != nil && spec.MaxDuration.Duration <= 0 {
+		return fmt.Errorf("maxDuration must be greater than 0")
+	}
+	return nil
+}
+
 func (v *TeamCustomValidator) validateNoMixedTeam(ctx context.Context, team *arkv1alpha1.Team) error {
 	var hasInternalAgents, hasExternalAgents bool
 
@@ -140,21 +216,155 @@ func (v *TeamCustomValidator) validateStrategy(ctx context.Context, team *arkv1a
 		}
 		// If graph is provided, validate it (allows multiple edges from same source for selector)
 		if team.Spec.Graph != nil {
-			return v.validateGraphForSelector(team)
+			return v.validateGraphConstraints(team)
 		}
 		return nil
 	case "graph":
 		return v.validateGraphStrategy(team)
+	case StrategyParallel:
+		return v.validateParallelStrategy(ctx, team)
+	case StrategyHandoff:
+		return v.validateHandoffStrategy(team)
+	case StrategyPlan:
+		return validatePlanStrategy(team)
+	case StrategyVote:
+		return v.validateVoteStrategy(ctx, team)
 	default:
-		return fmt.Errorf("unsupported strategy '%s': must be 'sequential', 'round-robin', 'selector', or 'graph'", team.Spec.Strategy)
+		return fmt.Errorf("unsupported strategy '%s': must be 'sequential', 'round-robin', 'selector', 'graph', 'parallel', 'handoff', 'plan', or 'vote'", team.Spec.Strategy)
 	}
 }
 
+func (v *TeamCustomValidator) validateVoteStrategy(ctx context.Context, team *arkv1alpha1.Team) error {
+	vote := team.Spec.Vote
+	if vote == nil {
+		vote = &arkv1alpha1.TeamVoteSpec{}
+	}
+
+	memberNames := make(map[string]bool)
+	for _, member := range team.Spec.Members {
+		memberNames[member.Name] = true
+	}
+	for i, name := range vote.Members {
+		if !memberNames[name] {
+			return fmt.Errorf("vote member %d: '%s' not found in team members", i, name)
+		}
+		if slices.Contains(vote.Members[:i], name) {
+			return fmt.Errorf("vote member %d: '%s' is listed more than once", i, name)
+		}
+	}
+	voters := len(vote.Members)
+	if voters == 0 {
+		voters = len(team.Spec.Members)
+	}
+	if voters < 2 {
+		return fmt.Errorf("vote strategy requires at least two voting members")
+	}
+
+	switch vote.Method {
+	case "", genai.VoteMethodMajority:
+		if vote.Judge != "" || vote.Rubric != "" {
+			return fmt.Errorf("vote judge and rubric are only used by the '%s' method", genai.VoteMethodJudge)
+		}
+	case genai.VoteMethodJudge:
+		if vote.Judge == "" {
+			return fmt.Errorf("vote method '%s' requires vote.judge", genai.VoteMethodJudge)
+		}
+		if err := v.ValidateLoadAgent(ctx, vote.Judge, team.Namespace); err != nil {
+			return fmt.Errorf("judge agent '%s' not found in namespace %s: %v", vote.Judge, team.Namespace, err)
+		}
+	default:
+		return fmt.Errorf("unsupported vote method '%s': must be '%s' or '%s'", vote.Method, genai.VoteMethodMajority, genai.VoteMethodJudge)
+	}
+	return nil
+}
+
+func validatePlanStrategy(team *arkv1alpha1.Team) error {
+	plan := team.Spec.Plan
+	if plan == nil || plan.Planner == "" {
+		return fmt.Errorf("plan strategy requires plan.planner")
+	}
+
+	found := false
+	for _, member := range team.Spec.Members {
+		if member.Name == plan.Planner {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("planner '%s' not found in team members", plan.Planner)
+	}
+	if len(team.Spec.Members) < 2 {
+		return fmt.Errorf("plan strategy requires at least one member besides the planner")
+	}
+	return nil
+}
+
+func (v *TeamCustomValidator) validateHandoffStrategy(team *arkv1alpha1.Team) error {
+	if team.Spec.MaxTurns == nil {
+		return fmt.Errorf("handoff strategy requires maxTurns to prevent infinite execution")
+	}
+
+	// Only agents are given the tools to transfer control, so a team member could never hand off
+	for i, member := range team.Spec.Members {
+		if member.Type != MemberTypeAgent {
+			return fmt.Errorf("team member %d: handoff strategy only supports agent members, '%s' is a %s", i, member.Name, member.Type)
+		}
+	}
+
+	// Graph edges are optional and restrict which members each agent can transfer to
+	if team.Spec.Graph != nil {
+		return v.validateGraphConstraints(team)
+	}
+	return nil
+}
+
+func (v *TeamCustomValidator) validateParallelStrategy(ctx context.Context, team *arkv1alpha1.Team) error {
+	parallel := team.Spec.Parallel
+	if parallel == nil {
+		return nil
+	}
+
+	memberNames := make(map[string]bool)
+	for _, member := range team.Spec.Members {
+		memberNames[member.Name] = true
+	}
+	for i, name := range parallel.Members {
+		if !memberNames[name] {
+			return fmt.Errorf("parallel member %d: '%s' not found in team members", i, name)
+		}
+		if slices.Contains(parallel.Members[:i], name) {
+			return fmt.Errorf("parallel member %d: '%s' is listed more than once", i, name)
+		}
+	}
+
+	switch parallel.FailurePolicy {
+	case "", genai.ParallelFailurePolicyFail, genai.ParallelFailurePolicyContinue:
+	default:
+		return fmt.Errorf("unsupported parallel failure policy '%s': must be '%s' or '%s'", parallel.FailurePolicy, genai.ParallelFailurePolicyFail, genai.ParallelFailurePolicyContinue)
+	}
+
+	if parallel.Aggregator != "" {
+		if err := v.ValidateLoadAgent(ctx, parallel.Aggregator, team.Namespace); err != nil {
+			return fmt.Errorf("parallel aggregator agent '%s' not found in namespace %s: %v", parallel.Aggregator, team.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
 func (v *TeamCustomValidator) validateSelectorAgent(ctx context.Context, team *arkv1alpha1.Team) error {
 	if team.Spec.Selector == nil || team.Spec.Selector.Agent == "" {
 		return fmt.Errorf("selector strategy requires selector.agent to be specified")
 	}
 
+	switch policy := team.Spec.Selector.InvalidSelectionPolicy; policy {
+	case "", genai.SelectorInvalidSelectionFail, genai.SelectorInvalidSelectionFallback, genai.SelectorInvalidSelectionTerminate:
+	default:
+		return fmt.Errorf("unsupported invalid selection policy '%s': must be '%s', '%s' or '%s'", policy,
+			genai.SelectorInvalidSelectionFail, genai.SelectorInvalidSelectionFallback, genai.SelectorInvalidSelectionTerminate)
+	}
+
 	agentName := team.Spec.Selector.Agent
 
 	err := v.ValidateLoadAgent(ctx, agentName, team.Namespace)
@@ -179,7 +389,7 @@ func (v *TeamCustomValidator) validateGraphStrategy(team *arkv1alpha1.Team) erro
 		memberNames[member.Name] = true
 	}
 
-	transitionMap := make(map[string]bool)
+	outgoing := make(map[string][]arkv1alpha1.TeamGraphEdge)
 	for i, edge := range team.Spec.Graph.Edges {
 		if !memberNames[edge.From] {
 			return fmt.Errorf("graph edge %d: 'from' member '%s' not found in team members", i, edge.From)
@@ -187,10 +397,16 @@ func (v *TeamCustomValidator) validateGraphStrategy(team *arkv1alpha1.Team) erro
 		if !memberNames[edge.To] {
 			return fmt.Errorf("graph edge %d: 'to' member '%s' not found in team members", i, edge.To)
 		}
-		if _, exists := transitionMap[edge.From]; exists {
-			return fmt.Errorf("member '%s' has more than one outgoing edge", edge.From)
+		if err := validateGraphEdgeCondition(edge); err != nil {
+			return fmt.Errorf("graph edge %d: %v", i, err)
+		}
+		outgoing[edge.From] = append(outgoing[edge.From], edge)
+	}
+
+	for _, member := range team.Spec.Members {
+		if err := validateOutgoingEdges(member.Name, outgoing[member.Name]); err != nil {
+			return err
 		}
-		transitionMap[edge.From] = true
 	}
 
 	if team.Spec.MaxTurns == nil {
@@ -200,7 +416,107 @@ func (v *TeamCustomValidator) validateGraphStrategy(team *arkv1alpha1.Team) erro
 	return nil
 }
 
-func (v *TeamCustomValidator) validateGraphForSelector(team *arkv1alpha1.Team) error {
+func validateGraphEdgeCondition(edge arkv1alpha1.TeamGraphEdge) error {
+	condition := edge.Condition
+	if condition == nil {
+		return nil
+	}
+	if edge.Default {
+		return fmt.Errorf("default edge from '%s' to '%s' cannot have a condition", edge.From, edge.To)
+	}
+
+	set := 0
+	for _, field := range []string{condition.Regex, condition.JQ, condition.Keyword} {
+		if field != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("condition must set exactly one of regex, jq or keyword")
+	}
+
+	if condition.Regex != "" {
+		if _, err := regexp.Compile(condition.Regex); err != nil {
+			return fmt.Errorf("invalid regex condition: %v", err)
+		}
+	}
+	if condition.JQ != "" {
+		if _, err := gojq.Parse(condition.JQ); err != nil {
+			return fmt.Errorf("invalid jq condition: %v", err)
+		}
+	}
+	return nil
+}
+
+// validateOutgoingEdges rejects edges from one member whose order of evaluation cannot be decided
+// or that can never be taken
+func validateOutgoingEdges(from string, edges []arkv1alpha1.TeamGraphEdge) error {
+	var unconditional, defaults int
+	for i, edge := range edges {
+		switch {
+		case edge.Default:
+			defaults++
+		case edge.Condition == nil:
+			unconditional++
+		}
+		for _, other := range edges[:i] {
+			if other.Condition != nil && edge.Condition != nil && *other.Condition == *edge.Condition {
+				return fmt.Errorf("member '%s' has ambiguous edges to '%s' and '%s' with the same condition", from, other.To, edge.To)
+			}
+		}
+	}
+
+	if defaults > 1 {
+		return fmt.Errorf("member '%s' has more than one default edge", from)
+	}
+	if unconditional > 0 && len(edges) > 1 {
+		return fmt.Errorf("member '%s' has more than one outgoing edge, so every edge except the default needs a condition", from)
+	}
+	return nil
+}
+
+// validateUniqueMemberNames rejects members sharing a name, as strategies address members by name
+func validateUniqueMemberNames(team *arkv1alpha1.Team) error {
+	seen := make(map[string]int, len(team.Spec.Members))
+	for i, member := range team.Spec.Members {
+		if first, ok := seen[member.Name]; ok {
+			return fmt.Errorf("team member %d: name '%s' is already used by team member %d", i, member.Name, first)
+		}
+		seen[member.Name] = i
+	}
+	return nil
+}
+
+// unreachableGraphMembers returns a warning for each member that graph execution, which starts at
+// the first member, can never reach
+func unreachableGraphMembers(team *arkv1alpha1.Team) admission.Warnings {
+	if team.Spec.Graph == nil || len(team.Spec.Members) == 0 {
+		return nil
+	}
+
+	reachable := map[string]bool{team.Spec.Members[0].Name: true}
+	queue := []string{team.Spec.Members[0].Name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range team.Spec.Graph.Edges {
+			if edge.From == current && !reachable[edge.To] {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	var warnings admission.Warnings
+	for _, member := range team.Spec.Members {
+		if !reachable[member.Name] {
+			warnings = append(warnings, fmt.Sprintf("team member '%s' is unreachable from '%s' in the graph and will never run", member.Name, team.Spec.Members[0].Name))
+		}
+	}
+	return warnings
+}
+
+func (v *TeamCustomValidator) validateGraphConstraints(team *arkv1alpha1.Team) error {
 	if team.Spec.Graph == nil {
 		return fmt.Errorf("graph constraint requires graph configuration")
 	}
@@ -216,7 +532,7 @@ func (v *TeamCustomValidator) validateGraphForSelector(team *arkv1alpha1.Team) e
 
 	// Validate edges reference valid members
 	// Note: Unlike validateGraphStrategy, we allow multiple edges with same 'from'
-	// because the selector agent will choose from multiple options
+	// because the selector agent, or the agent handing off, will choose from multiple options
 	for i, edge := range team.Spec.Graph.Edges {
 		if !memberNames[edge.From] {
 			return fmt.Errorf("graph edge %d: 'from' member '%s' not found in team members", i, edge.From)
@@ -224,6 +540,9 @@ func (v *TeamCustomValidator) validateGraphForSelector(team *arkv1alpha1.Team) e
 		if !memberNames[edge.To] {
 			return fmt.Errorf("graph edge %d: 'to' member '%s' not found in team members", i, edge.To)
 		}
+		if edge.Condition != nil || edge.Default {
+			return fmt.Errorf("graph edge %d: conditions and default edges are only supported by the graph strategy", i)
+		}
 	}
 
 	// Note: maxTurns is optional for selector strategy (it handles termination differently)
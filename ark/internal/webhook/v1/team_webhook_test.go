@@ -4,6 +4,7 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -178,6 +179,31 @@ var _ = Describe("Team Webhook", func() {
 		})
 	})
 
+	Context("Selector invalid selection policy", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = StrategySelector
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "analyst", Type: "agent"},
+			}
+		})
+
+		It("Should allow a supported policy", func() {
+			obj.Spec.Selector = &arkv1alpha1.TeamSelectorSpec{Agent: "coordinator", InvalidSelectionPolicy: "terminate"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should reject an unsupported policy", func() {
+			obj.Spec.Selector = &arkv1alpha1.TeamSelectorSpec{Agent: "coordinator", InvalidSelectionPolicy: "retry"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported invalid selection policy 'retry'"))
+		})
+	})
+
 	Context("Graph strategy validation (should remain strict)", func() {
 		It("Should reject multiple edges from same source for graph strategy", func() {
 			By("creating a graph team with multiple edges from same source")
@@ -190,7 +216,7 @@ var _ = Describe("Team Webhook", func() {
 			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
 				Edges: []arkv1alpha1.TeamGraphEdge{
 					{From: "researcher", To: "analyst"},
-					{From: "researcher", To: "writer"}, // Multiple edges from same source - NOT allowed for graph
+					{From: "researcher", To: "writer"}, // Multiple unconditional edges from same source - NOT allowed for graph
 				},
 			}
 			maxTurns := 10
@@ -201,4 +227,478 @@ var _ = Describe("Team Webhook", func() {
 			Expect(err.Error()).To(ContainSubstring("more than one outgoing edge"))
 		})
 	})
+
+	Context("Graph strategy conditional edges", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = "graph"
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "analyst", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+			maxTurns := 10
+			obj.Spec.MaxTurns = &maxTurns
+		})
+
+		It("Should allow conditional edges with a default edge", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Condition: &arkv1alpha1.TeamGraphEdgeCondition{JQ: ".needsAnalysis"}, Priority: 10},
+					{From: "researcher", To: "writer", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Regex: "(?i)done"}},
+					{From: "researcher", To: "writer", Default: true},
+					{From: "analyst", To: "writer"},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject edges with the same condition", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Keyword: "analyze"}},
+					{From: "researcher", To: "writer", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Keyword: "analyze"}},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("ambiguous edges to 'analyst' and 'writer'"))
+		})
+
+		It("Should reject more than one default edge", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Default: true},
+					{From: "researcher", To: "writer", Default: true},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("more than one default edge"))
+		})
+
+		It("Should reject a condition that sets more than one matcher", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Regex: "done", Keyword: "done"}},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of regex, jq or keyword"))
+		})
+
+		It("Should reject an invalid regex condition", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Regex: "done("}},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid regex condition"))
+		})
+
+		It("Should warn about unreachable members", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst"},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("'writer' is unreachable")))
+		})
+
+		It("Should reject conditions for the selector strategy", func() {
+			obj.Spec.Strategy = StrategySelector
+			obj.Spec.Selector = &arkv1alpha1.TeamSelectorSpec{Agent: "coordinator"}
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Keyword: "analyze"}},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only supported by the graph strategy"))
+		})
+	})
+
+	Context("Plan strategy validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = StrategyPlan
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "coordinator", Type: "agent"},
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+			obj.Spec.Plan = &arkv1alpha1.TeamPlanSpec{Planner: "coordinator", MaxReplans: 2}
+		})
+
+		It("Should allow a planner that is a team member", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should require a planner", func() {
+			obj.Spec.Plan = nil
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("plan strategy requires plan.planner"))
+		})
+
+		It("Should reject a planner that is not a team member", func() {
+			obj.Spec.Plan.Planner = "analyst"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("planner 'analyst' not found in team members"))
+		})
+
+		It("Should require members besides the planner", func() {
+			obj.Spec.Members = []arkv1alpha1.TeamMember{{Name: "coordinator", Type: "agent"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least one member besides the planner"))
+		})
+	})
+
+	Context("Vote strategy validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = StrategyVote
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "analyst", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+		})
+
+		It("Should allow a majority vote without configuration", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should allow a judge agent with a rubric", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Method: "judge", Judge: "coordinator", Rubric: "Prefer answers that cite sources"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should require a judge for the judge method", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Method: "judge"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("vote method 'judge' requires vote.judge"))
+		})
+
+		It("Should reject a judge agent that does not exist", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Method: "judge", Judge: "missing-judge"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("judge agent 'missing-judge' not found"))
+		})
+
+		It("Should reject a judge for the majority method", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Judge: "coordinator"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only used by the 'judge' method"))
+		})
+
+		It("Should reject vote members that are not team members", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Members: []string{"researcher", "editor"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("vote member 1: 'editor' not found in team members"))
+		})
+
+		It("Should reject a vote member listed twice", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Members: []string{"researcher", "analyst", "researcher"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("vote member 2: 'researcher' is listed more than once"))
+		})
+
+		It("Should require at least two voting members", func() {
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Members: []string{"researcher"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least two voting members"))
+		})
+	})
+
+	Context("Handoff strategy validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = StrategyHandoff
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "analyst", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+			maxTurns := 10
+			obj.Spec.MaxTurns = &maxTurns
+		})
+
+		It("Should allow graph edges as allowed transfers", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst"},
+					{From: "researcher", To: "writer"},
+					{From: "analyst", To: "researcher"},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject team members", func() {
+			obj.Spec.Members[1] = arkv1alpha1.TeamMember{Name: "analysts", Type: "team"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("handoff strategy only supports agent members"))
+		})
+
+		It("Should require maxTurns", func() {
+			obj.Spec.MaxTurns = nil
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("handoff strategy requires maxTurns"))
+		})
+
+		It("Should reject edge conditions", func() {
+			obj.Spec.Graph = &arkv1alpha1.TeamGraphSpec{
+				Edges: []arkv1alpha1.TeamGraphEdge{
+					{From: "researcher", To: "analyst", Default: true},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only supported by the graph strategy"))
+		})
+	})
+
+	Context("Member context validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = "sequential"
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent", Context: &arkv1alpha1.TeamContextSpec{Mode: "finalOutputs"}},
+			}
+		})
+
+		It("Should allow team and member contexts", func() {
+			obj.Spec.Context = &arkv1alpha1.TeamContextSpec{Mode: "lastMessages", LastMessages: 4}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should require lastMessages for the lastMessages mode", func() {
+			obj.Spec.Members[0].Context = &arkv1alpha1.TeamContextSpec{Mode: "lastMessages"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("team member 0 context: mode 'lastMessages' requires lastMessages"))
+		})
+
+		It("Should reject a missing summary model", func() {
+			obj.Spec.Context = &arkv1alpha1.TeamContextSpec{Mode: "summary", ModelRef: &arkv1alpha1.AgentModelRef{Name: "summarizer"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("model 'summarizer' does not exist"))
+		})
+	})
+
+	Context("Termination validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = "sequential"
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+			}
+		})
+
+		It("Should allow termination conditions", func() {
+			obj.Spec.Termination = &arkv1alpha1.TeamTerminationSpec{
+				Regex:       "^DONE",
+				Keyword:     "approved",
+				MaxTokens:   10000,
+				MaxDuration: &metav1.Duration{Duration: time.Minute},
+				NoProgress:  true,
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should reject an invalid regex", func() {
+			obj.Spec.Termination = &arkv1alpha1.TeamTerminationSpec{Regex: "DONE("}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("team termination: invalid regex"))
+		})
+
+		It("Should reject a non-positive duration", func() {
+			obj.Spec.Termination = &arkv1alpha1.TeamTerminationSpec{MaxDuration: &metav1.Duration{}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("maxDuration must be greater than 0"))
+		})
+	})
+
+	Context("Reference cycle validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = "sequential"
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "delegator", Namespace: "default"},
+				Spec: arkv1alpha1.AgentSpec{
+					Prompt: "You delegate reviews",
+					Tools:  []arkv1alpha1.AgentTool{{Type: "custom", Name: "ask-test-team"}},
+				},
+			})).To(Succeed())
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "ask-test-team", Namespace: "default"},
+				Spec:       arkv1alpha1.ToolSpec{Type: "team", Team: &arkv1alpha1.TeamToolRef{Name: "test-team"}},
+			})).To(Succeed())
+		})
+
+		It("Should reject a member that calls the team through a tool", func() {
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "delegator", Type: "agent"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("reference cycle detected: team/test-team -> agent/delegator -> tool/ask-test-team -> team/test-team"))
+		})
+
+		It("Should reject a selector agent that calls the team through a tool", func() {
+			obj.Spec.Strategy = "selector"
+			obj.Spec.Selector = &arkv1alpha1.TeamSelectorSpec{Agent: "delegator"}
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reference cycle detected"))
+		})
+
+		It("Should reject a vote judge that calls the team through a tool", func() {
+			obj.Spec.Strategy = StrategyVote
+			obj.Spec.Vote = &arkv1alpha1.TeamVoteSpec{Method: "judge", Judge: "delegator"}
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("reference cycle detected: team/test-team -> agent/delegator -> tool/ask-test-team -> team/test-team"))
+		})
+
+		It("Should reject a parallel aggregator that calls the team through a tool", func() {
+			obj.Spec.Strategy = StrategyParallel
+			obj.Spec.Parallel = &arkv1alpha1.TeamParallelSpec{Aggregator: "delegator"}
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reference cycle detected"))
+		})
+
+		It("Should allow members that do not call the team", func() {
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "writer", Type: "agent"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("Parallel strategy validation", func() {
+		BeforeEach(func() {
+			obj.Spec.Strategy = StrategyParallel
+			obj.Spec.Members = []arkv1alpha1.TeamMember{
+				{Name: "researcher", Type: "agent"},
+				{Name: "analyst", Type: "agent"},
+			}
+		})
+
+		It("Should allow a parallel team with an aggregator", func() {
+			obj.Spec.Parallel = &arkv1alpha1.TeamParallelSpec{
+				Members:       []string{"researcher"},
+				Aggregator:    "writer",
+				FailurePolicy: "continue",
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should reject parallel members that are not team members", func() {
+			obj.Spec.Parallel = &arkv1alpha1.TeamParallelSpec{Members: []string{"writer"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not found in team members"))
+		})
+
+		It("Should reject a parallel member listed twice", func() {
+			obj.Spec.Parallel = &arkv1alpha1.TeamParallelSpec{Members: []string{"analyst", "analyst"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("parallel member 1: 'analyst' is listed more than once"))
+		})
+
+		It("Should reject members that share a name", func() {
+			obj.Spec.Members = append(obj.Spec.Members, arkv1alpha1.TeamMember{Name: "researcher", Type: "team"})
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("team member 2: name 'researcher' is already used by team member 0"))
+		})
+
+		It("Should reject a missing aggregator agent", func() {
+			obj.Spec.Parallel = &arkv1alpha1.TeamParallelSpec{Aggregator: "missing"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("aggregator agent 'missing'"))
+		})
+	})
 })
@@ -7,8 +7,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"regexp"
+	"text/template"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/itchyny/gojq"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -25,13 +28,15 @@ var log = logf.Log
 // SetupToolWebhookWithManager registers the webhook for Tool in the manager.
 func SetupToolWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.Tool{}).
-		WithValidator(&ToolCustomValidator{}).
+		WithValidator(&ToolCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-tool,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=tools,verbs=create;update,versions=v1alpha1,name=vtool-v1.kb.io,admissionReviewVersions=v1
 
-type ToolCustomValidator struct{}
+type ToolCustomValidator struct {
+	*ResourceValidator
+}
 
 var _ webhook.CustomValidator = &ToolCustomValidator{}
 
@@ -62,7 +67,7 @@ func (v *ToolCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Ob
 	return nil, nil
 }
 
-func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.Tool) (admission.Warnings, error) {
+func (v *ToolCustomValidator) validateTool(ctx context.Context, tool *arkv1alpha1.Tool) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
 	// Validate inputSchema if present
@@ -72,15 +77,27 @@ func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.
 		}
 	}
 
+	if err := v.validatePostProcess(tool.Spec.PostProcess); err != nil {
+		return warnings, err
+	}
+
 	switch tool.Spec.Type {
 	case genai.ToolTypeHTTP:
 		return v.validateHTTP(tool.Spec.HTTP)
 	case genai.ToolTypeMCP:
 		return v.validateMCPTool(tool.Spec.MCP)
 	case genai.ToolTypeAgent:
-		return v.validateAgentTool(tool.Spec.Agent.Name)
+		warnings, err := v.validateAgentTool(tool.Spec.Agent.Name)
+		if err != nil {
+			return warnings, err
+		}
+		return warnings, v.ValidateNoReferenceCycle(ctx, tool)
 	case genai.ToolTypeTeam:
-		return v.validateTeamTool(tool.Spec.Team.Name)
+		warnings, err := v.validateTeamTool(tool.Spec.Team.Name)
+		if err != nil {
+			return warnings, err
+		}
+		return warnings, v.ValidateNoReferenceCycle(ctx, tool)
 	case genai.ToolTypeBuiltin:
 		return v.validateBuiltinTool(tool.Name)
 	default:
@@ -114,9 +131,91 @@ func (v *ToolCustomValidator) validateHTTP(httpSpec *arkv1alpha1.HTTPSpec) (admi
 		}
 	}
 
+	if httpSpec.Async != nil {
+		if err := v.validateHTTPAsync(httpSpec.Async); err != nil {
+			return warnings, err
+		}
+	}
+
 	return warnings, nil
 }
 
+// validateHTTPAsync validates the jq expressions and intervals used to poll long-running operations
+func (v *ToolCustomValidator) validateHTTPAsync(async *arkv1alpha1.HTTPAsyncSpec) error {
+	expressions := []struct{ field, expr string }{
+		{"statusURLPath", async.StatusURLPath},
+		{"successCondition", async.SuccessCondition},
+		{"failureCondition", async.FailureCondition},
+	}
+	for _, e := range expressions {
+		if e.expr == "" {
+			continue
+		}
+		if _, err := gojq.Parse(e.expr); err != nil {
+			return fmt.Errorf("invalid jq expression in async.%s: %v", e.field, err)
+		}
+	}
+
+	if async.PollInterval != nil && async.PollInterval.Duration < 0 {
+		return fmt.Errorf("async.pollInterval must not be negative")
+	}
+	if async.MaxPollInterval != nil && async.MaxPollInterval.Duration < 0 {
+		return fmt.Errorf("async.maxPollInterval must not be negative")
+	}
+	if async.PollInterval != nil && async.MaxPollInterval != nil && async.MaxPollInterval.Duration < async.PollInterval.Duration {
+		return fmt.Errorf("async.maxPollInterval must be greater than or equal to async.pollInterval")
+	}
+
+	return nil
+}
+
+// validatePostProcess checks that each output post-processing step is complete and its expression compiles
+func (v *ToolCustomValidator) validatePostProcess(steps []arkv1alpha1.ToolOutputStep) error {
+	for i, step := range steps {
+		var err error
+		switch step.Type {
+		case genai.PostProcessJQ:
+			if step.Expression == "" {
+				err = fmt.Errorf("expression is required")
+			} else if _, parseErr := gojq.Parse(step.Expression); parseErr != nil {
+				err = fmt.Errorf("invalid jq expression: %v", parseErr)
+			}
+		case genai.PostProcessTemplate:
+			if step.Expression == "" {
+				err = fmt.Errorf("expression is required")
+			} else if _, parseErr := template.New("postProcess").Parse(step.Expression); parseErr != nil {
+				err = fmt.Errorf("invalid template: %v", parseErr)
+			}
+		case genai.PostProcessRegex:
+			if step.Expression == "" {
+				err = fmt.Errorf("expression is required")
+			} else if re, compileErr := regexp.Compile(step.Expression); compileErr != nil {
+				err = fmt.Errorf("invalid regular expression: %v", compileErr)
+			} else if step.Group > re.NumSubexp() {
+				err = fmt.Errorf("regular expression has no capture group %d", step.Group)
+			}
+		case genai.PostProcessTable:
+			if step.Format != "" && step.Format != genai.TableFormatMarkdown && step.Format != genai.TableFormatCSV {
+				err = fmt.Errorf("unsupported format '%s': must be '%s' or '%s'", step.Format, genai.TableFormatMarkdown, genai.TableFormatCSV)
+			}
+		case genai.PostProcessRedact:
+			if step.Expression == "" && len(step.Fields) == 0 {
+				err = fmt.Errorf("expression or fields is required")
+			} else if step.Expression != "" {
+				if _, compileErr := regexp.Compile(step.Expression); compileErr != nil {
+					err = fmt.Errorf("invalid regular expression: %v", compileErr)
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported type '%s': supported types are: jq, template, regex, table, redact", step.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("postProcess step %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
 // validateMCPTool validates MCP-specific configuration
 func (v *ToolCustomValidator) validateMCPTool(mcp *arkv1alpha1.MCPToolRef) (admission.Warnings, error) {
 	var warnings admission.Warnings
@@ -4,11 +4,14 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/genai"
@@ -22,11 +25,33 @@ var _ = Describe("Tool Webhook", func() {
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		validator = &ToolCustomValidator{}
 
 		// Setup scheme
 		s := runtime.NewScheme()
 		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		// Agent 'planner' can call team 'research-team' through tool 'ask-research-team', and the
+		// team has 'planner' as a member
+		objects := []client.Object{
+			&arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "planner", Namespace: "default"},
+				Spec: arkv1alpha1.AgentSpec{
+					Tools: []arkv1alpha1.AgentTool{{Type: genai.AgentToolTypeCustom, Name: "ask-research-team"}},
+				},
+			},
+			&arkv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "research-team", Namespace: "default"},
+				Spec: arkv1alpha1.TeamSpec{
+					Strategy: "sequential",
+					Members:  []arkv1alpha1.TeamMember{{Name: "planner", Type: MemberTypeAgent}},
+				},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
+
+		validator = &ToolCustomValidator{
+			ResourceValidator: &ResourceValidator{Client: fakeClient},
+		}
 	})
 
 	Context("When validating team tool", func() {
@@ -69,4 +94,130 @@ var _ = Describe("Tool Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 	})
+
+	Context("When validating async http tool", func() {
+		newAsyncTool := func(async *arkv1alpha1.HTTPAsyncSpec) *arkv1alpha1.Tool {
+			return &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "async-tool",
+					Namespace: "default",
+				},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeHTTP,
+					HTTP: &arkv1alpha1.HTTPSpec{
+						URL:    "https://api.example.com/jobs",
+						Method: "POST",
+						Async:  async,
+					},
+				},
+			}
+		}
+
+		It("Should validate async configuration with jq conditions", func() {
+			tool := newAsyncTool(&arkv1alpha1.HTTPAsyncSpec{
+				StatusURLPath:    ".links.status",
+				SuccessCondition: `.state == "done"`,
+				FailureCondition: `.state == "failed"`,
+				PollInterval:     &metav1.Duration{Duration: time.Second},
+				MaxPollInterval:  &metav1.Duration{Duration: 10 * time.Second},
+			})
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject invalid jq expressions", func() {
+			tool := newAsyncTool(&arkv1alpha1.HTTPAsyncSpec{
+				SuccessCondition: ".state ==",
+			})
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid jq expression in async.successCondition"))
+		})
+
+		It("Should reject a max poll interval below the poll interval", func() {
+			tool := newAsyncTool(&arkv1alpha1.HTTPAsyncSpec{
+				PollInterval:    &metav1.Duration{Duration: 10 * time.Second},
+				MaxPollInterval: &metav1.Duration{Duration: time.Second},
+			})
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("maxPollInterval"))
+		})
+	})
+
+	Context("When validating post-processing steps", func() {
+		newToolWithSteps := func(steps ...arkv1alpha1.ToolOutputStep) *arkv1alpha1.Tool {
+			return &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-tool",
+					Namespace: "default",
+				},
+				Spec: arkv1alpha1.ToolSpec{
+					Type:        genai.ToolTypeTeam,
+					Team:        &arkv1alpha1.TeamToolRef{Name: "test-team"},
+					PostProcess: steps,
+				},
+			}
+		}
+
+		It("Should accept a valid pipeline", func() {
+			tool := newToolWithSteps(
+				arkv1alpha1.ToolOutputStep{Type: genai.PostProcessJQ, Expression: ".items"},
+				arkv1alpha1.ToolOutputStep{Type: genai.PostProcessTable, Format: genai.TableFormatCSV},
+				arkv1alpha1.ToolOutputStep{Type: genai.PostProcessRedact, Fields: []string{"password"}},
+			)
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject an invalid regular expression", func() {
+			tool := newToolWithSteps(arkv1alpha1.ToolOutputStep{Type: genai.PostProcessRegex, Expression: "("})
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("postProcess step 0: invalid regular expression"))
+		})
+
+		It("Should reject a template step without an expression", func() {
+			tool := newToolWithSteps(arkv1alpha1.ToolOutputStep{Type: genai.PostProcessTemplate})
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expression is required"))
+		})
+	})
+
+	Context("When validating reference cycles", func() {
+		It("Should reject a team tool used by a member of the team", func() {
+			tool := &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "ask-research-team", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeTeam,
+					Team: &arkv1alpha1.TeamToolRef{Name: "research-team"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("reference cycle detected: tool/ask-research-team -> team/research-team -> agent/planner -> tool/ask-research-team"))
+		})
+
+		It("Should allow a team tool that is not used by the team", func() {
+			tool := &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "consult-research-team", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeTeam,
+					Team: &arkv1alpha1.TeamToolRef{Name: "research-team"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
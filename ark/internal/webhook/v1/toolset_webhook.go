@@ -0,0 +1,113 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// SetupToolSetWebhookWithManager registers the webhook for ToolSet in the manager.
+func SetupToolSetWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.ToolSet{}).
+		WithValidator(&ToolSetCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-toolset,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=toolsets,verbs=create;update,versions=v1alpha1,name=vtoolset-v1.kb.io,admissionReviewVersions=v1
+
+type ToolSetCustomValidator struct {
+	*ResourceValidator
+}
+
+var _ webhook.CustomValidator = &ToolSetCustomValidator{}
+
+func (v *ToolSetCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	toolSet, ok := obj.(*arkv1alpha1.ToolSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ToolSet object but got %T", obj)
+	}
+
+	return v.validateToolSet(ctx, toolSet)
+}
+
+func (v *ToolSetCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	toolSet, ok := newObj.(*arkv1alpha1.ToolSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ToolSet object for the newObj but got %T", newObj)
+	}
+
+	return v.validateToolSet(ctx, toolSet)
+}
+
+func (v *ToolSetCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	_, ok := obj.(*arkv1alpha1.ToolSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ToolSet object but got %T", obj)
+	}
+
+	return nil, nil
+}
+
+func (v *ToolSetCustomValidator) validateToolSet(ctx context.Context, toolSet *arkv1alpha1.ToolSet) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	if len(toolSet.Spec.Tools) == 0 {
+		return warnings, fmt.Errorf("toolset must contain at least one tool")
+	}
+
+	// ToolSet entries share the agent tool validation rules
+	agentValidator := &AgentCustomValidator{ResourceValidator: v.ResourceValidator}
+	names := make(map[string]int)
+	for i, tool := range toolSet.Spec.Tools {
+		if tool.Type == "toolset" {
+			return warnings, fmt.Errorf("tool[%d]: toolsets cannot reference other toolsets", i)
+		}
+
+		toolWarnings, err := agentValidator.validateTool(i, tool)
+		if err != nil {
+			return warnings, err
+		}
+		warnings = append(warnings, toolWarnings...)
+
+		if tool.Name == "" {
+			continue
+		}
+		if previous, exists := names[tool.Name]; exists {
+			return warnings, fmt.Errorf("tool[%d]: tool name '%s' is already used by tool[%d]", i, tool.Name, previous)
+		}
+		names[tool.Name] = i
+	}
+
+	if err := v.validateDependentAgents(ctx, toolSet); err != nil {
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// validateDependentAgents checks that the toolset does not introduce tool name collisions
+// in agents that already reference it
+func (v *ToolSetCustomValidator) validateDependentAgents(ctx context.Context, toolSet *arkv1alpha1.ToolSet) error {
+	var agentList arkv1alpha1.AgentList
+	if err := v.Client.List(ctx, &agentList, client.InNamespace(toolSet.Namespace)); err != nil {
+		return fmt.Errorf("failed to list agents in namespace '%s': %v", toolSet.Namespace, err)
+	}
+
+	for i := range agentList.Items {
+		agent := &agentList.Items[i]
+		if err := v.validateToolNameCollisions(ctx, agent, toolSet); err != nil {
+			return fmt.Errorf("agent '%s': %v", agent.Name, err)
+		}
+	}
+
+	return nil
+}
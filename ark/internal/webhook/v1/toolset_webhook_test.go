@@ -0,0 +1,87 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("ToolSet Webhook", func() {
+	var (
+		ctx       context.Context
+		toolSet   *arkv1alpha1.ToolSet
+		agent     *arkv1alpha1.Agent
+		validator *ToolSetCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		agent = &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: arkv1alpha1.AgentSpec{
+				Prompt: "You are a test agent",
+				Tools: []arkv1alpha1.AgentTool{
+					{Type: "custom", Name: "search"},
+					{Type: "toolset", ToolSet: &arkv1alpha1.ToolSetRef{Name: "common"}},
+				},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(agent).Build()
+
+		validator = &ToolSetCustomValidator{
+			ResourceValidator: &ResourceValidator{Client: fakeClient},
+		}
+
+		toolSet = &arkv1alpha1.ToolSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "common", Namespace: "default"},
+			Spec: arkv1alpha1.ToolSetSpec{
+				Tools: []arkv1alpha1.AgentTool{
+					{Type: "custom", Name: "weather"},
+					{Type: "built-in", Name: "noop"},
+				},
+			},
+		}
+	})
+
+	It("Should allow a valid toolset", func() {
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should reject duplicate tool names", func() {
+		toolSet.Spec.Tools = append(toolSet.Spec.Tools, arkv1alpha1.AgentTool{Type: "custom", Name: "weather"})
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("already used by tool[0]"))
+	})
+
+	It("Should reject nested toolsets", func() {
+		toolSet.Spec.Tools = append(toolSet.Spec.Tools, arkv1alpha1.AgentTool{
+			Type:    "toolset",
+			ToolSet: &arkv1alpha1.ToolSetRef{Name: "other"},
+		})
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot reference other toolsets"))
+	})
+
+	It("Should reject updates that collide with tools of dependent agents", func() {
+		oldToolSet := toolSet.DeepCopy()
+		toolSet.Spec.Tools = append(toolSet.Spec.Tools, arkv1alpha1.AgentTool{Type: "custom", Name: "search"})
+		_, err := validator.ValidateUpdate(ctx, oldToolSet, toolSet)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("agent 'test-agent'"))
+	})
+})
@@ -6,5 +6,6 @@ export default {
   models: 'Models',
   query: 'Queries',
   team: 'Teams',
-  tools: 'Tools'
+  tools: 'Tools',
+  toolset: 'ToolSets'
 }
\ No newline at end of file